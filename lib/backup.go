@@ -0,0 +1,232 @@
+package lib
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// backupExclude lists the files in the config directory
+// which are not added to backups.
+var backupExclude = map[string]struct{}{
+	"socket":  {},
+	"control": {},
+}
+
+// The directories in a backup, into which the files from
+// the config and data directories are stored.
+const (
+	backupConfigDir = "config"
+	backupDataDir   = "data"
+)
+
+// Backup writes all files in the config and data directories, such as
+// the config, history, playback state and hooks, into a gzipped tar archive.
+func Backup(archive string) ([]string, error) {
+	var files []string
+
+	out, err := os.Create(archive)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create backup at %s", archive)
+	}
+	defer out.Close()
+
+	archivePath, _ := filepath.Abs(archive)
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	dirs := map[string]string{backupConfigDir: configPath}
+	if dataPath != configPath {
+		dirs[backupDataDir] = dataPath
+	}
+
+	for prefix, dir := range dirs {
+		err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := backupExclude[name]; ok || !info.Mode().IsRegular() || path == archivePath {
+				return nil
+			}
+
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = prefix + "/" + filepath.ToSlash(name)
+
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			if _, err := io.Copy(tw, file); err != nil {
+				return err
+			}
+
+			files = append(files, header.Name)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Restore extracts a backup created with Backup into the config and
+// data directories, replacing existing files.
+func Restore(archive string) ([]string, error) {
+	var files []string
+
+	if _, err := SendControl("status"); err == nil {
+		return nil, fmt.Errorf("Cannot restore while another instance is running")
+	}
+
+	in, err := os.Open(archive)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open backup at %s", archive)
+	}
+	defer in.Close()
+
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid backup", archive)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		path, ok := restorePath(header.Name)
+		if !ok {
+			return files, fmt.Errorf("Invalid file %s in backup", header.Name)
+		}
+		if path == "" {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return files, err
+		}
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return files, err
+		}
+
+		_, err = io.Copy(file, tr)
+		file.Close()
+		if err != nil {
+			return files, err
+		}
+
+		files = append(files, header.Name)
+	}
+
+	return files, nil
+}
+
+// restorePath returns the path into which the file with the given
+// name in a backup is restored, or an empty path if it is excluded.
+// Files from the data directory are restored into the data directory.
+// If the backup was created while the config and data directories were
+// the same, files which already exist in the data directory are restored
+// there, and the rest are moved into it by DataPath once they are used.
+func restorePath(name string) (string, bool) {
+	prefix := strings.SplitN(name, "/", 2)
+	if len(prefix) != 2 {
+		return "", false
+	}
+
+	file := filepath.Clean(filepath.FromSlash(prefix[1]))
+	if file == "." || filepath.IsAbs(file) || strings.HasPrefix(file, "..") {
+		return "", false
+	}
+
+	if _, ok := backupExclude[file]; ok {
+		return "", true
+	}
+
+	switch prefix[0] {
+	case backupDataDir:
+		return filepath.Join(dataPath, file), true
+
+	case backupConfigDir:
+		if dataPath != configPath {
+			if _, err := os.Stat(filepath.Join(dataPath, file)); err == nil {
+				return filepath.Join(dataPath, file), true
+			}
+		}
+
+		return filepath.Join(configPath, file), true
+	}
+
+	return "", false
+}
+
+// cliBackup creates a backup.
+func cliBackup(args []string) (interface{}, string, error) {
+	if len(args) != 1 {
+		return nil, "", fmt.Errorf("Usage: invidtui backup <file>")
+	}
+
+	files, err := Backup(args[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	return files, fmt.Sprintf("Backed up %d files to %s", len(files), args[0]), nil
+}
+
+// cliRestore restores a backup.
+func cliRestore(args []string) (interface{}, string, error) {
+	if len(args) != 1 {
+		return nil, "", fmt.Errorf("Usage: invidtui restore <file>")
+	}
+
+	files, err := Restore(args[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	return files, fmt.Sprintf("Restored %d files from %s", len(files), args[0]), nil
+}
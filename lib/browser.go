@@ -0,0 +1,110 @@
+package lib
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+)
+
+// browserData stores the directory bookmarks of the file browser,
+// and the last directory used in each of its contexts.
+type browserData struct {
+	Bookmarks []string          `json:"bookmarks"`
+	LastDirs  map[string]string `json:"lastDirs"`
+}
+
+var (
+	browser = browserData{
+		LastDirs: make(map[string]string),
+	}
+	browserLock sync.Mutex
+)
+
+// SetupBrowser loads the file browser's bookmarks and last used directories.
+func SetupBrowser() {
+	var data browserData
+
+	browserfile, err := DataPath("browser.json")
+	if err != nil {
+		return
+	}
+
+	content, err := ioutil.ReadFile(browserfile)
+	if err != nil || len(content) == 0 {
+		return
+	}
+
+	if err := json.Unmarshal(content, &data); err != nil {
+		return
+	}
+
+	browserLock.Lock()
+	defer browserLock.Unlock()
+
+	browser.Bookmarks = data.Bookmarks
+	if data.LastDirs != nil {
+		browser.LastDirs = data.LastDirs
+	}
+}
+
+// SaveBrowser saves the file browser's bookmarks and last used directories.
+func SaveBrowser() {
+	browserLock.Lock()
+	defer browserLock.Unlock()
+
+	browserfile, err := DataPath("browser.json")
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(browser, "", " ")
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(browserfile, data, 0664)
+}
+
+// Bookmarks returns the bookmarked directories.
+func Bookmarks() []string {
+	browserLock.Lock()
+	defer browserLock.Unlock()
+
+	return append([]string{}, browser.Bookmarks...)
+}
+
+// ToggleBookmark bookmarks the directory, or removes its bookmark if
+// it is already bookmarked. It returns whether the directory is bookmarked.
+func ToggleBookmark(dir string) bool {
+	browserLock.Lock()
+	defer browserLock.Unlock()
+
+	for i, bookmark := range browser.Bookmarks {
+		if bookmark == dir {
+			browser.Bookmarks = append(browser.Bookmarks[:i], browser.Bookmarks[i+1:]...)
+			return false
+		}
+	}
+
+	browser.Bookmarks = append(browser.Bookmarks, dir)
+
+	return true
+}
+
+// LastDir returns the directory which was last used in the file browser
+// for the given context, for example "playlist" or "audio-downloads".
+func LastDir(context string) string {
+	browserLock.Lock()
+	defer browserLock.Unlock()
+
+	return browser.LastDirs[context]
+}
+
+// SetLastDir stores the directory which was last used in the file
+// browser for the given context.
+func SetLastDir(context, dir string) {
+	browserLock.Lock()
+	defer browserLock.Unlock()
+
+	browser.LastDirs[context] = dir
+}
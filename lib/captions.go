@@ -0,0 +1,48 @@
+package lib
+
+import (
+	"strings"
+)
+
+// CaptionData stores the caption data of a video.
+type CaptionData struct {
+	Label        string `json:"label"`
+	LanguageCode string `json:"languageCode"`
+	URL          string `json:"url"`
+}
+
+// preferredCaption returns the URL of the caption in the first language from
+// the --captions setting that the video has captions for. Captions written by
+// the uploader are preferred over auto-generated ones, and a language such as
+// "en" also matches regional variants such as "en-US".
+func preferredCaption(video VideoResult) string {
+	if captionLangs == "" {
+		return ""
+	}
+
+	for _, lang := range strings.Split(captionLangs, ",") {
+		var selected CaptionData
+
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" {
+			continue
+		}
+
+		for _, caption := range video.Captions {
+			code := strings.ToLower(caption.LanguageCode)
+			if code != lang && !strings.HasPrefix(code, lang+"-") {
+				continue
+			}
+
+			if selected.URL == "" || strings.Contains(selected.Label, "auto-generated") {
+				selected = caption
+			}
+		}
+
+		if selected.URL != "" {
+			return videoHost(video) + selected.URL
+		}
+	}
+
+	return ""
+}
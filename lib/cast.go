@@ -0,0 +1,310 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CastDevice stores the data of a DLNA media renderer.
+type CastDevice struct {
+	Name       string
+	Location   string
+	ControlURL string
+}
+
+// castDescription stores the relevant parts of a UPnP device description.
+type castDescription struct {
+	URLBase string `xml:"URLBase"`
+	Device  struct {
+		FriendlyName string `xml:"friendlyName"`
+		Services     []struct {
+			ServiceType string `xml:"serviceType"`
+			ControlURL  string `xml:"controlURL"`
+		} `xml:"serviceList>service"`
+		Devices []struct {
+			Services []struct {
+				ServiceType string `xml:"serviceType"`
+				ControlURL  string `xml:"controlURL"`
+			} `xml:"serviceList>service"`
+		} `xml:"deviceList>device"`
+	} `xml:"device"`
+}
+
+var (
+	castDevice *CastDevice
+	castTitle  string
+	castLock   sync.Mutex
+)
+
+const (
+	ssdpAddr    = "239.255.255.250:1900"
+	avTransport = "urn:schemas-upnp-org:service:AVTransport:1"
+)
+
+// DiscoverCastDevices searches the local network for DLNA media renderers.
+// Chromecast devices are not supported.
+func DiscoverCastDevices(timeout time.Duration) ([]CastDevice, error) {
+	var devices []CastDevice
+
+	addr, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot search for DLNA renderers")
+	}
+	defer conn.Close()
+
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddr + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: " + avTransport + "\r\n\r\n"
+
+	_, err = conn.WriteTo([]byte(search), addr)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot search for DLNA renderers")
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+
+	locations := make(map[string]struct{})
+	buf := make([]byte, 2048)
+
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		res.Body.Close()
+
+		location := res.Header.Get("Location")
+		if _, ok := locations[location]; ok || location == "" {
+			continue
+		}
+		locations[location] = struct{}{}
+
+		device, err := castDeviceInfo(location)
+		if err != nil {
+			continue
+		}
+
+		devices = append(devices, device)
+	}
+
+	if devices == nil {
+		return nil, fmt.Errorf("No DLNA renderers found (Chromecast devices are not supported)")
+	}
+
+	return devices, nil
+}
+
+// CastVideo loads the video with the given ID on the device, and starts playback.
+func CastVideo(device CastDevice, id string, audio bool) (string, error) {
+	JobReset("video")
+
+	video, err := GetClient().Video(id)
+	if err != nil {
+		return "", err
+	}
+
+	if video.LiveNow {
+		return "", fmt.Errorf("Cannot cast live video")
+	}
+
+	format, ok := DownloadFormat(video, audio)
+	if !ok {
+		return "", fmt.Errorf("Could not find a stream to cast")
+	}
+
+	// The format's URL is bound to the address of the instance's client and
+	// expires, so the device loads the stream through the instance instead.
+	streamUrl := getLatestURL(videoHost(video), video.VideoID, format.Itag)
+
+	class := "object.item.videoItem"
+	if audio {
+		class = "object.item.audioItem"
+	}
+
+	metadata := `<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" ` +
+		`xmlns:dc="http://purl.org/dc/elements/1.1/" ` +
+		`xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">` +
+		`<item id="0" parentID="-1" restricted="1">` +
+		`<dc:title>` + html.EscapeString(video.Title) + `</dc:title>` +
+		`<dc:creator>` + html.EscapeString(video.Author) + `</dc:creator>` +
+		`<upnp:class>` + class + `</upnp:class>` +
+		`<res protocolInfo="http-get:*:` + strings.Split(format.Type, ";")[0] + `:*">` +
+		html.EscapeString(streamUrl) + `</res></item></DIDL-Lite>`
+
+	err = device.action(
+		"SetAVTransportURI",
+		"<CurrentURI>"+html.EscapeString(streamUrl)+"</CurrentURI>"+
+			"<CurrentURIMetaData>"+html.EscapeString(metadata)+"</CurrentURIMetaData>",
+	)
+	if err != nil {
+		return "", err
+	}
+
+	err = device.action("Play", "<Speed>1</Speed>")
+	if err != nil {
+		return "", err
+	}
+
+	castLock.Lock()
+	castDevice = &device
+	castTitle = video.Title
+	castLock.Unlock()
+
+	return video.Title, nil
+}
+
+// CastingTo returns the device that is being cast to, and the title of the
+// video that was cast. If nothing is being cast, the device is nil.
+func CastingTo() (*CastDevice, string) {
+	castLock.Lock()
+	defer castLock.Unlock()
+
+	return castDevice, castTitle
+}
+
+// CastPause pauses or resumes playback on the device being cast to.
+func CastPause(pause bool) error {
+	device, _ := CastingTo()
+	if device == nil {
+		return fmt.Errorf("Nothing is being cast")
+	}
+
+	if pause {
+		return device.action("Pause", "")
+	}
+
+	return device.action("Play", "<Speed>1</Speed>")
+}
+
+// CastStop stops playback on the device being cast to.
+func CastStop() error {
+	device, _ := CastingTo()
+	if device == nil {
+		return nil
+	}
+
+	castLock.Lock()
+	castDevice = nil
+	castTitle = ""
+	castLock.Unlock()
+
+	return device.action("Stop", "")
+}
+
+// castDeviceInfo fetches the device description from the location
+// and returns the device's name and AVTransport control URL.
+func castDeviceInfo(location string) (CastDevice, error) {
+	var desc castDescription
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return CastDevice{}, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return CastDevice{}, err
+	}
+	defer res.Body.Close()
+
+	err = xml.NewDecoder(res.Body).Decode(&desc)
+	if err != nil {
+		return CastDevice{}, err
+	}
+
+	services := desc.Device.Services
+	for _, device := range desc.Device.Devices {
+		services = append(services, device.Services...)
+	}
+
+	base := location
+	if desc.URLBase != "" {
+		base = desc.URLBase
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return CastDevice{}, err
+	}
+
+	for _, service := range services {
+		if !strings.HasPrefix(service.ServiceType, "urn:schemas-upnp-org:service:AVTransport:") {
+			continue
+		}
+
+		control, err := baseURL.Parse(service.ControlURL)
+		if err != nil {
+			return CastDevice{}, err
+		}
+
+		name := desc.Device.FriendlyName
+		if name == "" {
+			name = baseURL.Host
+		}
+
+		return CastDevice{
+			Name:       name,
+			Location:   location,
+			ControlURL: control.String(),
+		}, nil
+	}
+
+	return CastDevice{}, fmt.Errorf("%s does not support AVTransport", location)
+}
+
+// action sends an AVTransport action to the device.
+func (d CastDevice) action(name, args string) error {
+	body := `<?xml version="1.0" encoding="utf-8"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>` +
+		`<u:` + name + ` xmlns:u="` + avTransport + `"><InstanceID>0</InstanceID>` +
+		args + `</u:` + name + `></s:Body></s:Envelope>`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.ControlURL, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", `"`+avTransport+`#`+name+`"`)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Cannot connect to %s", d.Name)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s failed (%s)", d.Name, name, res.Status)
+	}
+
+	return nil
+}
@@ -3,7 +3,9 @@ package lib
 import (
 	"context"
 	"encoding/json"
+	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -12,13 +14,19 @@ type ChannelResult struct {
 	Title       string           `json:"title"`
 	ChannelID   string           `json:"authorId"`
 	Author      string           `json:"author"`
+	AuthorURL   string           `json:"authorUrl"`
 	Description string           `json:"description"`
 	ViewCount   int64            `json:"viewCount"`
+	TotalViews  int64            `json:"totalViews"`
+	SubCount    int              `json:"subCount"`
+	Joined      int64            `json:"joined"`
 	Videos      []PlaylistVideo  `json:"videos"`
 	Playlists   []PlaylistResult `json:"playlists"`
 }
 
 var (
+	linkRegex = regexp.MustCompile(`https?://[^\s]+`)
+
 	chanpage  int
 	chanspage int
 	chanid    string
@@ -26,7 +34,10 @@ var (
 	chanMutex sync.Mutex
 )
 
-const channelFields = "?fields=title,authorId,author,description,viewCount&hl=en"
+const (
+	channelFields = "?fields=title,authorId,author,description,viewCount&hl=en"
+	aboutFields   = "?fields=authorId,author,authorUrl,description,totalViews,subCount,joined&hl=en"
+)
 
 // Channel gets the playlist with the given ID and returns a ChannelResult.
 // If id is blank, it indicates that more results are to be loaded for the
@@ -39,7 +50,8 @@ func (c *Client) Channel(id, stype, params string) (ChannelResult, error) {
 		return ChannelResult{}, nil
 	}
 
-	channelCancel()
+	_, done := JobStart("channel")
+	defer done()
 
 	chantype = stype
 
@@ -65,10 +77,10 @@ func (c *Client) Channel(id, stype, params string) (ChannelResult, error) {
 
 	switch chantype {
 	case "videos":
-		result.Videos = append(result.Videos, res.([]PlaylistVideo)...)
+		result.Videos = append(result.Videos, filterRestrictedVideos(ChannelCtx(), res.([]PlaylistVideo))...)
 
 	case "playlists":
-		result.Playlists = append(result.Playlists, res.([]PlaylistResult)...)
+		result.Playlists = append(result.Playlists, filterRestrictedPlaylists(res.([]PlaylistResult))...)
 	}
 
 	return result, nil
@@ -108,18 +120,23 @@ func (c *Client) chandecode(query, dectype string) (interface{}, error) {
 }
 
 // ChannelVideos loads only the videos present in the channel.
+// The videos of podcast channels are loaded oldest first.
 func (c *Client) ChannelVideos(id string) (ChannelResult, error) {
+	cid := id
+
 	if id == "" {
+		cid = chanid
 		incChanPage(false)
 	} else {
 		setChanPage(1, false)
 	}
 
-	return c.Channel(
-		id,
-		"videos",
-		videoFields+"&page="+strconv.Itoa(getChanPage(false)),
-	)
+	params := videoFields + "&page=" + strconv.Itoa(getChanPage(false))
+	if IsPodcast(cid) {
+		params += "&sort_by=oldest"
+	}
+
+	return c.Channel(id, "videos", params)
 }
 
 // ChannelPlaylists loads only the playlists present in the channel.
@@ -132,14 +149,30 @@ func (c *Client) ChannelSearch(id, query string, getmore bool) ([]SearchResult,
 	return c.Search("channel", query, getmore, id)
 }
 
-// ChannelCtx returns the channel's context.
-func ChannelCtx() context.Context {
-	return ClientCtx()
+// ChannelAbout gets the information about the channel.
+func (c *Client) ChannelAbout(id string) (ChannelResult, error) {
+	res, err := c.chandecode("channels/"+id+aboutFields, "channels")
+	if err != nil {
+		return ChannelResult{}, err
+	}
+
+	return res.(ChannelResult), nil
 }
 
-// channelCancel cancels and renews the channel's context.
-func channelCancel() {
-	ClientCancel()
+// ChannelLinks returns the links present in the channel's description.
+func ChannelLinks(description string) []string {
+	var links []string
+
+	for _, link := range linkRegex.FindAllString(description, -1) {
+		links = append(links, strings.TrimRight(link, ".,;:!?)"))
+	}
+
+	return links
+}
+
+// ChannelCtx returns the channel's context.
+func ChannelCtx() context.Context {
+	return JobCtx("channel")
 }
 
 func getChanPage(search bool) int {
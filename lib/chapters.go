@@ -0,0 +1,219 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Chapter stores the title and the start time of a chapter.
+type Chapter struct {
+	Title string  `json:"title"`
+	Start float64 `json:"time"`
+}
+
+// chapterRestart is the time in seconds into a chapter, after which
+// PrevChapter seeks to the start of the current chapter instead of
+// the previous one.
+const chapterRestart = 3
+
+var (
+	chapterRegex = regexp.MustCompile(`(?:^|\s|\()((?:\d{1,2}:)?\d{1,2}:\d{2})(?:$|\s|\))`)
+
+	// videoChapters stores the chapters parsed from the description of
+	// the video with the ID chaptersVideoID. Only the chapters of the last
+	// video whose chapters were requested are kept, and if its description
+	// could not be fetched, no chapters are stored for it.
+	videoChapters   []Chapter
+	chaptersVideoID string
+	chaptersLock    sync.Mutex
+)
+
+// ChapterList returns the chapters of the playing file. The chapters
+// from mpv are returned if the file has any, otherwise the description
+// of the playing video is fetched, and the chapters parsed from it are
+// returned.
+func (c *Connector) ChapterList() []Chapter {
+	var chapters []Chapter
+
+	if list, err := c.Call("get_property_string", "chapter-list"); err == nil && list != nil {
+		if json.Unmarshal([]byte(list.(string)), &chapters) == nil && len(chapters) > 0 {
+			return chapters
+		}
+	}
+
+	id := c.PlayingVideoID()
+	if id == "" {
+		return nil
+	}
+
+	// The description is fetched without holding the lock, so that
+	// a slow request does not block the other chapter lookups.
+	chaptersLock.Lock()
+	cached, ok := videoChapters, id == chaptersVideoID
+	chaptersLock.Unlock()
+	if ok {
+		return cached
+	}
+
+	JobReset("chapters")
+	ctx, done := JobRun("chapters")
+
+	description, length, err := GetClient().videoDescription(ctx, id)
+	done()
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if err != nil {
+		logWarn("cannot get the video description for chapters", "id", id, "error", err)
+		description = ""
+	}
+
+	chaptersLock.Lock()
+	defer chaptersLock.Unlock()
+
+	videoChapters = parseChapters(description, length)
+	chaptersVideoID = id
+
+	return videoChapters
+}
+
+// CurrentChapter returns the position of the chapter which
+// contains the given time, or -1 if there are no chapters.
+func CurrentChapter(chapters []Chapter, time int64) int {
+	current := -1
+
+	for i, chapter := range chapters {
+		if chapter.Start > float64(time) {
+			break
+		}
+
+		current = i
+	}
+
+	return current
+}
+
+// SeekChapter seeks to the start of the chapter.
+func (c *Connector) SeekChapter(chapter Chapter) error {
+	return c.Set("time-pos", chapter.Start)
+}
+
+// NextChapter seeks to the start of the next chapter.
+func (c *Connector) NextChapter() error {
+	chapters := c.ChapterList()
+	if len(chapters) == 0 {
+		return fmt.Errorf("No chapters found")
+	}
+
+	next := CurrentChapter(chapters, c.TimePosition()) + 1
+	if next >= len(chapters) {
+		return fmt.Errorf("No next chapter")
+	}
+
+	return c.SeekChapter(chapters[next])
+}
+
+// PrevChapter seeks to the start of the previous chapter, or to the start
+// of the current chapter if it has been playing for a few seconds.
+func (c *Connector) PrevChapter() error {
+	chapters := c.ChapterList()
+	if len(chapters) == 0 {
+		return fmt.Errorf("No chapters found")
+	}
+
+	time := c.TimePosition()
+
+	prev := CurrentChapter(chapters, time)
+	if prev >= 0 && float64(time)-chapters[prev].Start < chapterRestart {
+		prev--
+	}
+	if prev < 0 {
+		prev = 0
+	}
+
+	return c.SeekChapter(chapters[prev])
+}
+
+// videoDescription gets the description and the length of the video.
+func (c *Client) videoDescription(ctx context.Context, id string) (string, int64, error) {
+	var result struct {
+		Description   string `json:"description"`
+		LengthSeconds int64  `json:"lengthSeconds"`
+	}
+
+	res, err := c.ClientRequest(ctx, "videos/"+id+"?fields=description,lengthSeconds&hl=en")
+	if err != nil {
+		return "", 0, err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return "", 0, err
+	}
+
+	return result.Description, result.LengthSeconds, nil
+}
+
+// parseChapters parses the chapters from the timestamps in a video
+// description, one chapter per line. Like on Youtube, the first
+// chapter must start at 0:00, and there must be at least two chapters
+// in ascending order, otherwise no chapters are returned.
+func parseChapters(description string, length int64) []Chapter {
+	var chapters []Chapter
+
+	for _, line := range strings.Split(description, "\n") {
+		match := chapterRegex.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+
+		start := parseTimestamp(line[match[2]:match[3]])
+		if start < 0 || (length > 0 && start >= length) {
+			continue
+		}
+
+		if len(chapters) > 0 && float64(start) <= chapters[len(chapters)-1].Start {
+			continue
+		}
+
+		title := strings.TrimSpace(line[:match[2]] + " " + line[match[3]:])
+		title = strings.Trim(title, " -–—:|()[]")
+		if title == "" {
+			title = "Chapter " + strconv.Itoa(len(chapters)+1)
+		}
+
+		chapters = append(chapters, Chapter{
+			Title: title,
+			Start: float64(start),
+		})
+	}
+
+	if len(chapters) < 2 || chapters[0].Start != 0 {
+		return nil
+	}
+
+	return chapters
+}
+
+// parseTimestamp returns the time in seconds of a timestamp
+// like 1:02:03 or 02:03, or -1 if it is invalid.
+func parseTimestamp(timestamp string) int64 {
+	var seconds int64
+
+	for _, field := range strings.Split(timestamp, ":") {
+		value, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return -1
+		}
+
+		seconds = seconds*60 + value
+	}
+
+	return seconds
+}
@@ -0,0 +1,279 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cliCommand stores a command-line subcommand.
+type cliCommand struct {
+	usage   string
+	offline bool
+	run     func(args []string) (interface{}, string, error)
+}
+
+var cliCommands = map[string]cliCommand{
+	"search": {
+		usage: "search <query>\tSearch for videos, playlists or channels (see --search-type)",
+		run:   cliSearch,
+	},
+	"resolve": {
+		usage: "resolve <url>\tShow information about a video or playlist URL or ID",
+		run:   cliResolve,
+	},
+	"download": {
+		usage: "download <url>\tDownload a video in the resolution set by --video-res",
+		run:   cliDownload("download", false),
+	},
+	"download-audio": {
+		usage: "download-audio <url>\tDownload the audio of a video",
+		run:   cliDownload("download-audio", true),
+	},
+	"export-history": {
+		usage:   "export-history\tPrint the watch history as CSV",
+		offline: true,
+		run:     cliExportHistory,
+	},
+	"backup": {
+		usage:   "backup <file>\tSave the config, history and playback state to an archive",
+		offline: true,
+		run:     cliBackup,
+	},
+	"restore": {
+		usage:   "restore <file>\tRestore the config, history and playback state from an archive",
+		offline: true,
+		run:     cliRestore,
+	},
+	"stats": {
+		usage:   "stats\tPrint the total playback time, and the playback time per channel, video and day as CSV",
+		offline: true,
+		run:     cliStats,
+	},
+}
+
+// IsCommand returns whether a subcommand was specified on the command-line.
+func IsCommand() bool {
+	return len(cliArgs) > 0
+}
+
+// RunCommand runs the subcommand specified on the command-line, and
+// returns its output, in JSON if the --json option is set.
+func RunCommand() (string, error) {
+	command, ok := cliCommands[cliArgs[0]]
+	if !ok {
+		return "", fmt.Errorf("%s is not a valid command", cliArgs[0])
+	}
+
+	if !command.offline {
+		if err := UpdateClient(); err != nil {
+			return "", err
+		}
+	}
+
+	data, text, err := command.run(cliArgs[1:])
+	if err != nil {
+		return "", err
+	}
+
+	if !jsonOutput {
+		return text, nil
+	}
+
+	output, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+// commandUsage returns the usage text for the subcommands.
+func commandUsage() string {
+	var usage []string
+
+	for _, command := range cliCommands {
+		usage = append(usage, "  "+strings.Replace(command.usage, "\t", "\n    \t", 1))
+	}
+	sort.Strings(usage)
+
+	return strings.Join(usage, "\n")
+}
+
+// cliSearch searches for the query and lists the results.
+func cliSearch(args []string) (interface{}, string, error) {
+	var lines []string
+
+	if len(args) == 0 {
+		return nil, "", fmt.Errorf("No search query specified")
+	}
+
+	results, err := GetClient().Search(searchType, strings.Join(args, " "), false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, result := range results {
+		var id string
+
+		switch result.Type {
+		case "video":
+			id = result.VideoID
+
+		case "playlist":
+			id = result.PlaylistID
+
+		case "channel":
+			id = result.AuthorID
+		}
+
+		lines = append(lines, strings.Join([]string{
+			result.Type, id, result.Title, result.Author,
+		}, "\t"))
+	}
+
+	return results, strings.Join(lines, "\n"), nil
+}
+
+// cliExportHistory exports the watch history.
+func cliExportHistory(args []string) (interface{}, string, error) {
+	var text strings.Builder
+
+	SetupWatchLog()
+
+	if err := ExportWatchLog(&text); err != nil {
+		return nil, "", err
+	}
+
+	return WatchLog(), strings.TrimSpace(text.String()), nil
+}
+
+// cliStats exports the playback statistics.
+func cliStats(args []string) (interface{}, string, error) {
+	var text strings.Builder
+
+	SetupWatchLog()
+	stats := GetWatchStats()
+
+	if err := ExportWatchStats(&text, stats); err != nil {
+		return nil, "", err
+	}
+
+	return stats, strings.TrimSpace(text.String()), nil
+}
+
+// cliResolve gets the video or playlist information for a URL or ID.
+func cliResolve(args []string) (interface{}, string, error) {
+	if len(args) != 1 {
+		return nil, "", fmt.Errorf("Usage: invidtui resolve <url>")
+	}
+
+	id, mtype, err := GetVPIDFromURL(args[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	if mtype == "playlist" {
+		playlist, err := GetClient().Playlist(id, false)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return playlist, fmt.Sprintf(
+			"Type: playlist\nID: %s\nTitle: %s\nAuthor: %s\nVideos: %d",
+			playlist.PlaylistID, playlist.Title, playlist.Author, playlist.VideoCount,
+		), nil
+	}
+
+	JobReset("video")
+
+	video, err := GetClient().Video(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	length := FormatDuration(video.LengthSeconds)
+	if video.LiveNow {
+		length = "Live"
+	}
+
+	return video, fmt.Sprintf(
+		"Type: video\nID: %s\nTitle: %s\nAuthor: %s\nLength: %s",
+		video.VideoID, video.Title, video.Author, length,
+	), nil
+}
+
+// cliDownload returns a command which downloads a video, or only its
+// audio if audio is set, to the download directory.
+func cliDownload(name string, audio bool) func(args []string) (interface{}, string, error) {
+	return func(args []string) (interface{}, string, error) {
+		if len(args) != 1 {
+			return nil, "", fmt.Errorf("Usage: invidtui %s <url>", name)
+		}
+
+		id, mtype, err := GetVPIDFromURL(args[0])
+		if err != nil {
+			return nil, "", err
+		}
+		if mtype != "video" {
+			return nil, "", fmt.Errorf("Only videos can be downloaded")
+		}
+
+		JobReset("video")
+
+		video, err := GetClient().Video(id)
+		if err != nil {
+			return nil, "", err
+		}
+		if video.LiveNow {
+			return nil, "", fmt.Errorf("Cannot download live video")
+		}
+
+		format, ok := DownloadFormat(video, audio)
+		if !ok {
+			return nil, "", fmt.Errorf("Could not find a format to download")
+		}
+
+		filename := DownloadPath(audio, video.Title, video.Author, video.VideoID, format.Container)
+
+		res, file, err := GetDownload(video.VideoID, format.Itag, filename, context.Background())
+		if err != nil {
+			return nil, "", err
+		}
+		defer res.Body.Close()
+		defer file.Close()
+
+		DownloadStarted()
+
+		size, err := io.Copy(file, res.Body)
+		DownloadFinished(size, err)
+		if err != nil {
+			return nil, "", err
+		}
+
+		data := map[string]interface{}{
+			"videoId": video.VideoID,
+			"title":   video.Title,
+			"itag":    format.Itag,
+			"file":    file.Name(),
+			"size":    size,
+		}
+
+		return data, "Downloaded " + file.Name() + " (" + strconv.FormatInt(size, 10) + " bytes)", nil
+	}
+}
+
+// DownloadFormat selects the format to download. For audio, the audio format
+// closest to the --audio-bitrate setting is selected, otherwise the combined
+// audio and video format closest to the --video-res setting.
+func DownloadFormat(video VideoResult, audio bool) (FormatData, bool) {
+	if audio {
+		return preferredAudio(video.AdaptiveFormats)
+	}
+
+	return preferredVideo(video.FormatStreams)
+}
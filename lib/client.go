@@ -25,14 +25,12 @@ const instanceApi = "https://api.invidious.io/instances.json?sort_by=api,health"
 const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/74.0.3729.169 Safari/537.36"
 
 var (
-	clientCtx     context.Context
-	clientCancel  context.CancelFunc
-	cliSendCtx    context.Context
-	cliSendCancel context.CancelFunc
-
 	currentClient *Client
 
 	clientLock sync.Mutex
+
+	transport     http.RoundTripper
+	transportOnce sync.Once
 )
 
 // NewClient creates a new client.
@@ -40,11 +38,37 @@ func NewClient(host string) *Client {
 	return &Client{
 		host: host,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   time.Duration(requestTimeout) * time.Second,
+			Transport: clientTransport(),
 		},
 	}
 }
 
+// clientTransport returns the transport for requests, which is shared
+// by all clients so that connections to instances are reused. It uses
+// the proxy set with --proxy.
+func clientTransport() http.RoundTripper {
+	transportOnce.Do(func() {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.ForceAttemptHTTP2 = true
+		t.MaxIdleConns = 100
+		t.MaxIdleConnsPerHost = maxIdleConns
+		t.IdleConnTimeout = 90 * time.Second
+		t.TLSHandshakeTimeout = 10 * time.Second
+		t.ResponseHeaderTimeout = time.Duration(requestTimeout) * time.Second
+
+		if proxyURL != "" {
+			if proxy, err := url.Parse(proxyURL); err == nil {
+				t.Proxy = http.ProxyURL(proxy)
+			}
+		}
+
+		transport = t
+	})
+
+	return transport
+}
+
 // UpdateClient queries available instances and updates the client.
 func UpdateClient() error {
 	if currentClient != nil {
@@ -101,11 +125,28 @@ func (c *Client) SetRequest(ctx context.Context, method, param string, body io.R
 		}
 	}
 
+	start := time.Now()
+
 	res, err := c.client.Do(req)
 	if err != nil {
+		if ctx.Err() == nil {
+			setOnline(false)
+			recordRequest(GetHostname(c.host), time.Since(start), true)
+		}
+
+		logWarn("request failed", "method", method, "url", c.host+param, "error", err, "duration", time.Since(start))
+
 		return nil, clientError(err)
 	}
 
+	setOnline(true)
+	recordRequest(GetHostname(c.host), time.Since(start), res.StatusCode >= http.StatusBadRequest)
+	if res.StatusCode >= http.StatusBadRequest {
+		logWarn("request failed", "method", method, "url", c.host+param, "status", res.StatusCode, "duration", time.Since(start))
+	} else {
+		logDebug("request", "method", method, "url", c.host+param, "status", res.StatusCode, "duration", time.Since(start))
+	}
+
 	return res, nil
 }
 
@@ -117,7 +158,7 @@ func (c *Client) GetRequest(ctx context.Context, param string, token ...string)
 	}
 
 	if res.StatusCode == http.StatusNotFound || res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP request returned %d", res.StatusCode)
+		return nil, apiError(res)
 	}
 
 	return res, err
@@ -172,23 +213,26 @@ func (c *Client) ClientRequest(ctx context.Context, param string, token ...strin
 
 // ClientSend sends a POST request to the API and returns a response.
 func (c *Client) ClientSend(param, body string, token ...string) (*http.Response, error) {
-	ClientSendCancel()
+	ctx, done := JobStart("send")
+	defer done()
 
-	return c.PostRequest(ClientSendCtx(), api+param, body, token...)
+	return c.PostRequest(ctx, api+param, body, token...)
 }
 
 // ClientDelete sends a DELETE request to the API and returns a response.
 func (c *Client) ClientDelete(param string, token ...string) (*http.Response, error) {
-	ClientSendCancel()
+	ctx, done := JobStart("send")
+	defer done()
 
-	return c.DeleteRequest(ClientSendCtx(), api+param, token...)
+	return c.DeleteRequest(ctx, api+param, token...)
 }
 
 // ClientPatch sends a PATCH request to the API and returns a response.
 func (c *Client) ClientPatch(param, body string, token ...string) (*http.Response, error) {
-	ClientSendCancel()
+	ctx, done := JobStart("send")
+	defer done()
 
-	return c.PatchRequest(ClientSendCtx(), api+param, body, token...)
+	return c.PatchRequest(ctx, api+param, body, token...)
 }
 
 // SelectedInstance returns the current client's hostname.
@@ -196,34 +240,9 @@ func (c *Client) SelectedInstance() string {
 	return GetHostname(c.host)
 }
 
-// ClientCtx returns the client's context.
+// ClientCtx renews and returns the client's context.
 func ClientCtx() context.Context {
-	ClientCancel()
-
-	return clientCtx
-}
-
-// ClientCancel cancels and renews the client context.
-func ClientCancel() {
-	if clientCtx != nil {
-		clientCancel()
-	}
-
-	clientCtx, clientCancel = context.WithCancel(context.Background())
-}
-
-// ClientSendCtx returns the client's send context.
-func ClientSendCtx() context.Context {
-	return cliSendCtx
-}
-
-// ClientSendCancel cancels and renews the client send context.
-func ClientSendCancel() {
-	if cliSendCtx != nil {
-		cliSendCancel()
-	}
-
-	cliSendCtx, cliSendCancel = context.WithCancel(context.Background())
+	return JobRenew("client")
 }
 
 // CheckInstance checks if an instance is functional.
@@ -318,6 +337,23 @@ func queryInstances() (*Client, error) {
 	return NewClient(bestInstance), nil
 }
 
+// apiError returns the error message sent by the instance for a failed
+// request, or the status code if the response has no error message.
+func apiError(res *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+
+	defer res.Body.Close()
+
+	err := json.NewDecoder(io.LimitReader(res.Body, 1<<16)).Decode(&body)
+	if err != nil || body.Error == "" {
+		return fmt.Errorf("HTTP request returned %d", res.StatusCode)
+	}
+
+	return fmt.Errorf("%s", strings.TrimSpace(strings.Split(body.Error, "\n")[0]))
+}
+
 // clientError returns a suitable error message for common http errors.
 func clientError(err error) error {
 	if err, ok := err.(net.Error); ok {
@@ -0,0 +1,178 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clipboardTool describes the commands of a clipboard utility
+// which print the clipboard contents, and set them from the input.
+type clipboardTool struct {
+	paste, copy []string
+}
+
+// clipboardTools lists the clipboard utilities, in order
+// of preference, for each platform.
+var clipboardTools = map[string][]clipboardTool{
+	"linux": {
+		{
+			paste: []string{"wl-paste", "--no-newline"},
+			copy:  []string{"wl-copy"},
+		},
+		{
+			paste: []string{"xclip", "-out", "-selection", "clipboard"},
+			copy:  []string{"xclip", "-in", "-selection", "clipboard"},
+		},
+		{
+			paste: []string{"xsel", "--output", "--clipboard"},
+			copy:  []string{"xsel", "--input", "--clipboard"},
+		},
+	},
+	"darwin": {
+		{
+			paste: []string{"pbpaste"},
+			copy:  []string{"pbcopy"},
+		},
+	},
+	"windows": {
+		{
+			paste: []string{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard"},
+			copy:  []string{"clip.exe"},
+		},
+	},
+}
+
+var (
+	// copiedText is the text which was last copied by CopyToClipboard,
+	// which the clipboard watcher ignores.
+	copiedText string
+	copiedLock sync.Mutex
+)
+
+// WatchClipboardURLs checks the clipboard every second, and calls onURL
+// with each new YouTube or Invidious video or playlist URL which is copied.
+// The contents of the clipboard when the watcher starts, and the text
+// copied by invidtui itself, are ignored.
+func WatchClipboardURLs(onURL func(uri string)) error {
+	tool, err := clipboardUtility()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		last, _ := readClipboard(tool.paste)
+
+		for range ticker.C {
+			text, err := readClipboard(tool.paste)
+			if err != nil || text == last {
+				continue
+			}
+
+			last = text
+
+			if isCopiedText(text) {
+				continue
+			}
+
+			if uri, ok := clipboardMediaURL(text); ok {
+				logInfo("queueing URL from the clipboard", "url", uri)
+				onURL(uri)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// CopyToClipboard sets the clipboard contents to text.
+func CopyToClipboard(text string) error {
+	tool, err := clipboardUtility()
+	if err != nil {
+		return err
+	}
+
+	copiedLock.Lock()
+	copiedText = strings.TrimSpace(text)
+	copiedLock.Unlock()
+
+	cmd := exec.Command(tool.copy[0], tool.copy[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Could not copy to the clipboard: %s", err.Error())
+	}
+
+	return nil
+}
+
+// isCopiedText returns whether the text was copied by CopyToClipboard.
+func isCopiedText(text string) bool {
+	copiedLock.Lock()
+	defer copiedLock.Unlock()
+
+	return copiedText != "" && text == copiedText
+}
+
+// clipboardUtility returns the first available clipboard utility.
+func clipboardUtility() (clipboardTool, error) {
+	tools := clipboardTools[runtime.GOOS]
+	if runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		tools = clipboardTools["linux"]
+		if os.Getenv("WAYLAND_DISPLAY") == "" {
+			tools = tools[1:]
+		}
+	}
+
+	for _, tool := range tools {
+		if _, err := exec.LookPath(tool.paste[0]); err == nil {
+			return tool, nil
+		}
+	}
+
+	return clipboardTool{}, fmt.Errorf("No clipboard utility found")
+}
+
+// readClipboard returns the clipboard contents.
+func readClipboard(cmd []string) (string, error) {
+	out, err := exec.Command(cmd[0], cmd[1:]...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// clipboardMediaURL returns the text if it is a YouTube or
+// Invidious URL which points to a video or a playlist.
+func clipboardMediaURL(text string) (string, bool) {
+	if strings.ContainsAny(text, " \n\t") {
+		return "", false
+	}
+
+	u, err := url.Parse(text)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+
+	switch {
+	case u.Hostname() == "youtu.be":
+		return text, strings.Trim(u.Path, "/") != ""
+
+	case u.Path == "/watch":
+		return text, u.Query().Get("v") != ""
+
+	case u.Path == "/playlist":
+		return text, u.Query().Get("list") != ""
+	}
+
+	return "", false
+}
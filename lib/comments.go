@@ -13,16 +13,25 @@ type CommentResult struct {
 
 // CommentsInfo stores the comment information.
 type CommentsInfo struct {
-	Verified             bool         `json:"verified"`
-	Author               string       `json:"author"`
-	AuthorID             string       `json:"authorId"`
-	AuthorURL            string       `json:"authorUrl"`
-	Content              string       `json:"content"`
-	PublishedText        string       `json:"publishedText"`
-	LikeCount            int          `json:"likeCount"`
-	CommentID            string       `json:"commentId"`
-	AuthorIsChannelOwner bool         `json:"authorIsChannelOwner"`
-	Replies              CommentReply `json:"replies"`
+	Verified             bool          `json:"verified"`
+	Author               string        `json:"author"`
+	AuthorID             string        `json:"authorId"`
+	AuthorURL            string        `json:"authorUrl"`
+	Content              string        `json:"content"`
+	PublishedText        string        `json:"publishedText"`
+	Published            int64         `json:"published"`
+	LikeCount            int           `json:"likeCount"`
+	CommentID            string        `json:"commentId"`
+	AuthorIsChannelOwner bool          `json:"authorIsChannelOwner"`
+	IsPinned             bool          `json:"isPinned"`
+	IsEdited             bool          `json:"isEdited"`
+	CreatorHeart         *CreatorHeart `json:"creatorHeart"`
+	Replies              CommentReply  `json:"replies"`
+}
+
+// CreatorHeart stores the name of the creator who hearted a comment.
+type CreatorHeart struct {
+	CreatorName string `json:"creatorName"`
 }
 
 // CommentReply stores the comment reply count and continuation.
@@ -31,23 +40,19 @@ type CommentReply struct {
 	Continuation string `json:"continuation"`
 }
 
-var (
-	commentCtx    context.Context
-	commentCancel context.CancelFunc
-)
-
 // Comments gets the comments for a video ID.
 func (c *Client) Comments(id string, continuation ...string) (CommentResult, error) {
 	var result CommentResult
 
-	CommentCancel()
+	ctx, done := JobStart("comments")
+	defer done()
 
 	query := "comments/" + id + "?hl=en"
 	if continuation != nil {
 		query += "&continuation=" + continuation[0]
 	}
 
-	res, err := c.ClientRequest(CommentCtx(), query)
+	res, err := c.ClientRequest(ctx, query)
 	if err != nil {
 		return CommentResult{}, err
 	}
@@ -63,14 +68,5 @@ func (c *Client) Comments(id string, continuation ...string) (CommentResult, err
 
 // CommentCtx returns the comment context.
 func CommentCtx() context.Context {
-	return commentCtx
-}
-
-// CommentCancel cancels and renews the comment context.
-func CommentCancel() {
-	if commentCtx != nil {
-		commentCancel()
-	}
-
-	commentCtx, commentCancel = context.WithCancel(context.Background())
+	return JobCtx("comments")
 }
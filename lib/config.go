@@ -1,7 +1,10 @@
 package lib
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,9 +18,14 @@ import (
 var (
 	sockPath   string
 	configPath string
+	dataPath   string
+	cachePath  string
 
 	videoResolution string
 	mpvpath         string
+	mpvArgs         []string
+	mpvArgsText     string
+	mpvProfile      string
 	ytdlpath        string
 	vidsearch       string
 	plistsearch     string
@@ -30,8 +38,60 @@ var (
 	instanceList    bool
 	customInstance  string
 	downloadFolder  string
+	audioDlFolder   string
+	videoDlFolder   string
+	audioDlTemplate string
+	videoDlTemplate string
 	authToken       string
 	genTokenLink    bool
+	screenReader    bool
+	noColor         bool
+	numberFormat    string
+	dateFormat      string
+	durationFormat  string
+	expandedPlayer  bool
+	queueTime       bool
+	scrollSpeed     int
+	sendCommand     string
+	daemonMode      bool
+	searchType      string
+	jsonOutput      bool
+	cliArgs         []string
+	openURL         string
+	listenPort      int
+	listenToken     string
+	syncHost        string
+	syncJoin        string
+	syncToken       string
+	webhooks        string
+	mpdAddress      string
+	debugMode       bool
+	configDir       string
+	proxyURL        string
+	audioBitrate    int
+	videoCodec      string
+	autoDownload    bool
+	restoreSession  string
+	restrictedMode  bool
+	restrictedPIN   string
+	restrictedChans string
+	captionLangs    string
+	liveSearch      bool
+	groupResults    bool
+	hideWatched     bool
+	forceAudio      bool
+	noSuspendPause  bool
+	youtubeLiveChat bool
+	watchClipboard  bool
+	shareLink       string
+	audioDevice     string
+	equalizerPreset string
+	equalizerBands  string
+	normalizeVolume bool
+	watchLaterTitle string
+	requestTimeout  int
+	maxIdleConns    int
+	logLevelName    string
 )
 
 // SetupFlags sets up the commandline flags
@@ -46,7 +106,30 @@ func SetupFlags() error {
 		&videoResolution,
 		"video-res",
 		"720p",
-		"Set the default video resolution.",
+		"Set the default video resolution. If it isn't available, the highest lower resolution is used.",
+	)
+
+	fs.StringVar(
+		&videoCodec,
+		"video-codec",
+		"",
+		"Set the preferred video codec (avc1, vp9, av01) for playback and downloads.",
+	)
+
+	fs.StringVar(
+		&captionLangs,
+		"captions",
+		"",
+		"Set a comma-separated list of caption languages in order of preference, for example \"en,de\".\n"+
+			"Captions in the first available language are loaded when playing videos.",
+	)
+
+	fs.IntVar(
+		&audioBitrate,
+		"audio-bitrate",
+		0,
+		"Set the preferred audio bitrate in kbps for playback and downloads.\n"+
+			"The highest bitrate that does not exceed it is used. Set to 0 to use the highest bitrate.",
 	)
 
 	fs.BoolVar(
@@ -91,6 +174,20 @@ func SetupFlags() error {
 		"Specify path to the mpv executable.",
 	)
 
+	fs.StringVar(
+		&mpvArgsText,
+		"mpv-args",
+		"",
+		"Set additional arguments which mpv is started with, for example \"--hwdec=auto --cache-secs=60\".",
+	)
+
+	fs.StringVar(
+		&mpvProfile,
+		"mpv-profile",
+		"",
+		"Set the mpv profile which is applied when mpv is started.",
+	)
+
 	fs.StringVar(
 		&ytdlpath,
 		"ytdl-path",
@@ -140,6 +237,49 @@ func SetupFlags() error {
 		"Specify directory to download media into.",
 	)
 
+	fs.StringVar(
+		&audioDlFolder,
+		"audio-download-dir",
+		"",
+		"Specify directory to download audio into, instead of --download-dir.",
+	)
+
+	fs.StringVar(
+		&videoDlFolder,
+		"video-download-dir",
+		"",
+		"Specify directory to download video into, instead of --download-dir.",
+	)
+
+	fs.StringVar(
+		&audioDlTemplate,
+		"audio-filename-template",
+		"{title}.{ext}",
+		"Set the file name of audio downloads. {title}, {author}, {id} and {ext} are replaced with the video's details.",
+	)
+
+	fs.StringVar(
+		&videoDlTemplate,
+		"video-filename-template",
+		"{title}.{ext}",
+		"Set the file name of video downloads. {title}, {author}, {id} and {ext} are replaced with the video's details.",
+	)
+
+	fs.BoolVar(
+		&autoDownload,
+		"auto-download-format",
+		false,
+		"Download the preferred format, according to --video-res, --video-codec and --audio-bitrate,\n"+
+			"instead of showing the download options.",
+	)
+
+	fs.StringVar(
+		&watchLaterTitle,
+		"watch-later-playlist",
+		"Watch Later",
+		"Set the title of the account playlist which the Watch Later list is synced with.",
+	)
+
 	fs.StringVar(
 		&authToken,
 		"token",
@@ -147,6 +287,310 @@ func SetupFlags() error {
 		"Specify an authorization token. "+
 			"This has to be used along with the --force-instance option.",
 	)
+	fs.BoolVar(
+		&screenReader,
+		"screen-reader",
+		false,
+		"Use plain text labels and announce player state changes, for screen readers.",
+	)
+
+	fs.BoolVar(
+		&noColor,
+		"no-color",
+		false,
+		"Disable colors and use only bold and reverse text attributes. "+
+			"This is also enabled if the NO_COLOR environment variable is set.",
+	)
+
+	fs.StringVar(
+		&numberFormat,
+		"number-format",
+		"short",
+		"Set how view and subscriber counts are displayed (short, full).",
+	)
+
+	fs.StringVar(
+		&dateFormat,
+		"date-format",
+		"short",
+		"Set how publish dates are displayed (short, relative).\n"+
+			"Any other value is used as a Go time layout to display absolute dates, for example \"2006-01-02\".",
+	)
+
+	fs.StringVar(
+		&durationFormat,
+		"duration-format",
+		"clock",
+		"Set how video durations are displayed in lists (clock, text).",
+	)
+
+	fs.BoolVar(
+		&expandedPlayer,
+		"expanded-player",
+		false,
+		"Show the channel name and a full-width progress bar on separate lines in the player.",
+	)
+
+	fs.BoolVar(
+		&queueTime,
+		"show-queue-time",
+		false,
+		"Show the remaining duration of the queue in the status bar.",
+	)
+
+	fs.IntVar(
+		&scrollSpeed,
+		"title-scroll-speed",
+		4,
+		"Set the speed, in characters per second, at which long titles scroll in the player.\n"+
+			"Set to 0 to disable scrolling.",
+	)
+
+	fs.BoolVar(
+		&restrictedMode,
+		"restricted-mode",
+		false,
+		"Hide videos which are not family friendly from search results, and refuse to play or download them.",
+	)
+
+	fs.StringVar(
+		&restrictedPIN,
+		"restricted-pin",
+		"",
+		"Start in locked mode, which enforces restricted mode and hides the history and downloads until unlocked with this PIN.",
+	)
+
+	fs.StringVar(
+		&restrictedChans,
+		"restricted-channels",
+		"",
+		"Only show and play videos from these channel IDs (comma-separated) in locked mode.",
+	)
+
+	fs.BoolVar(
+		&forceAudio,
+		"force-audio",
+		false,
+		"Load only the audio stream of videos, even if video playback is selected.",
+	)
+
+	fs.BoolVar(
+		&noSuspendPause,
+		"no-suspend-pause",
+		false,
+		"Do not pause playback when invidtui is suspended, or when the system resumes from sleep.",
+	)
+
+	fs.BoolVar(
+		&youtubeLiveChat,
+		"youtube-live-chat",
+		false,
+		"Poll live chat directly from Youtube, if the instance does not serve it. This sends requests to Youtube instead of the instance.",
+	)
+
+	fs.BoolVar(
+		&watchClipboard,
+		"watch-clipboard",
+		false,
+		"Queue YouTube and Invidious video or playlist URLs which are copied to the clipboard.",
+	)
+
+	fs.StringVar(
+		&audioDevice,
+		"audio-device",
+		"",
+		"Set the mpv audio output device, as listed by 'mpv --audio-device=help'.",
+	)
+
+	fs.StringVar(
+		&equalizerPreset,
+		"equalizer",
+		"flat",
+		"Set the audio equalizer preset (flat, bass, vocal, treble, custom).",
+	)
+
+	fs.StringVar(
+		&equalizerBands,
+		"equalizer-bands",
+		"",
+		"Set the gains in dB of the 10 bands of the custom equalizer preset, as a comma-separated list.",
+	)
+
+	fs.BoolVar(
+		&normalizeVolume,
+		"normalize-volume",
+		false,
+		"Normalize the loudness of all loaded files with mpv's loudnorm filter, so that the volume stays consistent.",
+	)
+
+	fs.StringVar(
+		&shareLink,
+		"share-link",
+		"youtube",
+		"Set the link which is copied when sharing the playing video at its current position (youtube, invidious).",
+	)
+
+	fs.StringVar(
+		&restoreSession,
+		"restore-session",
+		"ask",
+		"Set whether the queue and playback position are restored after invidtui exits uncleanly (ask, always, never).",
+	)
+
+	fs.BoolVar(
+		&daemonMode,
+		"daemon",
+		false,
+		"Run the player without the interface, and control it with --send.",
+	)
+
+	fs.BoolVar(
+		&liveSearch,
+		"live-search",
+		false,
+		"Show suggestions and results while typing a search query.",
+	)
+
+	fs.BoolVar(
+		&groupResults,
+		"group-results",
+		false,
+		"Show search results of mixed types in collapsible sections.",
+	)
+
+	fs.BoolVar(
+		&hideWatched,
+		"hide-watched",
+		false,
+		"Hide videos which are in the watch history from search and trending results.",
+	)
+
+	fs.StringVar(
+		&searchType,
+		"search-type",
+		"video",
+		"Set the type of results for the search command (video, playlist, channel, all, song, album, artist).",
+	)
+
+	fs.BoolVar(
+		&jsonOutput,
+		"json",
+		false,
+		"Print the output of commands in JSON.",
+	)
+
+	fs.StringVar(
+		&openURL,
+		"open",
+		"",
+		"Queue a video/playlist URL or ID in a running instance, or start and play it if no instance is running.",
+	)
+
+	fs.StringVar(
+		&sendCommand,
+		"send",
+		"",
+		"Send a command to a running instance and exit, for example \"play-toggle\" or \"queue <url>\".\n"+
+			"Use \"help\" to list the available commands.",
+	)
+
+	fs.IntVar(
+		&listenPort,
+		"listen-port",
+		0,
+		"Set the port on localhost at which browser extensions can queue videos, for example http://127.0.0.1:<port>/?url=<url>&token=<token>.\n"+
+			"Set to 0 to disable.",
+	)
+
+	fs.StringVar(
+		&listenToken,
+		"listen-token",
+		"",
+		"Set the token which requests to the listen-port must include. If not set, a token is generated\n"+
+			"and saved in the \"listen-token\" file in the data directory.",
+	)
+
+	fs.StringVar(
+		&syncHost,
+		"sync-host",
+		"",
+		"Start a sync session on the address, for example \"192.168.1.2:9999\", so that other instances can follow playback.\n"+
+			"If the address has no host, the session is started on 127.0.0.1.",
+	)
+
+	fs.StringVar(
+		&syncJoin,
+		"sync-join",
+		"",
+		"Join the sync session at the address, for example \"192.168.1.2:9999\".",
+	)
+
+	fs.StringVar(
+		&syncToken,
+		"sync-token",
+		"",
+		"Set the token which guests must send to join the sync session. It is required to host or join a session.",
+	)
+
+	fs.StringVar(
+		&webhooks,
+		"webhook",
+		"",
+		"Set a comma-separated list of URLs which receive a JSON payload when a track starts or finishes,\n"+
+			"a download completes or the feed is refreshed.",
+	)
+
+	fs.StringVar(
+		&mpdAddress,
+		"mpd-address",
+		"",
+		"Set the address, for example \"localhost:6600\", at which MPD clients can control the player. Only loopback addresses are allowed.",
+	)
+
+	fs.BoolVar(
+		&debugMode,
+		"debug",
+		false,
+		"Log requests and mpv commands, write the mpv log to the cache directory, and serve pprof at http://localhost:6060/debug/pprof.",
+	)
+
+	fs.StringVar(
+		&logLevelName,
+		"log-level",
+		"info",
+		"Set the level (debug, info, warn, error) of messages written to invidtui.log in the data directory.",
+	)
+
+	fs.StringVar(
+		&proxyURL,
+		"proxy",
+		"",
+		"Set the proxy for requests to instances, downloads and mpv, for example \"http://localhost:8080\" or \"socks5://localhost:1080\".\n"+
+			"mpv only supports HTTP proxies, so a SOCKS5 proxy is not used for playback.",
+	)
+
+	fs.IntVar(
+		&requestTimeout,
+		"request-timeout",
+		10,
+		"Set the timeout in seconds for requests to instances.",
+	)
+
+	fs.IntVar(
+		&maxIdleConns,
+		"max-idle-conns",
+		10,
+		"Set the maximum number of idle connections that are kept open to an instance for reuse.",
+	)
+
+	fs.StringVar(
+		&configDir,
+		"config-dir",
+		"",
+		"Set the directory to read the config file from (default \"$XDG_CONFIG_HOME/invidtui\").\n"+
+			"History, the player state and the log are stored in $XDG_DATA_HOME/invidtui, and the mpv log in $XDG_CACHE_HOME/invidtui.",
+	)
+
 	fs.IntVar(
 		&connretries,
 		"num-retries",
@@ -154,17 +598,16 @@ func SetupFlags() error {
 		"Set the number of retries for connecting to the socket.",
 	)
 
-	config, err := ConfigPath("config")
-	if err != nil {
+	if err := loadConfigFile(fs); err != nil {
 		return err
 	}
-	fs.ParseFile(config)
 
 	fs.Usage = func() {
 		fmt.Fprintf(
 			fs.Output(),
-			"invidtui [<flags>]\n\nConfig file is %s\n\nFlags:\n",
-			config,
+			"invidtui [<flags>] [<command> <args>]\n\nConfig file is %s\n\nCommands:\n%s\n\n"+
+				"Flags (each flag can also be set with an INVIDTUI_<FLAG> environment variable, for example INVIDTUI_FORCE_INSTANCE):\n",
+			configFile, commandUsage(),
 		)
 
 		fs.VisitAll(func(f *flag.Flag) {
@@ -191,14 +634,52 @@ func SetupFlags() error {
 					"play-video",
 					"close-instances",
 					"download-dir",
+					"audio-download-dir",
+					"video-download-dir",
 					"use-current-instance",
+					"screen-reader",
+					"no-color",
+					"expanded-player",
+					"show-queue-time",
+					"send",
+					"daemon",
+					"json",
+					"open",
+					"sync-host",
+					"sync-join",
+					"sync-token",
+					"webhook",
+					"mpd-address",
+					"listen-token",
+					"debug",
+					"config-dir",
+					"proxy",
+					"video-codec",
+					"auto-download-format",
+					"restricted-mode",
+					"restricted-pin",
+					"restricted-channels",
+					"force-audio",
+					"no-suspend-pause",
+					"youtube-live-chat",
+					"watch-clipboard",
+					"mpv-args",
+					"mpv-profile",
+					"audio-device",
+					"equalizer-bands",
+					"normalize-volume",
+					"captions",
+					"live-search",
+					"group-results",
+					"hide-watched",
 				} {
 					if f.Name == name {
 						goto cmdOutPrint
 					}
 				}
 
-				if f.Name != "num-retries" {
+				if f.Name != "num-retries" && f.Name != "title-scroll-speed" && f.Name != "listen-port" && f.Name != "audio-bitrate" &&
+					f.Name != "request-timeout" && f.Name != "max-idle-conns" {
 					s += fmt.Sprintf(" (default %q)", f.DefValue)
 				} else {
 					s += fmt.Sprintf(" (default %v)", f.DefValue)
@@ -210,7 +691,26 @@ func SetupFlags() error {
 		})
 	}
 
-	fs.Parse(os.Args[1:])
+	untrack := trackCLIFlags(fs)
+
+	args := os.Args[1:]
+	for {
+		fs.Parse(args)
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+
+		cliArgs = append(cliArgs, args[0])
+		args = args[1:]
+	}
+
+	untrack()
+
+	if sendCommand != "" {
+		return nil
+	}
 
 	for _, q := range []string{
 		"144p",
@@ -232,86 +732,203 @@ func SetupFlags() error {
 		return fmt.Errorf("%s is not a valid video resolution", videoResolution)
 	}
 
-	_, err = exec.LookPath(mpvpath)
-	if err != nil {
-		return fmt.Errorf("Could not find the mpv executable")
+	if err := checkFormat("number-format", numberFormat); err != nil {
+		return err
 	}
 
-	_, err = exec.LookPath("ffmpeg")
-	if err != nil {
-		return fmt.Errorf("Could not find the ffmpeg executable")
+	if err := checkFormat("duration-format", durationFormat); err != nil {
+		return err
 	}
 
-	err = findYoutubeDL()
-	if err != nil {
+	switch restoreSession {
+	case "ask", "always", "never":
+
+	default:
+		return fmt.Errorf("%s is not a valid session restore mode", restoreSession)
+	}
+
+	if _, ok := equalizerGains[equalizerPreset]; !ok && equalizerPreset != "custom" {
+		return fmt.Errorf("%s is not a valid equalizer preset", equalizerPreset)
+	}
+
+	if _, err := parseEqualizerGains(equalizerBands); err != nil {
 		return err
 	}
 
-	if downloadFolder != "" {
-		if dir, err := os.Stat(downloadFolder); err != nil || !dir.IsDir() {
-			return fmt.Errorf("Cannot access %s for downloads", downloadFolder)
+	for profile, options := range configProfiles {
+		if preset, ok := options["equalizer"]; ok {
+			if _, ok := equalizerGains[preset]; !ok && preset != "custom" {
+				return fmt.Errorf("[player.%s]: %s is not a valid equalizer preset", profile, preset)
+			}
+		}
+
+		if _, err := parseEqualizerGains(options["equalizer-bands"]); err != nil {
+			return fmt.Errorf("[player.%s]: %s", profile, err.Error())
 		}
 	}
 
-	return nil
-}
+	switch shareLink {
+	case "youtube", "invidious":
 
-// SetupConfig checks for the config directory, and creates one if it
-// doesn't exist.
-func SetupConfig() error {
-	var dotConfigExists bool
+	default:
+		return fmt.Errorf("%s is not a valid share link type", shareLink)
+	}
 
-	configDirs := []string{".config/invidtui", ".invidtui"}
+	switch logLevelName {
+	case "debug", "info", "warn", "error":
 
-	fullpath, err := homedir.Expand("~")
-	if err != nil {
-		return fmt.Errorf("Cannot get home directory")
+	default:
+		return fmt.Errorf("%s is not a valid log level", logLevelName)
 	}
 
-	for i, cd := range configDirs {
-		p := filepath.Join(fullpath, cd)
-		configDirs[i] = p
+	switch videoCodec {
+	case "", "avc1", "vp9", "av01":
 
-		if _, err := os.Stat(p); err == nil {
-			configPath = p
-			return nil
+	default:
+		return fmt.Errorf("%s is not a valid video codec", videoCodec)
+	}
+
+	if audioBitrate < 0 {
+		return fmt.Errorf("%d is not a valid audio bitrate", audioBitrate)
+	}
+
+	if requestTimeout <= 0 {
+		return fmt.Errorf("%d is not a valid request timeout", requestTimeout)
+	}
+
+	if maxIdleConns < 0 {
+		return fmt.Errorf("%d is not a valid number of idle connections", maxIdleConns)
+	}
+
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("%s is not a valid proxy URL", proxyURL)
 		}
 
-		if i == 0 {
-			if _, err := os.Stat(
-				filepath.Clean(filepath.Dir(p)),
-			); err == nil {
-				dotConfigExists = true
-			}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+
+		default:
+			return fmt.Errorf("%s is not a supported proxy scheme", u.Scheme)
 		}
 	}
 
-	if configPath == "" {
-		if dotConfigExists {
+	if err := setMPVArgs(mpvArgsText); err != nil {
+		return err
+	}
 
-			err := os.Mkdir(configDirs[0], 0700)
-			if err != nil {
-				return fmt.Errorf("Cannot create %s", configDirs[0])
-			}
+	if mpdAddress != "" {
+		addr, ok := loopbackAddress(mpdAddress)
+		if !ok {
+			return fmt.Errorf("The MPD server has no password support, so it can only listen on a loopback address")
+		}
 
-			configPath = configDirs[0]
+		mpdAddress = addr
+	}
 
-		} else {
+	if syncHost != "" && syncJoin != "" {
+		return fmt.Errorf("Cannot host and join a sync session at the same time")
+	}
 
-			err := os.Mkdir(configDirs[1], 0700)
-			if err != nil {
-				return fmt.Errorf("Cannot create %s", configDirs[1])
-			}
+	if (syncHost != "" || syncJoin != "") && syncToken == "" {
+		return fmt.Errorf("A token must be set with --sync-token to host or join a sync session")
+	}
+
+	if host, port, err := net.SplitHostPort(syncHost); err == nil && host == "" {
+		syncHost = net.JoinHostPort("127.0.0.1", port)
+	}
+
+	switch searchType {
+	case "video", "playlist", "channel", "all", "song", "album", "artist":
 
-			configPath = configDirs[1]
+	default:
+		return fmt.Errorf("%s is not a valid search type", searchType)
+	}
+
+	if !IsCommand() {
+		_, err := exec.LookPath(mpvpath)
+		if err != nil {
+			return fmt.Errorf("Could not find the mpv executable")
+		}
+
+		_, err = exec.LookPath("ffmpeg")
+		if err != nil {
+			return fmt.Errorf("Could not find the ffmpeg executable")
+		}
+
+		err = findYoutubeDL()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, folder := range []string{downloadFolder, audioDlFolder, videoDlFolder} {
+		if folder == "" {
+			continue
+		}
+
+		if dir, err := os.Stat(folder); err != nil || !dir.IsDir() {
+			return fmt.Errorf("Cannot access %s for downloads", folder)
+		}
+	}
+
+	setupRestrictedLock()
+
+	for _, template := range []string{audioDlTemplate, videoDlTemplate} {
+		if !strings.Contains(template, "{title}") && !strings.Contains(template, "{id}") {
+			return fmt.Errorf("The filename template %q must contain {title} or {id}", template)
 		}
 	}
 
 	return nil
 }
 
-// ConfigPath returns the absolute path for the given filetype:
-// socket, history and config, and performs actions related to it.
+// SetupConfig sets up the config, data and cache directories, and creates
+// them if they don't exist. The config directory can be set with --config-dir,
+// and otherwise follows XDG_CONFIG_HOME. If only the old ~/.invidtui directory
+// exists, it is used for all files.
+func SetupConfig() error {
+	home, err := homedir.Expand("~")
+	if err != nil {
+		return fmt.Errorf("Cannot get home directory")
+	}
+
+	xdgDir := func(env, def string) string {
+		if dir := os.Getenv(env); dir != "" && filepath.IsAbs(dir) {
+			return filepath.Join(dir, "invidtui")
+		}
+
+		return filepath.Join(home, def, "invidtui")
+	}
+
+	configPath = xdgDir("XDG_CONFIG_HOME", ".config")
+	dataPath = xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	cachePath = xdgDir("XDG_CACHE_HOME", ".cache")
+
+	if dir := configDirFlag(); dir != "" {
+		configPath, err = homedir.Expand(dir)
+		if err != nil {
+			return fmt.Errorf("Cannot get path of %s", dir)
+		}
+	} else if _, err := os.Stat(configPath); err != nil {
+		legacy := filepath.Join(home, ".invidtui")
+		if _, err := os.Stat(legacy); err == nil {
+			configPath, dataPath, cachePath = legacy, legacy, legacy
+		}
+	}
+
+	for _, dir := range []string{configPath, dataPath, cachePath} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("Cannot create %s", dir)
+		}
+	}
+
+	return nil
+}
+
+// ConfigPath returns the absolute path for the given filetype in the
+// config directory: socket, auth and config, and performs actions related to it.
 func ConfigPath(ftype string) (string, error) {
 	var cfpath string
 
@@ -338,18 +955,75 @@ func ConfigPath(ftype string) (string, error) {
 	default:
 		cfpath = filepath.Join(configPath, ftype)
 
-		if _, err := os.Stat(cfpath); err != nil {
-			fd, err := os.Create(cfpath)
-			fd.Close()
-			if err != nil {
-				return "", fmt.Errorf("Cannot create %s file at %s", ftype, cfpath)
-			}
+		if err := createFile(ftype, cfpath); err != nil {
+			return "", err
 		}
 	}
 
 	return cfpath, nil
 }
 
+// DataPath returns the absolute path for the given file in the data
+// directory, such as the history and the player state. Files which
+// were stored in the config directory by older versions are moved
+// into the data directory.
+func DataPath(name string) (string, error) {
+	path := filepath.Join(dataPath, name)
+
+	if _, err := os.Stat(path); err != nil && dataPath != configPath {
+		os.Rename(filepath.Join(configPath, name), path)
+	}
+
+	if err := createFile(name, path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// createFile creates an empty file at path, if it doesn't exist.
+func createFile(ftype, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	fd, err := os.Create(path)
+	fd.Close()
+	if err != nil {
+		return fmt.Errorf("Cannot create %s file at %s", ftype, path)
+	}
+
+	return nil
+}
+
+// configDirFlag returns the directory set with --config-dir or
+// INVIDTUI_CONFIG_DIR. It is checked before the flags are parsed,
+// since the config file is read from this directory.
+func configDirFlag() string {
+	args := os.Args[1:]
+
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+
+		if strings.HasPrefix(name, "config-dir=") {
+			return strings.TrimPrefix(name, "config-dir=")
+		}
+
+		if name == "config-dir" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return os.Getenv("INVIDTUI_CONFIG_DIR")
+}
+
 // GetSearchQuery returns the search type and query from
 // the command-line options.
 func GetSearchQuery() (string, string, error) {
@@ -381,6 +1055,215 @@ func GetPlayParams() (string, bool, error) {
 	return "", false, fmt.Errorf("No player parameters specified")
 }
 
+// OpenURL forwards the URL specified with --open to a running instance, and
+// returns whether it was forwarded. If no instance is running, the URL is
+// set to be played on startup instead.
+func OpenURL() (bool, error) {
+	if openURL == "" {
+		return false, nil
+	}
+
+	_, err := SendControl("queue " + openURL)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, ErrNoInstance) {
+		return false, err
+	}
+
+	playvideo = openURL
+
+	return false, nil
+}
+
+// ScreenReaderMode returns whether the screen-reader friendly mode is enabled.
+func ScreenReaderMode() bool {
+	return screenReader
+}
+
+// NoColorMode returns whether colors should be disabled.
+func NoColorMode() bool {
+	return noColor || os.Getenv("NO_COLOR") != ""
+}
+
+// ExpandedPlayer returns whether the expanded player layout is enabled.
+func ExpandedPlayer() bool {
+	return expandedPlayer
+}
+
+// TitleScrollSpeed returns the speed at which long titles scroll in the player.
+func TitleScrollSpeed() int {
+	return scrollSpeed
+}
+
+// ShowQueueTime returns whether the remaining duration of
+// the queue should be shown in the status bar.
+func ShowQueueTime() bool {
+	return queueTime
+}
+
+// AutoDownloadFormat returns whether the preferred format should be
+// downloaded without showing the download options.
+func AutoDownloadFormat() bool {
+	return autoDownload
+}
+
+// RestoreSession returns whether the previous session should be restored.
+func RestoreSession() string {
+	return restoreSession
+}
+
+// LiveSearch returns whether results should be shown while typing a search query.
+func LiveSearch() bool {
+	return liveSearch
+}
+
+// GroupResults returns whether search results of mixed types should be grouped by type.
+func GroupResults() bool {
+	return groupResults
+}
+
+// HideWatched returns whether watched videos should be hidden from search and trending results.
+func HideWatched() bool {
+	return hideWatched
+}
+
+// WatchClipboard returns whether URLs copied to the clipboard should be queued.
+func WatchClipboard() bool {
+	return watchClipboard
+}
+
+// DaemonMode returns whether invidtui is running without the interface.
+func DaemonMode() bool {
+	return daemonMode
+}
+
+// MPDAddress returns the address for the MPD server.
+func MPDAddress() string {
+	return mpdAddress
+}
+
+// SendCommand returns the command to be sent to a running instance.
+func SendCommand() string {
+	return sendCommand
+}
+
+// loopbackAddress returns the address with its host set to 127.0.0.1 if
+// it has no host, and whether the host is a loopback address.
+func loopbackAddress(addr string) (string, bool) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, false
+	}
+
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return addr, false
+	}
+
+	return net.JoinHostPort(host, port), true
+}
+
+// setMPVArgs parses and sets the arguments which mpv is started with.
+// They are applied when mpv is started or restarted.
+func setMPVArgs(text string) error {
+	args, err := parseMPVArgs(text)
+	if err != nil {
+		return err
+	}
+
+	mpvLock.Lock()
+	defer mpvLock.Unlock()
+
+	mpvArgs = args
+
+	return nil
+}
+
+// parseMPVArgs splits the arguments set with --mpv-args. Arguments can be
+// quoted with single or double quotes to include spaces. Arguments which
+// would break the connection to mpv, or make mpv exit or unload a file
+// when the playback ends, are not allowed.
+func parseMPVArgs(text string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var quote rune
+	var inArg bool
+
+	for _, r := range text {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+
+		case quote != 0:
+			arg.WriteRune(r)
+
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("mpv-args: unterminated quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	for _, arg := range args {
+		for _, option := range []string{
+			"--input-ipc-server",
+			"--idle",
+			"--terminal",
+			"--input-terminal",
+		} {
+			if arg == option || strings.HasPrefix(arg, option+"=") {
+				return nil, fmt.Errorf("mpv-args: %s cannot be changed", option)
+			}
+		}
+
+		switch arg {
+		case "--no-idle", "--no-keep-open", "--keep-open=no":
+			return nil, fmt.Errorf("mpv-args: %s is not allowed", arg)
+		}
+	}
+
+	return args, nil
+}
+
+// checkFormat checks whether value is valid for the number
+// or duration format options.
+func checkFormat(option, value string) error {
+	switch option {
+	case "number-format":
+		if value != "short" && value != "full" {
+			return fmt.Errorf("%s is not a valid number format", value)
+		}
+
+	case "duration-format":
+		if value != "clock" && value != "text" {
+			return fmt.Errorf("%s is not a valid duration format", value)
+		}
+	}
+
+	return nil
+}
+
 // findYoutubeDL searches for the youtube-dl or yt-dlp executables.
 func findYoutubeDL() error {
 	if ytdlpath != "" {
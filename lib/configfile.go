@@ -0,0 +1,759 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jnovack/flag"
+)
+
+// configSection describes a section of the config file, and
+// the flags which can be set in it.
+type configSection struct {
+	name    string
+	comment string
+	options []string
+}
+
+// configSections lists the sections of the config file in the order
+// they are written. The keybinds section and the colors in the theme
+// section are not flags, and are handled separately.
+var configSections = []configSection{
+	{
+		name:    "instances",
+		comment: "Invidious instance selection and authentication.",
+		options: []string{"force-instance", "use-current-instance", "token", "watch-later-playlist", "proxy", "request-timeout", "max-idle-conns"},
+	},
+	{
+		name:    "player",
+		comment: "Player and media options.",
+		options: []string{
+			"video-res", "video-codec", "audio-bitrate", "captions", "mpv-path", "mpv-args", "mpv-profile",
+			"ytdl-path", "num-retries", "restore-session", "restricted-mode", "restricted-pin",
+			"restricted-channels", "force-audio", "no-suspend-pause", "share-link", "audio-device",
+			"equalizer", "equalizer-bands", "normalize-volume", "youtube-live-chat",
+		},
+	},
+	{
+		name:    "downloads",
+		comment: "Download options.",
+		options: []string{
+			"download-dir", "audio-download-dir", "video-download-dir",
+			"audio-filename-template", "video-filename-template", "auto-download-format",
+		},
+	},
+	{
+		name:    "search",
+		comment: "Search options.",
+		options: []string{"live-search", "group-results", "hide-watched"},
+	},
+	{
+		name:    "theme",
+		comment: "Display options, and the colors of selected list entries.",
+		options: []string{
+			"no-color", "screen-reader", "number-format", "date-format",
+			"duration-format", "expanded-player", "show-queue-time", "title-scroll-speed",
+		},
+	},
+	{
+		name:    "integrations",
+		comment: "Servers and hooks for controlling invidtui from other programs.",
+		options: []string{"listen-port", "listen-token", "mpd-address", "webhook", "watch-clipboard"},
+	},
+	{
+		name:    "logging",
+		comment: "Logging options.",
+		options: []string{"log-level"},
+	},
+}
+
+// themeDefaults lists the colors which can be set in the theme section.
+var themeDefaults = []struct {
+	name, value string
+}{
+	{"selected-foreground", "blue"},
+	{"selected-background", "white"},
+}
+
+// reloadOptions lists the options which are applied when the
+// config file is reloaded. Other options need a restart to change.
+// The mpv arguments are applied when mpv is restarted.
+var reloadOptions = map[string]struct{}{
+	"number-format":      {},
+	"date-format":        {},
+	"duration-format":    {},
+	"show-queue-time":    {},
+	"title-scroll-speed": {},
+	"normalize-volume":   {},
+	"mpv-args":           {},
+}
+
+// profileOptions lists the options which can be set for an mpv profile,
+// in a [player.<profile>] section. They override the options in the player
+// section when the profile is selected with mpv-profile.
+var profileOptions = map[string]struct{}{
+	"equalizer":       {},
+	"equalizer-bands": {},
+}
+
+var (
+	configFile     string
+	configFlags    *flag.FlagSet
+	cliFlags       = make(map[string]struct{})
+	configModified time.Time
+	configTheme    = make(map[string]string)
+	configKeybinds = make(map[string]map[string]string)
+	configProfiles = make(map[string]map[string]string)
+)
+
+// loadConfigFile applies the options in the config file to the flagset.
+// If the config file doesn't exist, the options from the old flag-style
+// config file are applied instead, and a config file is generated from them.
+func loadConfigFile(fs *flag.FlagSet) error {
+	configFlags = fs
+	configFile = filepath.Join(configPath, "config.toml")
+
+	if _, err := os.Stat(configFile); err != nil {
+		legacy := filepath.Join(configPath, "config")
+		if _, err := os.Stat(legacy); err == nil {
+			fs.ParseFile(legacy)
+		}
+
+		if err := writeConfigFile(fs); err != nil {
+			return err
+		}
+	}
+
+	if info, err := os.Stat(configFile); err == nil {
+		configModified = info.ModTime()
+	}
+
+	sections, err := parseConfigFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	return applyConfig(fs, sections)
+}
+
+// WatchConfig checks the config file for modifications every few
+// seconds, and calls reload when it is modified.
+func WatchConfig(reload func()) {
+	go func() {
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			info, err := os.Stat(configFile)
+			if err != nil || info.ModTime().Equal(configModified) {
+				continue
+			}
+
+			configModified = info.ModTime()
+
+			reload()
+		}
+	}()
+}
+
+// ReloadConfig parses the config file again, and applies the theme colors,
+// keybindings and the options which can be changed at runtime. Options which
+// were set on the command line or with environment variables are not changed,
+// and options which were removed from the config file are reset to their
+// defaults. The keybindings are checked with checkKeybinds, since they are
+// parsed by the UI. Nothing is applied if any of the options or keybindings
+// are invalid.
+func ReloadConfig(checkKeybinds func(keybinds map[string]map[string]string) error) error {
+	sections, err := parseConfigFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	options, theme, keybinds, profiles, err := splitConfig(sections)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(reloadOptions))
+	for option := range reloadOptions {
+		if f := configFlags.Lookup(option); f != nil {
+			values[option] = f.DefValue
+		}
+	}
+
+	for _, option := range options {
+		if _, ok := reloadOptions[option[0]]; !ok {
+			continue
+		}
+
+		if err := checkOption(configFlags.Lookup(option[0]), option[1]); err != nil {
+			return fmt.Errorf("%s: %s", configFile, err.Error())
+		}
+
+		values[option[0]] = option[1]
+	}
+
+	if _, err := parseMPVArgs(values["mpv-args"]); err != nil {
+		return fmt.Errorf("%s: %s", configFile, err.Error())
+	}
+
+	if err := checkKeybinds(keybinds); err != nil {
+		return fmt.Errorf("%s: %s", configFile, err.Error())
+	}
+
+	normalized, argsText := normalizeVolume, mpvArgsText
+
+	for option, value := range values {
+		if _, ok := cliFlags[option]; ok || envOverride(option) {
+			continue
+		}
+
+		configFlags.Set(option, value)
+	}
+
+	if normalizeVolume != normalized {
+		go applyNormalization(GetMPV())
+	}
+
+	if mpvArgsText != argsText {
+		setMPVArgs(mpvArgsText)
+	}
+
+	configTheme = theme
+	configKeybinds = keybinds
+	configProfiles = profiles
+
+	return nil
+}
+
+// ThemeColor returns the color set for name in the theme section
+// of the config file, or def if it isn't set.
+func ThemeColor(name, def string) string {
+	if color, ok := configTheme[name]; ok && color != "" {
+		return color
+	}
+
+	return def
+}
+
+// Keybindings returns the keybindings set in the config file for each
+// context, as a map of the new key to the default key. Keybindings in
+// the keybinds section are returned in the global context.
+func Keybindings() map[string]map[string]string {
+	keybinds := make(map[string]map[string]string, len(configKeybinds))
+	for context, keys := range configKeybinds {
+		keybinds[context] = make(map[string]string, len(keys))
+
+		for key, action := range keys {
+			keybinds[context][key] = action
+		}
+	}
+
+	return keybinds
+}
+
+// applyConfig sets the flags, theme colors and keybindings from
+// the parsed sections of the config file. Options which are set
+// with environment variables are skipped, so that they take precedence.
+func applyConfig(fs *flag.FlagSet, sections map[string]map[string]string) error {
+	options, theme, keybinds, profiles, err := splitConfig(sections)
+	if err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		if envOverride(option[0]) {
+			continue
+		}
+
+		if err := fs.Set(option[0], option[1]); err != nil {
+			return fmt.Errorf("%s: %s is not a valid value for %s", configFile, option[1], option[0])
+		}
+	}
+
+	configTheme = theme
+	configKeybinds = keybinds
+	configProfiles = profiles
+
+	return nil
+}
+
+// splitConfig splits the parsed sections of the config file into
+// flag options, theme colors, keybindings and the options of each
+// mpv profile.
+func splitConfig(
+	sections map[string]map[string]string,
+) ([][2]string, map[string]string, map[string]map[string]string, map[string]map[string]string, error) {
+	var options [][2]string
+
+	theme := make(map[string]string)
+	keybinds := make(map[string]map[string]string)
+	profiles := make(map[string]map[string]string)
+
+	for name, section := range sections {
+		if strings.HasPrefix(name, "player.") {
+			for key, value := range section {
+				if _, ok := profileOptions[key]; !ok {
+					return nil, nil, nil, nil, fmt.Errorf("%s: unknown option %s in section [%s]", configFile, key, name)
+				}
+
+				if err := checkOption(configFlags.Lookup(key), value); err != nil {
+					return nil, nil, nil, nil, fmt.Errorf("%s: [%s]: %s", configFile, name, err.Error())
+				}
+			}
+
+			profiles[strings.TrimPrefix(name, "player.")] = section
+			continue
+		}
+
+		if name == "keybinds" || strings.HasPrefix(name, "keybinds.") {
+			context := strings.TrimPrefix(strings.TrimPrefix(name, "keybinds"), ".")
+			if context == "" {
+				context = "global"
+			}
+
+			keybinds[context] = section
+			continue
+		}
+
+		for key, value := range section {
+			switch {
+
+			case name == "theme" && isThemeColor(key):
+				theme[key] = value
+
+			case isConfigOption(name, key):
+				options = append(options, [2]string{key, value})
+
+			default:
+				return nil, nil, nil, nil, fmt.Errorf("%s: unknown option %s in section [%s]", configFile, key, name)
+			}
+		}
+	}
+
+	return options, theme, keybinds, profiles, nil
+}
+
+// profileOption returns the value of the option which is set for the
+// mpv profile selected with mpv-profile, or def if it isn't set.
+func profileOption(name, def string) string {
+	if value, ok := configProfiles[mpvProfile][name]; ok && mpvProfile != "" {
+		return value
+	}
+
+	return def
+}
+
+// parseConfigFile parses the config file into a map of sections to options.
+// It supports the subset of TOML needed for the config file: sections,
+// and keys with string, integer or boolean values.
+func parseConfigFile(path string) (map[string]map[string]string, error) {
+	var section string
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read config file at %s", path)
+	}
+
+	sections := make(map[string]map[string]string)
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("%s:%d: invalid section", path, i+1)
+			}
+
+			section = strings.TrimSpace(line[1:end])
+			if sections[section] == nil {
+				sections[section] = make(map[string]string)
+			}
+
+			continue
+		}
+
+		if section == "" {
+			return nil, fmt.Errorf("%s:%d: option is not in a section", path, i+1)
+		}
+
+		keyEnd := 0
+		if line[0] == '"' || line[0] == '\'' {
+			if end := strings.IndexByte(line[1:], line[0]); end >= 0 {
+				keyEnd = end + 2
+			}
+		}
+
+		pos := strings.Index(line[keyEnd:], "=") + keyEnd
+		if pos < keyEnd {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, i+1)
+		}
+
+		key, err := parseConfigValue(line[:pos])
+		if err != nil || key == "" {
+			return nil, fmt.Errorf("%s:%d: invalid key", path, i+1)
+		}
+
+		value, err := parseConfigValue(line[pos+1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, i+1, err.Error())
+		}
+
+		sections[section][key] = value
+	}
+
+	return sections, nil
+}
+
+// parseConfigValue parses a quoted or bare value, and strips trailing comments.
+func parseConfigValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	switch value[0] {
+	case '"':
+		for i := 1; i < len(value); i++ {
+			switch value[i] {
+			case '\\':
+				i++
+
+			case '"':
+				if rest := strings.TrimSpace(value[i+1:]); rest != "" && rest[0] != '#' {
+					return "", fmt.Errorf("unexpected text after string")
+				}
+
+				return strconv.Unquote(value[:i+1])
+			}
+		}
+
+		return "", fmt.Errorf("unterminated string")
+
+	case '\'':
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+
+		return value[1 : end+1], nil
+	}
+
+	if pos := strings.Index(value, "#"); pos >= 0 {
+		value = value[:pos]
+	}
+
+	return strings.TrimSpace(value), nil
+}
+
+// writeConfigFile generates a commented config file. Options which were
+// set in the flagset are written with their values, and all other options
+// are written commented out with their default values.
+func writeConfigFile(fs *flag.FlagSet) error {
+	set := make(map[string]struct{})
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = struct{}{}
+	})
+
+	file, err := os.OpenFile(configFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("Cannot create config file at %s", configFile)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+
+	fmt.Fprint(w, "# invidtui configuration file.\n#\n")
+	fmt.Fprint(w, "# Each option can be overridden by the command-line flag of the same name,\n")
+	fmt.Fprint(w, "# for example --video-res=1080p, or by an environment variable, for example\n")
+	fmt.Fprint(w, "# INVIDTUI_VIDEO_RES=1080p. Uncomment an option to change it.\n")
+	fmt.Fprint(w, "# Changes to the keybinds, colors, display formats and volume normalization are applied while running.\n")
+
+	for _, section := range configSections {
+		fmt.Fprintf(w, "\n# %s\n[%s]\n", section.comment, section.name)
+
+		for _, name := range section.options {
+			f := fs.Lookup(name)
+			if f == nil {
+				continue
+			}
+
+			fmt.Fprintf(w, "\n# %s\n", strings.ReplaceAll(f.Usage, "\n", "\n# "))
+
+			if _, ok := set[name]; ok {
+				fmt.Fprintf(w, "%s = %s\n", name, formatConfigValue(f, f.Value.String()))
+			} else {
+				fmt.Fprintf(w, "# %s = %s\n", name, formatConfigValue(f, f.DefValue))
+			}
+		}
+
+		if section.name == "theme" {
+			fmt.Fprint(w, "\n# The colors of the selected entry in lists, as color names or #rrggbb values.\n")
+
+			for _, color := range themeDefaults {
+				fmt.Fprintf(w, "# %s = %q\n", color.name, color.value)
+			}
+		}
+	}
+
+	fmt.Fprint(w, "\n# Remap keys, as \"<new key>\" = \"<default key>\". Keys are written as a single\n")
+	fmt.Fprint(w, "# character, or a key name such as \"enter\", \"esc\", \"pgup\" or \"f5\", optionally\n")
+	fmt.Fprint(w, "# prefixed with \"ctrl+\" or \"alt+\". Keys in this section are remapped in every view.\n")
+	fmt.Fprint(w, "[keybinds]\n")
+	fmt.Fprint(w, "# \"ctrl+f\" = \"/\"\n")
+	fmt.Fprint(w, "\n# Keys can also be remapped only in a view, in the [keybinds.results],\n")
+	fmt.Fprint(w, "# [keybinds.player], [keybinds.playlist], [keybinds.filebrowser] and\n")
+	fmt.Fprint(w, "# [keybinds.comments] sections.\n")
+	fmt.Fprint(w, "# [keybinds.playlist]\n")
+	fmt.Fprint(w, "# \"x\" = \"d\"\n")
+
+	fmt.Fprint(w, "\n# The equalizer options can be set for an mpv profile selected with\n")
+	fmt.Fprint(w, "# mpv-profile, in a [player.<profile>] section. The equalizer is saved\n")
+	fmt.Fprint(w, "# in this section when it is changed while the profile is selected.\n")
+	fmt.Fprint(w, "# [player.night]\n")
+	fmt.Fprint(w, "# equalizer = \"vocal\"\n")
+
+	return w.Flush()
+}
+
+// saveConfigOption sets the option to value in the config file, so that it
+// is applied on the next start. The line with the option, or its commented
+// out default, is replaced in the option's section, and the rest of the file
+// is kept as is.
+func saveConfigOption(name, value string) error {
+	var section string
+
+	for _, s := range configSections {
+		if isConfigOption(s.name, name) {
+			section = s.name
+			break
+		}
+	}
+
+	f := configFlags.Lookup(name)
+	if section == "" || f == nil {
+		return fmt.Errorf("%s is not a config option", name)
+	}
+
+	if err := writeConfigOption(section, name, formatConfigValue(f, value)); err != nil {
+		return err
+	}
+
+	return configFlags.Set(name, value)
+}
+
+// saveProfileOption sets the option to value in the section of the mpv
+// profile selected with mpv-profile, or in the option's section if no
+// profile is selected.
+func saveProfileOption(name, value string) error {
+	f := configFlags.Lookup(name)
+	if _, ok := profileOptions[name]; !ok || f == nil {
+		return fmt.Errorf("%s is not a profile option", name)
+	}
+
+	if mpvProfile == "" {
+		return saveConfigOption(name, value)
+	}
+
+	if err := writeConfigOption("player."+mpvProfile, name, formatConfigValue(f, value)); err != nil {
+		return err
+	}
+
+	if configProfiles[mpvProfile] == nil {
+		configProfiles[mpvProfile] = make(map[string]string)
+	}
+	configProfiles[mpvProfile][name] = value
+
+	return nil
+}
+
+// writeConfigOption writes the option with the formatted value into
+// the section of the config file, and adds the section if it is not
+// present.
+func writeConfigOption(section, name, value string) error {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("Cannot read config file at %s", configFile)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	option := name + " = " + value
+
+	header, set, commented := -1, -1, -1
+	current := ""
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "[") {
+			if end := strings.Index(line, "]"); end > 0 {
+				current = strings.TrimSpace(line[1:end])
+				if current == section {
+					header = i
+				}
+			}
+
+			continue
+		}
+
+		if current != section {
+			continue
+		}
+
+		key := strings.TrimSpace(strings.TrimLeft(line, "#"))
+		if !strings.HasPrefix(key, name) ||
+			!strings.HasPrefix(strings.TrimSpace(key[len(name):]), "=") {
+			continue
+		}
+
+		if line[0] == '#' {
+			commented = i
+		} else {
+			set = i
+		}
+	}
+
+	switch {
+	case set >= 0:
+		lines[set] = option
+
+	case commented >= 0:
+		lines[commented] = option
+
+	case header >= 0:
+		lines = append(lines[:header+1], append([]string{option}, lines[header+1:]...)...)
+
+	default:
+		lines = append(lines, "["+section+"]", option)
+	}
+
+	if err := ioutil.WriteFile(configFile, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		return fmt.Errorf("Cannot write config file at %s", configFile)
+	}
+
+	if info, err := os.Stat(configFile); err == nil {
+		configModified = info.ModTime()
+	}
+
+	return nil
+}
+
+// formatConfigValue returns the value of a flag as written in the config file.
+func formatConfigValue(f *flag.Flag, value string) string {
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		return value
+	}
+
+	if _, err := strconv.Atoi(f.DefValue); err == nil {
+		return value
+	}
+
+	return strconv.Quote(value)
+}
+
+// checkOption checks whether value is valid for the option.
+func checkOption(f *flag.Flag, value string) error {
+	if f == nil {
+		return nil
+	}
+
+	if err := checkFormat(f.Name, value); err != nil {
+		return err
+	}
+
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s is not a valid value for %s", value, f.Name)
+		}
+	} else if _, err := strconv.Atoi(f.DefValue); err == nil {
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%s is not a valid value for %s", value, f.Name)
+		}
+	}
+
+	return nil
+}
+
+// cliValue records the flags which are set on the command line.
+type cliValue struct {
+	flag.Value
+
+	name string
+}
+
+// Set records the flag and sets its value.
+func (c *cliValue) Set(value string) error {
+	cliFlags[c.name] = struct{}{}
+
+	return c.Value.Set(value)
+}
+
+// IsBoolFlag returns whether the flag is a boolean flag.
+func (c *cliValue) IsBoolFlag() bool {
+	bf, ok := c.Value.(interface{ IsBoolFlag() bool })
+
+	return ok && bf.IsBoolFlag()
+}
+
+// trackCLIFlags records the flags which are set while the command line
+// is parsed, so that they are not overridden when the config file is
+// reloaded. The returned function stops recording the flags.
+func trackCLIFlags(fs *flag.FlagSet) func() {
+	fs.VisitAll(func(f *flag.Flag) {
+		f.Value = &cliValue{Value: f.Value, name: f.Name}
+	})
+
+	return func() {
+		fs.VisitAll(func(f *flag.Flag) {
+			if c, ok := f.Value.(*cliValue); ok {
+				f.Value = c.Value
+			}
+		})
+	}
+}
+
+// envOverride returns whether the option is set with an
+// INVIDTUI_<OPTION> environment variable.
+func envOverride(option string) bool {
+	name := "INVIDTUI_" + strings.ToUpper(strings.ReplaceAll(option, "-", "_"))
+
+	return os.Getenv(name) != ""
+}
+
+// isConfigOption returns whether the option can be set in the section.
+func isConfigOption(section, option string) bool {
+	for _, s := range configSections {
+		if s.name != section {
+			continue
+		}
+
+		for _, o := range s.options {
+			if o == option {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isThemeColor returns whether name is a color in the theme section.
+func isThemeColor(name string) bool {
+	for _, color := range themeDefaults {
+		if color.name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,188 @@
+package lib
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ControlHandler describes a function which handles a command
+// sent to the control socket, and returns a reply.
+type ControlHandler func(args []string) (string, error)
+
+// ControlReply stores the reply to a control command.
+type ControlReply struct {
+	OK    bool   `json:"ok"`
+	Reply string `json:"reply,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ErrNoInstance is returned when no running instance is listening
+// on the control socket.
+var ErrNoInstance = errors.New("Cannot connect to a running instance")
+
+var (
+	controlListener net.Listener
+	controlHandlers = make(map[string]ControlHandler)
+	controlLock     sync.Mutex
+)
+
+// RegisterControl registers a handler for a control command.
+func RegisterControl(name string, handler ControlHandler) {
+	controlLock.Lock()
+	defer controlLock.Unlock()
+
+	controlHandlers[name] = handler
+}
+
+// ControlCommands returns the names of the registered control commands.
+func ControlCommands() []string {
+	controlLock.Lock()
+	defer controlLock.Unlock()
+
+	names := make([]string, 0, len(controlHandlers))
+	for name := range controlHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// StartControl starts listening for commands on the control socket.
+func StartControl() error {
+	sock := controlSocketPath()
+
+	if _, err := os.Stat(sock); err == nil {
+		if conn, err := net.DialTimeout("unix", sock, time.Second); err == nil {
+			conn.Close()
+			return fmt.Errorf("Control socket at %s is in use, is another instance running?", sock)
+		}
+
+		os.Remove(sock)
+	}
+
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		return fmt.Errorf("Cannot create control socket at %s", sock)
+	}
+
+	controlLock.Lock()
+	controlListener = listener
+	controlLock.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handleControl(conn)
+		}
+	}()
+
+	return nil
+}
+
+// StopControl stops listening on the control socket.
+func StopControl() {
+	controlLock.Lock()
+	defer controlLock.Unlock()
+
+	if controlListener == nil {
+		return
+	}
+
+	controlListener.Close()
+	controlListener = nil
+
+	os.Remove(controlSocketPath())
+}
+
+// SendControl sends a command to the control socket of a running
+// instance and returns its reply.
+func SendControl(command string) (string, error) {
+	var reply ControlReply
+
+	conn, err := net.DialTimeout("unix", controlSocketPath(), 5*time.Second)
+	if err != nil {
+		return "", ErrNoInstance
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	_, err = fmt.Fprintln(conn, strings.TrimSpace(command))
+	if err != nil {
+		return "", err
+	}
+
+	err = json.NewDecoder(conn).Decode(&reply)
+	if err != nil {
+		return "", fmt.Errorf("Invalid reply from the running instance")
+	}
+
+	if !reply.OK {
+		return "", errors.New(reply.Error)
+	}
+
+	return reply.Reply, nil
+}
+
+// handleControl reads a command from the connection, runs it
+// and writes the reply.
+func handleControl(conn net.Conn) {
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+
+	json.NewEncoder(conn).Encode(runControl(strings.Fields(line)))
+}
+
+// runControl runs a control command with its arguments.
+func runControl(args []string) ControlReply {
+	var reply ControlReply
+
+	if len(args) == 0 {
+		reply.Error = "No command specified"
+		return reply
+	}
+
+	controlLock.Lock()
+	handler, ok := controlHandlers[args[0]]
+	controlLock.Unlock()
+
+	if !ok {
+		reply.Error = fmt.Sprintf("%s is not a valid command", args[0])
+		return reply
+	}
+
+	text, err := handler(args[1:])
+	if err != nil {
+		reply.Error = err.Error()
+	} else {
+		reply.OK = true
+		reply.Reply = text
+	}
+
+	return reply
+}
+
+// controlSocketPath returns the path to the control socket.
+func controlSocketPath() string {
+	return filepath.Join(configPath, "control")
+}
@@ -0,0 +1,173 @@
+package lib
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RunDaemon runs the player without the interface, and controls it via
+// the control socket. It returns when the quit command is sent, when an
+// interrupt or termination signal is received, or when mpv has exited
+// and cannot be restarted.
+func RunDaemon() error {
+	var quitOnce sync.Once
+
+	quit := make(chan struct{})
+	stop := func() {
+		quitOnce.Do(func() {
+			close(quit)
+		})
+	}
+
+	RegisterPlayerControl(func() {}, stop)
+
+	if err := StartControl(); err != nil {
+		GetMPV().MPVStop(true)
+		return err
+	}
+
+	if err := StartEndpoint(); err != nil {
+		log.Println("Error: " + err.Error())
+	}
+
+	go monitorDaemon(quit)
+	go detectDaemonClose(quit, stop)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	select {
+	case <-sig:
+	case <-quit:
+	}
+
+	stop()
+
+	StopControl()
+	StopEndpoint()
+	GetMPV().MPVStop(true)
+
+	return nil
+}
+
+// monitorDaemon logs the playback errors and resumed positions from mpv,
+// and tracks the playback time so that the queue can be restored if mpv
+// has to be restarted.
+func monitorDaemon(quit chan struct{}) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+
+		case <-t.C:
+			TrackRecovery()
+
+		case perr, ok := <-MPVErrors:
+			if !ok {
+				return
+			}
+
+			log.Printf("Error: Unable to play %s: %s", perr.Title, perr.Error)
+
+		case _, ok := <-MPVFileLoaded:
+			if !ok {
+				return
+			}
+
+			if data := PlayingData(); data != nil {
+				log.Println("Playing " + data.Get("title"))
+			}
+
+		case position, ok := <-MPVResumed:
+			if !ok {
+				return
+			}
+
+			log.Println("Resuming at " + FormatDuration(position))
+		}
+	}
+}
+
+// detectDaemonClose reconnects to mpv if the connection to it was lost,
+// and restarts it with the previous queue if it has exited. The daemon
+// is stopped if mpv cannot be restarted.
+func detectDaemonClose(quit chan struct{}, stop func()) {
+	closing := func() bool {
+		select {
+		case <-quit:
+			return true
+
+		default:
+		}
+
+		return false
+	}
+
+	for {
+		GetMPV().WaitUntilClosed()
+		if closing() {
+			return
+		}
+
+		log.Println("Connection to MPV lost, reconnecting")
+
+		if err := MPVReconnect(); err == nil {
+			log.Println("Reconnected to MPV")
+			continue
+		}
+		if closing() {
+			return
+		}
+
+		log.Println("Restarting MPV")
+
+		queue, position, playTime, err := MPVRestart()
+		if err != nil {
+			log.Println("Error: " + err.Error())
+			stop()
+
+			return
+		}
+
+		go restoreDaemonQueue(queue, position, playTime)
+	}
+}
+
+// restoreDaemonQueue loads the queue into a restarted mpv instance,
+// and resumes the entry at position from the provided playback time.
+func restoreDaemonQueue(queue []string, position int, playTime int64) {
+	if len(queue) == 0 {
+		return
+	}
+
+	GetMPV().Set("pause", "yes")
+
+	for _, filename := range queue {
+		GetMPV().PlaylistInsert(filename, -1)
+	}
+
+	if position < 0 || position >= GetMPV().PlaylistCount() {
+		return
+	}
+
+	GetMPV().SetPlaylistPos(position)
+
+	for i := 0; i < 20 && playTime > 0; i++ {
+		time.Sleep(500 * time.Millisecond)
+
+		if GetMPV().Duration() > 0 {
+			GetMPV().Call("seek", playTime, "absolute")
+			break
+		}
+	}
+
+	log.Println("Restored the queue after restarting MPV")
+}
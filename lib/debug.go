@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// debugAddress is the address at which the pprof handlers are served.
+const debugAddress = "localhost:6060"
+
+// SetupDebug opens the log file, and starts the pprof
+// server if --debug is set.
+func SetupDebug() error {
+	if err := setupLog(); err != nil {
+		return err
+	}
+
+	if !debugMode {
+		return nil
+	}
+
+	logInfo("debug mode started")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	// Like the other local servers, requests which are not addressed
+	// to localhost are refused, to prevent DNS rebinding.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !localRequest(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+
+	go func() {
+		if err := http.ListenAndServe(debugAddress, handler); err != nil {
+			LogError("pprof server failed", "error", err)
+		}
+	}()
+
+	return nil
+}
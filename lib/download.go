@@ -6,12 +6,14 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
 var downloadLock sync.Mutex
 
-// GetDownload gets the video's response body and the file name to be saved to.
+// GetDownload gets the video's response body and the file to be saved to.
+// If the file name is not an absolute path, the file is saved in the download directory.
 func GetDownload(id, itag, filename string, ctx context.Context) (*http.Response, *os.File, error) {
 	var authToken []string
 
@@ -20,21 +22,28 @@ func GetDownload(id, itag, filename string, ctx context.Context) (*http.Response
 		authToken = append(authToken, token)
 	}
 
-	url, _ := url.Parse(getLatestURL(id, itag))
+	url, _ := url.Parse(getLatestURL(GetClient().host, id, itag))
 	param := url.RequestURI()
 	client := &Client{
 		host: GetClient().host,
 		client: &http.Client{
-			Transport: http.DefaultTransport,
+			Transport: clientTransport(),
 		},
 	}
 
 	res, err := client.GetRequest(ctx, param, authToken...)
 	if err != nil {
+		LogError("download failed", "id", id, "itag", itag, "error", err)
 		return nil, nil, err
 	}
 
-	file, err := os.OpenFile(filepath.Join(DownloadFolder(), filename), os.O_CREATE|os.O_WRONLY, 0644)
+	logInfo("download started", "id", id, "itag", itag, "file", filename, "size", res.ContentLength)
+
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(DownloadFolder(), filename)
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -49,3 +58,62 @@ func DownloadFolder() string {
 
 	return downloadFolder
 }
+
+// DownloadDir returns the directory for audio or video downloads, which
+// is the directory set for the download type, or the download directory.
+// If neither is set, the directory set for the other download type is
+// used, so that downloads are not saved in the working directory.
+func DownloadDir(audio bool) string {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+
+	dirs := []string{videoDlFolder, downloadFolder, audioDlFolder}
+	if audio {
+		dirs = []string{audioDlFolder, downloadFolder, videoDlFolder}
+	}
+
+	for _, dir := range dirs {
+		if dir != "" {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+// DownloadFilename returns the file name of an audio or video download,
+// from the filename template which is set for the download type.
+func DownloadFilename(audio bool, title, author, id, ext string) string {
+	template := videoDlTemplate
+	if audio {
+		template = audioDlTemplate
+	}
+
+	clean := func(s string) string {
+		return strings.NewReplacer("/", "_", string(os.PathSeparator), "_").Replace(s)
+	}
+
+	return strings.NewReplacer(
+		"{title}", clean(title),
+		"{author}", clean(author),
+		"{id}", clean(id),
+		"{ext}", clean(ext),
+	).Replace(template)
+}
+
+// DownloadPath returns the absolute path of an audio or video download,
+// in the directory and with the filename template set for the download type.
+func DownloadPath(audio bool, title, author, id, ext string) string {
+	path := filepath.Join(DownloadDir(audio), DownloadFilename(audio, title, author, id, ext))
+
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return path
+}
+
+// IsAudioFormat returns whether the format only contains audio.
+func IsAudioFormat(format FormatData) bool {
+	return strings.HasPrefix(format.Type, "audio/")
+}
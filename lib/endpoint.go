@@ -0,0 +1,234 @@
+package lib
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// endpointOrigins are the origins of browser extensions, which
+// are the only web origins allowed to send requests.
+var endpointOrigins = []string{"chrome-extension://", "moz-extension://", "safari-web-extension://"}
+
+var (
+	endpointServer *http.Server
+	endpointToken  string
+	endpointLock   sync.Mutex
+)
+
+// StartEndpoint starts a HTTP server on localhost, which browser extensions
+// can use to queue or play videos. Requests are of the form:
+//
+//	http://127.0.0.1:<port>/?url=<url>&token=<token>
+//	http://127.0.0.1:<port>/queue?url=<url>&audio=1&token=<token>
+//	http://127.0.0.1:<port>/play?url=<url>&token=<token>
+//
+// The token can also be sent as a bearer token in the Authorization header.
+// Requests must be addressed to localhost, and requests from web pages
+// other than browser extensions are refused, so that websites cannot
+// control the player.
+//
+// Metrics in the Prometheus text format are served at /metrics.
+//
+// The server is started only if --listen-port is set.
+func StartEndpoint() error {
+	if listenPort <= 0 {
+		return nil
+	}
+
+	token, err := loadEndpointToken()
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", endpointHandler("queue"))
+	mux.HandleFunc("/queue", endpointHandler("queue"))
+	mux.HandleFunc("/play", endpointHandler("play"))
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		if !localRequest(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		metricsHandler(w, r)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(listenPort))
+	if err != nil {
+		return fmt.Errorf("Cannot listen on port %d", listenPort)
+	}
+
+	server := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	endpointLock.Lock()
+	endpointServer = server
+	endpointToken = token
+	endpointLock.Unlock()
+
+	go server.Serve(listener)
+
+	return nil
+}
+
+// StopEndpoint stops the HTTP server.
+func StopEndpoint() {
+	endpointLock.Lock()
+	defer endpointLock.Unlock()
+
+	if endpointServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	endpointServer.Shutdown(ctx)
+	endpointServer = nil
+}
+
+// endpointHandler returns a handler which runs the control command
+// with the URL provided in the request.
+func endpointHandler(command string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var reply ControlReply
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet, http.MethodPost:
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !localRequest(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if !validEndpointToken(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		name := command
+		if audio, _ := strconv.ParseBool(r.FormValue("audio")); audio {
+			name += "-audio"
+		}
+
+		uri := r.FormValue("url")
+		if uri == "" {
+			reply.Error = "No URL specified"
+		} else {
+			reply = runControl([]string{name, uri})
+		}
+
+		if !reply.OK {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+
+		json.NewEncoder(w).Encode(reply)
+	}
+}
+
+// localRequest returns whether the request is addressed to localhost, which
+// prevents DNS rebinding, and whether it is not sent from a web page other
+// than a browser extension.
+func localRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+
+	switch host {
+	case "127.0.0.1", "localhost", "::1":
+
+	default:
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, prefix := range endpointOrigins {
+		if strings.HasPrefix(origin, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validEndpointToken returns whether the request contains the token,
+// either as the token parameter or as a bearer token.
+func validEndpointToken(r *http.Request) bool {
+	endpointLock.Lock()
+	token := endpointToken
+	endpointLock.Unlock()
+
+	sent := r.FormValue("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		sent = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	return token != "" && subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1
+}
+
+// loadEndpointToken returns the token set with --listen-token. If it is not
+// set, the token saved in the data directory is returned, and a new token
+// is generated and saved if there is none.
+func loadEndpointToken() (string, error) {
+	if listenToken != "" {
+		return listenToken, nil
+	}
+
+	tokenFile, err := DataPath("listen-token")
+	if err != nil {
+		return "", err
+	}
+
+	data, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("Cannot read the listen-port token")
+	}
+
+	if token := strings.TrimSpace(string(data)); token != "" {
+		return token, nil
+	}
+
+	value := make([]byte, 16)
+	if _, err := rand.Read(value); err != nil {
+		return "", fmt.Errorf("Cannot generate a token for the listen-port")
+	}
+
+	token := hex.EncodeToString(value)
+
+	// The file is created by DataPath with the default permissions,
+	// which may allow other users to read the token.
+	os.Chmod(tokenFile, 0600)
+
+	if err := ioutil.WriteFile(tokenFile, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("Cannot save the listen-port token")
+	}
+
+	return token, nil
+}
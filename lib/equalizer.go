@@ -0,0 +1,189 @@
+package lib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// equalizerLabel is the label of the equalizer filter in mpv's
+// filter chain, so that it can be replaced without affecting
+// other audio filters.
+const equalizerLabel = "@invidtui-eq"
+
+// EqualizerGainLimit is the maximum gain of a band, in dB.
+const EqualizerGainLimit = 12
+
+// EqualizerBands lists the center frequencies of the equalizer bands, in Hz.
+var EqualizerBands = []int{31, 62, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+
+// EqualizerPresets lists the names of the equalizer presets, in the order
+// they are shown. The custom preset uses the gains set with the band editor.
+var EqualizerPresets = []string{"flat", "bass", "vocal", "treble", "custom"}
+
+// equalizerGains stores the gains of each band for the presets.
+var equalizerGains = map[string][]float64{
+	"flat":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	"bass":   {6, 5, 4, 2, 0, 0, 0, 0, 0, 0},
+	"vocal":  {-2, -2, -1, 0, 2, 4, 4, 2, 0, -1},
+	"treble": {0, 0, 0, 0, 0, 0, 2, 4, 5, 6},
+}
+
+var equalizerLock sync.Mutex
+
+// Equalizer returns the selected equalizer preset, and the gains of its bands.
+// If an mpv profile is selected, the preset set for the profile is returned.
+func Equalizer() (string, []float64) {
+	equalizerLock.Lock()
+	defer equalizerLock.Unlock()
+
+	preset := profileOption("equalizer", equalizerPreset)
+
+	return preset, presetGains(preset)
+}
+
+// SetEqualizer applies the equalizer preset, and saves it to the config file,
+// for the selected mpv profile if any. The gains are only used and saved for
+// the custom preset.
+func SetEqualizer(preset string, gains []float64) error {
+	if _, ok := equalizerGains[preset]; !ok && preset != "custom" {
+		return fmt.Errorf("%s is not a valid equalizer preset", preset)
+	}
+
+	if preset != "custom" {
+		gains = equalizerGains[preset]
+	} else if err := checkEqualizerGains(gains); err != nil {
+		return err
+	}
+
+	if err := GetMPV().SetAudioEqualizer(gains); err != nil {
+		return err
+	}
+
+	equalizerLock.Lock()
+	defer equalizerLock.Unlock()
+
+	if preset == "custom" {
+		if err := saveProfileOption("equalizer-bands", formatEqualizerGains(gains)); err != nil {
+			return err
+		}
+	}
+
+	return saveProfileOption("equalizer", preset)
+}
+
+// SetAudioEqualizer replaces the equalizer in mpv's audio filter chain
+// with the gains of each band. The equalizer is removed if all gains are 0.
+func (c *Connector) SetAudioEqualizer(gains []float64) error {
+	var filters []string
+
+	for i, gain := range gains {
+		if gain == 0 || i >= len(EqualizerBands) {
+			continue
+		}
+
+		filters = append(filters, fmt.Sprintf(
+			"equalizer=f=%d:t=o:w=1:g=%s",
+			EqualizerBands[i], strconv.FormatFloat(gain, 'f', -1, 64),
+		))
+	}
+
+	// The filter is not present if the equalizer is flat,
+	// so the error of the remove command is ignored.
+	c.Call("af", "remove", equalizerLabel)
+
+	if filters == nil {
+		return nil
+	}
+
+	if _, err := c.Call("af", "add", equalizerLabel+":lavfi=["+strings.Join(filters, ",")+"]"); err != nil {
+		return fmt.Errorf("Could not set the equalizer")
+	}
+
+	return nil
+}
+
+// applyEqualizer applies the selected equalizer preset to
+// a newly connected mpv instance.
+func applyEqualizer(c *Connector) {
+	_, gains := Equalizer()
+
+	if err := c.SetAudioEqualizer(gains); err != nil {
+		LogError("equalizer could not be applied", "error", err)
+	}
+}
+
+// EqualizerPresetGains returns the gains of the preset's bands. The gains
+// of the custom preset are the ones saved in the config file.
+func EqualizerPresetGains(preset string) []float64 {
+	equalizerLock.Lock()
+	defer equalizerLock.Unlock()
+
+	return presetGains(preset)
+}
+
+// presetGains returns the gains of the preset's bands.
+// It must be called with equalizerLock held.
+func presetGains(preset string) []float64 {
+	if gains, ok := equalizerGains[preset]; ok {
+		return append([]float64{}, gains...)
+	}
+
+	gains, err := parseEqualizerGains(profileOption("equalizer-bands", equalizerBands))
+	if err != nil {
+		return append([]float64{}, equalizerGains["flat"]...)
+	}
+
+	return gains
+}
+
+// parseEqualizerGains parses a comma-separated list of gains
+// for each band, as set with --equalizer-bands.
+func parseEqualizerGains(text string) ([]float64, error) {
+	gains := make([]float64, len(EqualizerBands))
+	if text == "" {
+		return gains, nil
+	}
+
+	values := strings.Split(text, ",")
+	if len(values) != len(EqualizerBands) {
+		return nil, fmt.Errorf("equalizer-bands: %d gains are required", len(EqualizerBands))
+	}
+
+	for i, value := range values {
+		gain, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("equalizer-bands: %s is not a valid gain", value)
+		}
+
+		gains[i] = gain
+	}
+
+	return gains, checkEqualizerGains(gains)
+}
+
+// checkEqualizerGains checks whether the gains of each band are within the limits.
+func checkEqualizerGains(gains []float64) error {
+	if len(gains) != len(EqualizerBands) {
+		return fmt.Errorf("equalizer-bands: %d gains are required", len(EqualizerBands))
+	}
+
+	for _, gain := range gains {
+		if gain < -EqualizerGainLimit || gain > EqualizerGainLimit {
+			return fmt.Errorf("equalizer-bands: gains must be between -%d and %d dB", EqualizerGainLimit, EqualizerGainLimit)
+		}
+	}
+
+	return nil
+}
+
+// formatEqualizerGains returns the gains as written in the config file.
+func formatEqualizerGains(gains []float64) string {
+	values := make([]string, len(gains))
+	for i, gain := range gains {
+		values[i] = strconv.FormatFloat(gain, 'f', -1, 64)
+	}
+
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,215 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// fallbackInstances is the maximum number of other instances
+// from which a video which failed to load is requested.
+const fallbackInstances = 5
+
+// ageRestrictedErrors are parts of the error messages sent by
+// instances for videos which are age-restricted.
+var ageRestrictedErrors = []string{
+	"confirm your age",
+	"age-restricted",
+	"age restricted",
+	"inappropriate for some users",
+}
+
+// geoBlockedErrors are parts of the error messages sent by instances
+// for videos which are not available in the instance's region.
+var geoBlockedErrors = []string{
+	"not available in your country",
+	"blocked it in your country",
+	"not made this video available in your country",
+}
+
+// errAgeRestricted is returned when an age-restricted video
+// could not be loaded from any instance.
+var errAgeRestricted = errors.New("Video is age-restricted")
+
+var (
+	// FallbackStatus is a channel to receive messages about
+	// videos which were loaded from other instances.
+	FallbackStatus = make(chan string, 10)
+
+	ageRestricted     = make(map[string]struct{})
+	ageRestrictedLock sync.Mutex
+)
+
+// IsAgeRestricted returns whether the video with the given ID
+// was found to be age-restricted.
+func IsAgeRestricted(id string) bool {
+	ageRestrictedLock.Lock()
+	defer ageRestrictedLock.Unlock()
+
+	_, ok := ageRestricted[id]
+
+	return ok
+}
+
+// resolveVideo gets the video with the given ID from the current instance.
+// If the video is age-restricted or geo-blocked on the current instance,
+// it is requested from other instances. If an age-restricted video cannot
+// be served by any of them, errAgeRestricted is returned.
+func resolveVideo(id string) (VideoResult, error) {
+	video, err := GetClient().Video(id)
+	if err == nil {
+		return video, nil
+	}
+
+	switch {
+	case isGeoBlocked(err):
+		logInfo("video is geo-blocked", "video", id, "instance", GetClient().SelectedInstance())
+
+		video, instance, ferr := otherInstanceVideo(id)
+		if ferr != nil {
+			return VideoResult{}, err
+		}
+
+		sendFallbackStatus("Loaded " + video.Title + " from " + instance)
+
+		return video, nil
+
+	case isAgeRestricted(err) && !restrictedActive():
+		ageRestrictedLock.Lock()
+		ageRestricted[id] = struct{}{}
+		ageRestrictedLock.Unlock()
+
+		logInfo("video is age-restricted", "video", id, "instance", GetClient().SelectedInstance())
+
+		video, instance, ferr := otherInstanceVideo(id)
+		if ferr != nil {
+			return VideoResult{}, errAgeRestricted
+		}
+
+		sendFallbackStatus("Loaded " + video.Title + " from " + instance)
+
+		return video, nil
+	}
+
+	return VideoResult{}, err
+}
+
+// otherInstanceVideo requests the video with the given ID from instances
+// other than the current one, and returns the video along with the
+// instance which served it.
+func otherInstanceVideo(id string) (VideoResult, string, error) {
+	instances, err := GetInstanceList()
+	if err != nil {
+		return VideoResult{}, "", err
+	}
+
+	current := GetClient().SelectedInstance()
+	tried := 0
+
+	for _, instance := range instances {
+		if instance == current {
+			continue
+		}
+
+		if tried == fallbackInstances {
+			break
+		}
+		tried++
+
+		video, err := NewClient("https://" + instance).Video(id)
+		if err == nil {
+			logInfo("loaded video from fallback instance", "video", id, "instance", instance)
+
+			return video, instance, nil
+		}
+
+		logDebug("fallback instance failed", "video", id, "instance", instance, "error", err)
+	}
+
+	return VideoResult{}, "", fmt.Errorf("No other instance could load the video")
+}
+
+// checkStreamHost checks whether the stream URLs of the video point to the
+// instance which served it, since another instance, like the one which
+// refused a geo-blocked video, cannot serve its streams.
+func checkStreamHost(video VideoResult, uris ...string) error {
+	host := GetHostname(videoHost(video))
+
+	for _, uri := range uris {
+		if uri == "" {
+			continue
+		}
+
+		if streamHost := GetHostname(uri); streamHost != host {
+			logWarn("stream is not served by the video's instance", "video", video.VideoID, "instance", host, "stream", streamHost)
+
+			return fmt.Errorf("Could not load the streams of %s from %s", video.Title, host)
+		}
+	}
+
+	return nil
+}
+
+// loadYoutubeDL loads the video with the given ID from Youtube, which mpv
+// resolves with youtube-dl. It is used for age-restricted videos which
+// cannot be loaded from any instance.
+func loadYoutubeDL(id string, audio bool) (string, error) {
+	var options string
+
+	mtype := "Video"
+	if audio {
+		mtype = "Audio"
+		options = "vid=no,ytdl-format=bestaudio/best"
+	}
+
+	uri := "https://www.youtube.com/watch?v=" + url.QueryEscape(id)
+	uri += "&videoid=" + url.QueryEscape(id)
+	uri += "&mediatype=" + mtype
+
+	_, err := GetMPV().Call("loadfile", uri, "append-play", options)
+	if err != nil {
+		return "", fmt.Errorf("Unable to load %s", id)
+	}
+
+	addToMonitor(id)
+
+	logInfo("loaded age-restricted video with youtube-dl", "video", id)
+
+	return id, nil
+}
+
+// sendFallbackStatus sends a message to FallbackStatus.
+func sendFallbackStatus(msg string) {
+	select {
+	case FallbackStatus <- msg:
+	default:
+	}
+}
+
+// isAgeRestricted returns whether an error sent by an
+// instance is due to the video being age-restricted.
+func isAgeRestricted(err error) bool {
+	return errorContains(err, ageRestrictedErrors)
+}
+
+// isGeoBlocked returns whether an error sent by an instance is
+// due to the video being unavailable in the instance's region.
+func isGeoBlocked(err error) bool {
+	return errorContains(err, geoBlockedErrors)
+}
+
+// errorContains returns whether the error message
+// contains any of the given messages.
+func errorContains(err error, msgs []string) bool {
+	text := strings.ToLower(err.Error())
+
+	for _, msg := range msgs {
+		if strings.Contains(text, msg) {
+			return true
+		}
+	}
+
+	return false
+}
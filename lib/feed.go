@@ -22,6 +22,13 @@ type FeedVideos struct {
 	AuthorURL     string `json:"authorUrl"`
 	PublishedText string `json:"publishedText"`
 	ViewCount     int64  `json:"viewCount"`
+
+	IsUpcoming        bool  `json:"isUpcoming"`
+	PremiereTimestamp int64 `json:"premiereTimestamp"`
+
+	LiveNow        bool `json:"liveNow"`
+	AuthorVerified bool `json:"authorVerified"`
+	Premium        bool `json:"premium"`
 }
 
 var (
@@ -39,8 +46,11 @@ func (c *Client) Feed(getmore bool) (FeedResult, error) {
 		resetFeedPage()
 	}
 
+	ctx, done := JobStart("feed")
+	defer done()
+
 	query := "auth/feed?hl=en&page=" + getFeedPage()
-	res, err := c.ClientRequest(ClientCtx(), query, GetToken())
+	res, err := c.ClientRequest(ctx, query, GetToken())
 	if err != nil {
 		return FeedResult{}, err
 	}
@@ -51,6 +61,10 @@ func (c *Client) Feed(getmore bool) (FeedResult, error) {
 		return FeedResult{}, err
 	}
 
+	if !getmore {
+		SendWebhook("feed-refreshed", map[string]int{"videos": len(result.Videos)})
+	}
+
 	return result, nil
 }
 
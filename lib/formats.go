@@ -0,0 +1,166 @@
+package lib
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// preferredAudio returns the audio format with the highest bitrate that
+// does not exceed the --audio-bitrate setting. If every format exceeds it,
+// the format with the lowest bitrate is returned.
+func preferredAudio(formats []FormatData) (FormatData, bool) {
+	var selected FormatData
+
+	limit := int64(audioBitrate) * 1000
+
+	for _, format := range formats {
+		if !strings.HasPrefix(format.Type, "audio/") || format.Container == "" {
+			continue
+		}
+
+		switch {
+		case selected.Itag == "":
+			selected = format
+
+		case limit > 0 && selected.Bitrate > limit:
+			if format.Bitrate < selected.Bitrate {
+				selected = format
+			}
+
+		case limit > 0 && format.Bitrate > limit:
+			continue
+
+		case format.Bitrate > selected.Bitrate:
+			selected = format
+		}
+	}
+
+	return selected, selected.Itag != ""
+}
+
+// preferredVideo returns the video format with the highest resolution that
+// does not exceed the --video-res setting, preferring formats which match
+// the --video-codec setting. If every format exceeds the resolution, the
+// format with the lowest resolution is returned.
+func preferredVideo(formats []FormatData) (FormatData, bool) {
+	var selected FormatData
+
+	limit := formatHeight(videoResolution)
+
+	better := func(format FormatData) bool {
+		height, selectedHeight := formatHeight(format.Resolution), formatHeight(selected.Resolution)
+
+		if (height > limit) != (selectedHeight > limit) {
+			return height <= limit
+		}
+
+		if height != selectedHeight {
+			if height > limit {
+				return height < selectedHeight
+			}
+
+			return height > selectedHeight
+		}
+
+		if match, selectedMatch := matchesCodec(format), matchesCodec(selected); match != selectedMatch {
+			return match
+		}
+
+		return format.Bitrate > selected.Bitrate
+	}
+
+	for _, format := range formats {
+		if !strings.HasPrefix(format.Type, "video/") || format.Resolution == "" {
+			continue
+		}
+
+		if selected.Itag == "" || better(format) {
+			selected = format
+		}
+	}
+
+	return selected, selected.Itag != ""
+}
+
+// matchesCodec returns whether the format matches the --video-codec setting.
+func matchesCodec(format FormatData) bool {
+	return videoCodec == "" || strings.Contains(format.Type, videoCodec)
+}
+
+// formatHeight returns the height from a resolution, for example 720 from "720p60".
+func formatHeight(resolution string) int {
+	if pos := strings.IndexFunc(resolution, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	}); pos >= 0 {
+		resolution = resolution[:pos]
+	}
+
+	height, _ := strconv.Atoi(resolution)
+
+	return height
+}
+
+// MatchFormat returns the format of the video which is closest to the
+// given format of another video, so that a format selected for one video
+// can be applied to others. The format with the same itag is returned if
+// the video has it, otherwise the format of the same type with the closest
+// resolution or bitrate, preferring formats with the same container.
+func MatchFormat(source, video VideoResult, format FormatData) (FormatData, bool) {
+	var selected FormatData
+	var formats []FormatData
+
+	audio := IsAudioFormat(format)
+	muxed := isMuxedFormat(source, format)
+
+	if muxed {
+		formats = video.FormatStreams
+	} else {
+		formats = video.AdaptiveFormats
+	}
+
+	distance := func(f FormatData) int64 {
+		if audio {
+			return abs64(f.Bitrate - format.Bitrate)
+		}
+
+		return abs64(int64(formatHeight(f.Resolution) - formatHeight(format.Resolution)))
+	}
+
+	better := func(f FormatData) bool {
+		if d, selectedDistance := distance(f), distance(selected); d != selectedDistance {
+			return d < selectedDistance
+		}
+
+		if match, selectedMatch := f.Container == format.Container, selected.Container == format.Container; match != selectedMatch {
+			return match
+		}
+
+		return abs64(int64(f.FPS-format.FPS)) < abs64(int64(selected.FPS-format.FPS))
+	}
+
+	for _, f := range formats {
+		if f.Itag == format.Itag {
+			return f, true
+		}
+
+		if IsAudioFormat(f) != audio || f.Container == "" || (!audio && f.Resolution == "") {
+			continue
+		}
+
+		if selected.Itag == "" || better(f) {
+			selected = f
+		}
+	}
+
+	return selected, selected.Itag != ""
+}
+
+// abs64 returns the absolute value of n.
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -17,7 +17,7 @@ var (
 func SetupHistory() {
 	var err error
 
-	historyFile, err = ConfigPath("history")
+	historyFile, err = DataPath("history")
 	if err != nil {
 		return
 	}
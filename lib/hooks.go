@@ -0,0 +1,185 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hookTimeout is the maximum time a hook is allowed to run.
+const hookTimeout = 10 * time.Second
+
+var (
+	// entryColumns stores the text shown in the custom column of
+	// each list entry, by the ID of the entry.
+	entryColumns     = make(map[string]string)
+	entryColumnsLock sync.Mutex
+)
+
+// HookPath returns the path to the hook with the given name, if
+// it exists. Hooks are executables placed in the "hooks" directory
+// within the config directory.
+func HookPath(name string) (string, bool) {
+	path := filepath.Join(configPath, "hooks", name)
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	return path, true
+}
+
+// RunHook runs the hook with the given name, writes the input to it as JSON,
+// and returns its output. If the hook does not exist, nothing is returned.
+func RunHook(name string, input interface{}) ([]byte, error) {
+	path, ok := HookPath(name)
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("Hook %s failed: %s", name, msg)
+		}
+
+		return nil, fmt.Errorf("Hook %s failed: %s", name, err.Error())
+	}
+
+	return output, nil
+}
+
+// runTrackHook runs the on_track_start hook for the track, with the path
+// to the thumbnail of the track added to its data as "thumbnail".
+func runTrackHook(track map[string]string) {
+	if _, ok := HookPath("on_track_start"); !ok {
+		return
+	}
+
+	data := make(map[string]string, len(track)+1)
+	for key, value := range track {
+		data[key] = value
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	if path, err := Thumbnail(ctx, track["videoid"]); err == nil {
+		data["thumbnail"] = path
+	}
+
+	RunHook("on_track_start", data)
+}
+
+// RunKeyHook runs the hook for a key, with the selected entry as input.
+// Each line of the hook's output is run as a control command (see --send).
+func RunKeyHook(name string, info SearchResult) error {
+	output, err := RunHook(name, info)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		reply := runControl(strings.Fields(scanner.Text()))
+		if !reply.OK && reply.Error != "No command specified" {
+			return errors.New(reply.Error)
+		}
+	}
+
+	return nil
+}
+
+// searchResultsHook passes the search results to the on_search_results
+// hook, which can filter or reorder them by printing the modified results.
+// If the hook fails or does not exist, the results are returned unchanged.
+func searchResultsHook(results []SearchResult) []SearchResult {
+	var modified []SearchResult
+
+	output, err := RunHook("on_search_results", results)
+	if err != nil || len(bytes.TrimSpace(output)) == 0 {
+		return results
+	}
+
+	if err := json.Unmarshal(output, &modified); err != nil {
+		return results
+	}
+
+	return modified
+}
+
+// listColumnsHook passes the search results to the on_list_columns hook,
+// which can print a JSON object with the text of a custom column for each
+// result, by its video, playlist or channel ID. If reset is set, the text
+// for the previous results is cleared.
+func listColumnsHook(results []SearchResult, reset bool) {
+	var columns map[string]string
+
+	if _, ok := HookPath("on_list_columns"); !ok {
+		return
+	}
+
+	output, err := RunHook("on_list_columns", results)
+	if err != nil {
+		logWarn("list columns hook failed", "error", err)
+	}
+
+	json.Unmarshal(output, &columns)
+
+	entryColumnsLock.Lock()
+	defer entryColumnsLock.Unlock()
+
+	if reset {
+		entryColumns = make(map[string]string)
+	}
+
+	for id, text := range columns {
+		entryColumns[id] = text
+	}
+}
+
+// EntryColumn returns the text of the custom column for the entry,
+// which was set by the on_list_columns hook.
+func EntryColumn(entry SearchResult) string {
+	var id string
+
+	switch entry.Type {
+	case "video":
+		id = entry.VideoID
+
+	case "playlist":
+		id = entry.PlaylistID
+
+	case "channel":
+		id = entry.AuthorID
+	}
+
+	entryColumnsLock.Lock()
+	defer entryColumnsLock.Unlock()
+
+	return entryColumns[id]
+}
@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// job stores the context of a named job, and the number
+// of operations currently running within it.
+type job struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	running  int
+	canceled bool
+}
+
+var (
+	jobs     = make(map[string]*job)
+	jobsLock sync.Mutex
+)
+
+// JobRenew cancels the named job, and returns a new context for it.
+func JobRenew(name string) context.Context {
+	jobsLock.Lock()
+	defer jobsLock.Unlock()
+
+	return renewJob(name).ctx
+}
+
+// JobReset returns the context of the named job, and replaces the job only
+// if it was canceled. Unlike JobRenew, operations which are already running
+// within the job are not interrupted, so it is used for jobs like video loads
+// which may run concurrently.
+func JobReset(name string) context.Context {
+	jobsLock.Lock()
+	defer jobsLock.Unlock()
+
+	j, ok := jobs[name]
+	if !ok || j.canceled || j.ctx.Err() != nil {
+		j = renewJob(name)
+	}
+
+	return j.ctx
+}
+
+// JobStart renews the named job, and marks it as active until the returned
+// function is called. It is used for jobs that replace their previous run.
+func JobStart(name string) (context.Context, func()) {
+	JobRenew(name)
+
+	return JobRun(name)
+}
+
+// JobCtx returns the context of the named job. A canceled job's
+// context stays canceled until the job is renewed.
+func JobCtx(name string) context.Context {
+	jobsLock.Lock()
+	defer jobsLock.Unlock()
+
+	j, ok := jobs[name]
+	if !ok {
+		j = renewJob(name)
+	}
+
+	return j.ctx
+}
+
+// JobRun returns the context of the named job, and marks the job as active
+// until the returned function is called. Active jobs are shown in the status bar.
+func JobRun(name string) (context.Context, func()) {
+	var once sync.Once
+
+	jobsLock.Lock()
+	defer jobsLock.Unlock()
+
+	j, ok := jobs[name]
+	if !ok {
+		j = renewJob(name)
+	}
+
+	j.running++
+
+	return j.ctx, func() {
+		once.Do(func() {
+			jobsLock.Lock()
+			defer jobsLock.Unlock()
+
+			j.running--
+		})
+	}
+}
+
+// JobCancel cancels the named job.
+func JobCancel(name string) {
+	jobsLock.Lock()
+	defer jobsLock.Unlock()
+
+	if j, ok := jobs[name]; ok {
+		j.cancel()
+		j.canceled = true
+	}
+}
+
+// JobCancelAll cancels all jobs.
+func JobCancelAll() {
+	jobsLock.Lock()
+	defer jobsLock.Unlock()
+
+	for _, j := range jobs {
+		j.cancel()
+		j.canceled = true
+	}
+}
+
+// ActiveJobs returns the sorted names of the jobs which are running.
+func ActiveJobs() []string {
+	var names []string
+
+	jobsLock.Lock()
+	defer jobsLock.Unlock()
+
+	for name, j := range jobs {
+		if j.running > 0 && !j.canceled {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// renewJob cancels the named job if it exists, and replaces it.
+// It must be called with jobsLock held.
+func renewJob(name string) *job {
+	if j, ok := jobs[name]; ok {
+		j.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{ctx: ctx, cancel: cancel}
+
+	jobs[name] = j
+
+	return j
+}
@@ -0,0 +1,370 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The live chat is polled from the instance's livechat endpoint. If the
+// instance does not serve it and --youtube-live-chat is set, it is polled
+// from Youtube's live chat page and its continuation API instead, which
+// sends the requests directly to Youtube.
+const (
+	liveChatPage = "https://www.youtube.com/live_chat?is_popout=1&v="
+	liveChatAPI  = "https://www.youtube.com/youtubei/v1/live_chat/get_live_chat?prettyPrint=false"
+
+	// liveChatClientVersion is the version of the web client which is sent
+	// to the API, if it cannot be found in the live chat page.
+	liveChatClientVersion = "2.20240101.00.00"
+
+	// liveChatMinInterval is the minimum interval at which the live chat
+	// is polled, regardless of the interval requested by the API.
+	liveChatMinInterval = time.Second
+
+	// liveChatPollInterval is the interval at which the
+	// instance's livechat endpoint is polled.
+	liveChatPollInterval = 5 * time.Second
+)
+
+var (
+	liveChatContinuationRegex = regexp.MustCompile(`"continuation":"([^"]+)"`)
+	liveChatVersionRegex      = regexp.MustCompile(`"INNERTUBE_CONTEXT_CLIENT_VERSION":"([^"]+)"`)
+)
+
+var (
+	// ErrLiveChatUnavailable is returned if the instance does not serve
+	// live chat, or if the live stream has no live chat.
+	ErrLiveChatUnavailable = errors.New("Live chat is not available for this stream on this instance")
+
+	// ErrLiveChatEnded is returned if the live chat has ended.
+	ErrLiveChatEnded = errors.New("Live chat has ended")
+)
+
+// LiveChat stores the state of the live chat of a live stream.
+type LiveChat struct {
+	id            string
+	continuation  string
+	clientVersion string
+
+	youtube bool
+	client  *http.Client
+}
+
+// LiveChatMessage stores a live chat message.
+type LiveChatMessage struct {
+	Author  string
+	Message string
+	Amount  string
+	Time    time.Time
+}
+
+// liveChatResult stores the response of the instance's livechat endpoint.
+type liveChatResult struct {
+	Messages []struct {
+		Author    string `json:"author"`
+		Message   string `json:"message"`
+		Amount    string `json:"amount"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"messages"`
+	Continuation string `json:"continuation"`
+}
+
+// liveChatResponse stores the response of the live chat API.
+type liveChatResponse struct {
+	ContinuationContents *struct {
+		LiveChatContinuation struct {
+			Continuations []struct {
+				Invalidation *liveChatContinuation `json:"invalidationContinuationData"`
+				Timed        *liveChatContinuation `json:"timedContinuationData"`
+				Reload       *liveChatContinuation `json:"reloadContinuationData"`
+			} `json:"continuations"`
+
+			Actions []struct {
+				AddChatItemAction struct {
+					Item struct {
+						Text *liveChatRenderer `json:"liveChatTextMessageRenderer"`
+						Paid *liveChatRenderer `json:"liveChatPaidMessageRenderer"`
+					} `json:"item"`
+				} `json:"addChatItemAction"`
+			} `json:"actions"`
+		} `json:"liveChatContinuation"`
+	} `json:"continuationContents"`
+}
+
+// liveChatContinuation stores the continuation for the next
+// request, and the time to wait before sending it.
+type liveChatContinuation struct {
+	Continuation string `json:"continuation"`
+	TimeoutMs    int    `json:"timeoutMs"`
+}
+
+// liveChatRenderer stores a message in the live chat API response.
+type liveChatRenderer struct {
+	AuthorName struct {
+		SimpleText string `json:"simpleText"`
+	} `json:"authorName"`
+
+	Message struct {
+		Runs []struct {
+			Text  string `json:"text"`
+			Emoji *struct {
+				EmojiID   string   `json:"emojiId"`
+				Shortcuts []string `json:"shortcuts"`
+			} `json:"emoji"`
+		} `json:"runs"`
+	} `json:"message"`
+
+	PurchaseAmountText struct {
+		SimpleText string `json:"simpleText"`
+	} `json:"purchaseAmountText"`
+
+	TimestampUsec string `json:"timestampUsec"`
+}
+
+// NewLiveChat returns the live chat of the live stream with the given ID.
+func NewLiveChat(id string) *LiveChat {
+	return &LiveChat{
+		id:            id,
+		clientVersion: liveChatClientVersion,
+	}
+}
+
+// startYoutube switches the live chat to be polled from Youtube. The live
+// chat page is requested to get the continuation for the first poll.
+func (l *LiveChat) startYoutube(ctx context.Context) error {
+	l.client = &http.Client{
+		Timeout:   time.Duration(requestTimeout) * time.Second,
+		Transport: clientTransport(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, liveChatPage+url.QueryEscape(l.id), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := l.send(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	page, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	match := liveChatContinuationRegex.FindSubmatch(page)
+	if match == nil {
+		return ErrLiveChatUnavailable
+	}
+
+	l.youtube = true
+	l.continuation = string(match[1])
+
+	if match := liveChatVersionRegex.FindSubmatch(page); match != nil {
+		l.clientVersion = string(match[1])
+	}
+
+	return nil
+}
+
+// Messages returns the messages which were sent since the previous call,
+// and the time to wait before the live chat is polled again.
+func (l *LiveChat) Messages(ctx context.Context) ([]LiveChatMessage, time.Duration, error) {
+	if l.youtube {
+		return l.youtubeMessages(ctx)
+	}
+
+	var result liveChatResult
+	var messages []LiveChatMessage
+
+	query := "livechat/" + url.PathEscape(l.id) + "?hl=en"
+	if l.continuation != "" {
+		query += "&continuation=" + url.QueryEscape(l.continuation)
+	}
+
+	res, err := GetClient().SetRequest(ctx, http.MethodGet, api+query, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		res.Body.Close()
+
+		if !youtubeLiveChat || l.continuation != "" {
+			return nil, 0, ErrLiveChatUnavailable
+		}
+
+		if err := l.startYoutube(ctx); err != nil {
+			return nil, 0, err
+		}
+
+		return l.youtubeMessages(ctx)
+
+	case res.StatusCode != http.StatusOK:
+		return nil, 0, apiError(res)
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, 0, err
+	}
+
+	if result.Continuation != "" {
+		l.continuation = result.Continuation
+	}
+
+	for _, message := range result.Messages {
+		sent := time.Now()
+		if message.Timestamp > 0 {
+			sent = time.Unix(message.Timestamp, 0)
+		}
+
+		messages = append(messages, LiveChatMessage{
+			Author:  message.Author,
+			Message: message.Message,
+			Amount:  message.Amount,
+			Time:    sent,
+		})
+	}
+
+	return messages, liveChatPollInterval, nil
+}
+
+// youtubeMessages returns the messages which were sent since the previous
+// call from Youtube's live chat API, and the time to wait before it is
+// polled again.
+func (l *LiveChat) youtubeMessages(ctx context.Context) ([]LiveChatMessage, time.Duration, error) {
+	var response liveChatResponse
+	var messages []LiveChatMessage
+
+	body, err := json.Marshal(map[string]interface{}{
+		"context": map[string]interface{}{
+			"client": map[string]string{
+				"clientName":    "WEB",
+				"clientVersion": l.clientVersion,
+				"hl":            "en",
+			},
+		},
+		"continuation": l.continuation,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, liveChatAPI, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := l.send(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, 0, err
+	}
+
+	if response.ContinuationContents == nil {
+		return nil, 0, ErrLiveChatEnded
+	}
+
+	contents := response.ContinuationContents.LiveChatContinuation
+
+	interval := liveChatMinInterval
+	for _, c := range contents.Continuations {
+		for _, data := range []*liveChatContinuation{c.Invalidation, c.Timed, c.Reload} {
+			if data == nil || data.Continuation == "" {
+				continue
+			}
+
+			l.continuation = data.Continuation
+			if wait := time.Duration(data.TimeoutMs) * time.Millisecond; wait > interval {
+				interval = wait
+			}
+		}
+	}
+
+	for _, action := range contents.Actions {
+		item := action.AddChatItemAction.Item
+
+		renderer := item.Text
+		if renderer == nil {
+			renderer = item.Paid
+		}
+		if renderer == nil {
+			continue
+		}
+
+		messages = append(messages, renderer.message())
+	}
+
+	return messages, interval, nil
+}
+
+// send sends the request to Youtube, with the consent cookie
+// set so that the request is not redirected to the consent page.
+func (l *LiveChat) send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Language", "en")
+	req.AddCookie(&http.Cookie{Name: "SOCS", Value: "CAI"})
+
+	res, err := l.client.Do(req)
+	if err != nil {
+		logWarn("live chat request failed", "url", req.URL.String(), "error", err)
+		return nil, clientError(err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		logWarn("live chat request failed", "url", req.URL.String(), "status", res.StatusCode)
+
+		return nil, fmt.Errorf("Live chat request returned %d", res.StatusCode)
+	}
+
+	return res, nil
+}
+
+// message returns the live chat message from the renderer.
+func (r *liveChatRenderer) message() LiveChatMessage {
+	var text strings.Builder
+
+	for _, run := range r.Message.Runs {
+		switch {
+		case run.Emoji != nil && len(run.Emoji.Shortcuts) > 0:
+			text.WriteString(run.Emoji.Shortcuts[0])
+
+		case run.Emoji != nil:
+			text.WriteString(run.Emoji.EmojiID)
+
+		default:
+			text.WriteString(run.Text)
+		}
+	}
+
+	sent := time.Now()
+	if usec, err := strconv.ParseInt(r.TimestampUsec, 10, 64); err == nil {
+		sent = time.Unix(0, usec*int64(time.Microsecond))
+	}
+
+	return LiveChatMessage{
+		Author:  r.AuthorName.SimpleText,
+		Message: text.String(),
+		Amount:  r.PurchaseAmountText.SimpleText,
+		Time:    sent,
+	}
+}
@@ -0,0 +1,162 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logLevels lists the log levels, in increasing order of severity.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+const (
+	levelDebug = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+const (
+	// logMaxSize is the size at which the log file is rotated.
+	logMaxSize = 1 << 20
+
+	// logBackups is the number of rotated log files which are kept.
+	logBackups = 3
+)
+
+var (
+	logFile  *os.File
+	logPath  string
+	logSize  int64
+	logLevel int
+	logLock  sync.Mutex
+)
+
+// setupLog opens the log file in the data directory.
+func setupLog() error {
+	logLevel = levelInfo
+
+	for i, level := range logLevels {
+		if level == logLevelName {
+			logLevel = i
+		}
+	}
+
+	if debugMode {
+		logLevel = levelDebug
+	}
+
+	logPath = filepath.Join(dataPath, "invidtui.log")
+
+	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("Cannot open log file at %s", logPath)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("Cannot open log file at %s", logPath)
+	}
+
+	logFile, logSize = file, info.Size()
+
+	return nil
+}
+
+// LogError writes an error message to the log file.
+func LogError(msg string, fields ...interface{}) {
+	writeLog(levelError, msg, fields...)
+}
+
+// logDebug writes a debug message to the log file.
+func logDebug(msg string, fields ...interface{}) {
+	writeLog(levelDebug, msg, fields...)
+}
+
+// logInfo writes an info message to the log file.
+func logInfo(msg string, fields ...interface{}) {
+	writeLog(levelInfo, msg, fields...)
+}
+
+// logWarn writes a warning message to the log file.
+func logWarn(msg string, fields ...interface{}) {
+	writeLog(levelWarn, msg, fields...)
+}
+
+// writeLog writes a message with the given level to the log file, if
+// the level is not below --log-level. The fields are key-value pairs,
+// which are appended to the message as key=value.
+func writeLog(level int, msg string, fields ...interface{}) {
+	logLock.Lock()
+	defer logLock.Unlock()
+
+	if logFile == nil || level < logLevel {
+		return
+	}
+
+	var line strings.Builder
+
+	line.WriteString(time.Now().Format("2006-01-02T15:04:05.000Z07:00"))
+	line.WriteString(" level=" + logLevels[level])
+	line.WriteString(" msg=" + logValue(msg))
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		line.WriteString(fmt.Sprintf(" %v=%s", fields[i], logValue(fields[i+1])))
+	}
+
+	line.WriteString("\n")
+
+	if logSize+int64(line.Len()) > logMaxSize {
+		rotateLog()
+	}
+
+	n, _ := logFile.WriteString(line.String())
+	logSize += int64(n)
+}
+
+// rotateLog renames the log file and its backups, removing the oldest
+// backup, and opens a new log file. It must be called with logLock held.
+func rotateLog() {
+	logFile.Close()
+
+	for i := logBackups - 1; i > 0; i-- {
+		os.Rename(logPath+"."+strconv.Itoa(i), logPath+"."+strconv.Itoa(i+1))
+	}
+	os.Rename(logPath, logPath+".1")
+
+	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		logFile = nil
+		return
+	}
+
+	logFile, logSize = file, 0
+}
+
+// logValue formats a value for the log, quoting it if it is
+// empty or contains spaces, quotes or an equals sign.
+func logValue(value interface{}) string {
+	var s string
+
+	switch v := value.(type) {
+	case error:
+		s = v.Error()
+
+	case nil:
+		s = "<nil>"
+
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
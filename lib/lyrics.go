@@ -0,0 +1,130 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LyricsResult stores the lyrics data.
+type LyricsResult struct {
+	PlainLyrics  string `json:"plainLyrics"`
+	SyncedLyrics string `json:"syncedLyrics"`
+}
+
+// LyricLine stores a line of synced lyrics.
+type LyricLine struct {
+	Time float64
+	Text string
+}
+
+const (
+	lyricsHost = "https://lrclib.net"
+
+	// lyricsUserAgent identifies invidtui to LRCLIB, as its API requests.
+	lyricsUserAgent = "invidtui (https://github.com/darkhz/invidtui)"
+)
+
+var (
+	lrcRegex   = regexp.MustCompile(`^\[(\d+):(\d+(?:\.\d+)?)\](.*)$`)
+	titleRegex = regexp.MustCompile(`(?i)\s*[\(\[][^\)\]]*(official|lyric|video|audio|visualizer|hd|4k)[^\)\]]*[\)\]]`)
+)
+
+// Lyrics gets the lyrics for a track with the given title and author.
+// If the title is in the format "Artist - Track", the artist is taken
+// from the title, otherwise the author is used as the artist.
+func Lyrics(ctx context.Context, title, author string) (LyricsResult, error) {
+	var result []LyricsResult
+
+	artist, track := ParseTrack(title, author)
+
+	query := "/api/search?track_name=" + url.QueryEscape(track)
+	if artist != "" {
+		query += "&artist_name=" + url.QueryEscape(artist)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lyricsHost+query, nil)
+	if err != nil {
+		return LyricsResult{}, err
+	}
+	req.Header.Set("User-Agent", lyricsUserAgent)
+
+	// The lyrics are not fetched with an instance client, so that
+	// requests to LRCLIB do not affect the instance status and metrics.
+	client := &http.Client{
+		Timeout:   time.Duration(requestTimeout) * time.Second,
+		Transport: clientTransport(),
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return LyricsResult{}, clientError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return LyricsResult{}, fmt.Errorf("Lyrics request returned %d", res.StatusCode)
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return LyricsResult{}, err
+	}
+
+	for _, lyrics := range result {
+		if lyrics.SyncedLyrics != "" {
+			return lyrics, nil
+		}
+	}
+
+	if len(result) == 0 || result[0].PlainLyrics == "" {
+		return LyricsResult{}, fmt.Errorf("No lyrics found for %s", track)
+	}
+
+	return result[0], nil
+}
+
+// ParseTrack parses the artist and track name from a video title.
+func ParseTrack(title, author string) (string, string) {
+	artist := strings.TrimSuffix(author, " - Topic")
+
+	title = titleRegex.ReplaceAllString(title, "")
+	if split := strings.SplitN(title, " - ", 2); len(split) == 2 {
+		artist, title = split[0], split[1]
+	}
+
+	return strings.TrimSpace(artist), strings.TrimSpace(title)
+}
+
+// ParseSyncedLyrics parses lyrics in the LRC format.
+func ParseSyncedLyrics(lrc string) []LyricLine {
+	var lines []LyricLine
+
+	for _, line := range strings.Split(lrc, "\n") {
+		match := lrcRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		min, _ := strconv.Atoi(match[1])
+		sec, _ := strconv.ParseFloat(match[2], 64)
+
+		lines = append(lines, LyricLine{
+			Time: float64(min*60) + sec,
+			Text: strings.TrimSpace(match[3]),
+		})
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time < lines[j].Time
+	})
+
+	return lines
+}
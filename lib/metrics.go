@@ -0,0 +1,198 @@
+package lib
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// requestMetric stores the request metrics for an instance.
+type requestMetric struct {
+	count   int64
+	errors  int64
+	seconds float64
+}
+
+// cacheMetric stores the hits and misses of a cache.
+type cacheMetric struct {
+	hits   int64
+	misses int64
+}
+
+var (
+	requestMetrics = make(map[string]*requestMetric)
+	cacheMetrics   = make(map[string]*cacheMetric)
+
+	downloadsTotal  int64
+	downloadsFailed int64
+	downloadsActive int64
+	downloadBytes   int64
+
+	metricsLock sync.Mutex
+)
+
+// DownloadStarted records the start of a download.
+func DownloadStarted() {
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+
+	downloadsActive++
+}
+
+// DownloadFinished records the end of a download, and
+// the number of bytes that were downloaded.
+func DownloadFinished(size int64, err error) {
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+
+	downloadsActive--
+	downloadsTotal++
+	downloadBytes += size
+
+	if err != nil {
+		downloadsFailed++
+		LogError("download failed", "size", size, "error", err)
+
+		return
+	}
+
+	logInfo("download finished", "size", size)
+}
+
+// recordRequest records the duration and result of a request to an instance.
+func recordRequest(instance string, duration time.Duration, failed bool) {
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+
+	metric, ok := requestMetrics[instance]
+	if !ok {
+		metric = &requestMetric{}
+		requestMetrics[instance] = metric
+	}
+
+	metric.count++
+	metric.seconds += duration.Seconds()
+
+	if failed {
+		metric.errors++
+	}
+}
+
+// recordCache records a hit or a miss of the cache with the given name.
+func recordCache(name string, hit bool) {
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+
+	metric, ok := cacheMetrics[name]
+	if !ok {
+		metric = &cacheMetric{}
+		cacheMetrics[name] = metric
+	}
+
+	if hit {
+		metric.hits++
+	} else {
+		metric.misses++
+	}
+}
+
+// metricsHandler writes the metrics in the Prometheus text format.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	writeMetrics(w)
+}
+
+// writeMetrics writes the metrics in the Prometheus text format.
+func writeMetrics(w io.Writer) {
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+
+	instances := make([]string, 0, len(requestMetrics))
+	for instance := range requestMetrics {
+		instances = append(instances, instance)
+	}
+	sort.Strings(instances)
+
+	caches := make([]string, 0, len(cacheMetrics))
+	for name := range cacheMetrics {
+		caches = append(caches, name)
+	}
+	sort.Strings(caches)
+
+	metric := func(name, mtype, help string, values func(write func(labels, value string))) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, mtype)
+
+		values(func(labels, value string) {
+			fmt.Fprintf(w, "%s%s %s\n", name, labels, value)
+		})
+	}
+
+	perInstance := func(value func(m *requestMetric) string) func(write func(labels, value string)) {
+		return func(write func(labels, value string)) {
+			for _, instance := range instances {
+				write("{instance="+strconv.Quote(instance)+"}", value(requestMetrics[instance]))
+			}
+		}
+	}
+
+	perCache := func(value func(m *cacheMetric) int64) func(write func(labels, value string)) {
+		return func(write func(labels, value string)) {
+			for _, name := range caches {
+				write("{cache="+strconv.Quote(name)+"}", strconv.FormatInt(value(cacheMetrics[name]), 10))
+			}
+		}
+	}
+
+	single := func(value string) func(write func(labels, value string)) {
+		return func(write func(labels, value string)) {
+			write("", value)
+		}
+	}
+
+	metric(
+		"invidtui_api_requests_total", "counter",
+		"Total number of requests sent to each instance.",
+		perInstance(func(m *requestMetric) string { return strconv.FormatInt(m.count, 10) }),
+	)
+	metric(
+		"invidtui_api_request_errors_total", "counter",
+		"Total number of failed requests to each instance.",
+		perInstance(func(m *requestMetric) string { return strconv.FormatInt(m.errors, 10) }),
+	)
+	metric(
+		"invidtui_api_request_duration_seconds_total", "counter",
+		"Total time spent on requests to each instance.",
+		perInstance(func(m *requestMetric) string { return strconv.FormatFloat(m.seconds, 'f', -1, 64) }),
+	)
+
+	metric(
+		"invidtui_cache_hits_total", "counter",
+		"Total number of lookups which were served from each cache.",
+		perCache(func(m *cacheMetric) int64 { return m.hits }),
+	)
+	metric(
+		"invidtui_cache_misses_total", "counter",
+		"Total number of lookups which were not served from each cache.",
+		perCache(func(m *cacheMetric) int64 { return m.misses }),
+	)
+
+	online := "0"
+	if IsOnline() {
+		online = "1"
+	}
+
+	metric("invidtui_online", "gauge", "Whether the current instance is reachable.", single(online))
+	metric("invidtui_downloads_total", "counter", "Total number of finished downloads.",
+		single(strconv.FormatInt(downloadsTotal, 10)))
+	metric("invidtui_download_errors_total", "counter", "Total number of failed downloads.",
+		single(strconv.FormatInt(downloadsFailed, 10)))
+	metric("invidtui_downloads_active", "gauge", "Number of downloads in progress.",
+		single(strconv.FormatInt(downloadsActive, 10)))
+	metric("invidtui_download_bytes_total", "counter", "Total number of downloaded bytes.",
+		single(strconv.FormatInt(downloadBytes, 10)))
+}
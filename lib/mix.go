@@ -0,0 +1,55 @@
+package lib
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// mixResult stores the mix data.
+type mixResult struct {
+	Title  string          `json:"title"`
+	MixID  string          `json:"mixId"`
+	Videos []PlaylistVideo `json:"videos"`
+}
+
+// IsMix returns whether the playlist ID is the ID of a mix, which is
+// a playlist that Youtube generates from a video, channel or artist.
+func IsMix(id string) bool {
+	return strings.HasPrefix(id, "RD")
+}
+
+// mix gets the mix with the given ID and returns it as a PlaylistResult.
+// Mixes are not paginated, so all of their videos are returned at once.
+func (c *Client) mix(id string, onVideos func(PlaylistResult, []PlaylistVideo)) (PlaylistResult, error) {
+	var mix mixResult
+
+	ctx, done := JobStart("playlist")
+	defer done()
+
+	res, err := c.ClientRequest(ctx, "mixes/"+id+"?hl=en")
+	if err != nil {
+		return PlaylistResult{}, err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(&mix); err != nil {
+		return PlaylistResult{}, err
+	}
+
+	result := PlaylistResult{
+		Title:      mix.Title,
+		PlaylistID: mix.MixID,
+		Author:     "YouTube",
+		VideoCount: len(mix.Videos),
+	}
+
+	mix.Videos = filterRestrictedVideos(ctx, mix.Videos)
+
+	if onVideos != nil && len(mix.Videos) > 0 {
+		onVideos(result, mix.Videos)
+	}
+
+	result.Videos = mix.Videos
+
+	return result, nil
+}
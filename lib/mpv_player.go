@@ -2,10 +2,13 @@ package lib
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,27 +22,72 @@ type Connector struct {
 	conn *mpvipc.Connection
 }
 
+// AudioDevice stores an audio output device of mpv.
+type AudioDevice struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PlaylistEntry stores an entry of the mpv playlist.
+type PlaylistEntry struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Filename string `json:"filename"`
+	Current  bool   `json:"current"`
+	Playing  bool   `json:"playing"`
+}
+
+const (
+	minSpeed = 0.25
+	maxSpeed = 4.0
+)
+
+// speedPresets lists the playback speeds which are cycled through.
+var speedPresets = []float64{0.75, 1, 1.25, 1.5, 2}
+
+// liveOptions are the options with which live streams are loaded, so that
+// the stream is cached and can be seeked within the cached duration.
+const liveOptions = "cache=yes,force-seekable=yes"
+
 var (
 	loop   string
 	socket string
 	mpvcmd *exec.Cmd
 	mpvctl *Connector
 
+	mpvLock    sync.Mutex
+	mpvReady   chan struct{}
+	mpvErr     error
+	mpvStopped bool
+
+	// queueLock serializes adding entries to the queue, so that
+	// an entry which is appended and then moved to a position,
+	// for example when it is inserted or replaced, is not mixed
+	// up with an entry which is appended at the same time.
+	queueLock sync.Mutex
+
 	monitorMutex sync.Mutex
 	monitorMap   map[int]string
 	mpvInfoChan  chan int
-	mpvErrorChan chan int
+	mpvErrorChan chan PlaybackError
 
-	// MPVErrors is a channel to receive mpv error messages.
-	MPVErrors chan string
+	// MPVErrors is a channel to receive mpv playback errors.
+	MPVErrors chan PlaybackError
 
 	// MPVFileLoaded is a channel to receive file-loaded events.
 	MPVFileLoaded chan struct{}
 
+	// MPVResumed is a channel to receive the positions
+	// which videos were resumed at.
+	MPVResumed chan int64
+
 	//MPVPlaylistData is a channel to receive playlist data events.
-	MPVPlaylistData chan []map[string]interface{}
+	MPVPlaylistData chan []PlaylistEntry
 )
 
+// reconnectRetries is the number of retries for reconnecting to the socket.
+const reconnectRetries = 5
+
 // NewConnector returns a Connector with an active mpvipc connection.
 func NewConnector(conn *mpvipc.Connection) *Connector {
 	return &Connector{
@@ -47,12 +95,18 @@ func NewConnector(conn *mpvipc.Connection) *Connector {
 	}
 }
 
-// GetMPV returns the currently active mpvipc instance.
+// GetMPV returns the currently active mpvipc instance. Until mpv
+// has started, commands sent to the instance return an error.
 func GetMPV() *Connector {
+	mpvLock.Lock()
+	defer mpvLock.Unlock()
+
 	return mpvctl
 }
 
-// MPVStart loads the mpv executable, and connects to the socket.
+// MPVStart loads the mpv executable and connects to the socket in the
+// background, so that a slow or missing mpv does not delay startup.
+// MPVWait can be used to wait until mpv has started.
 func MPVStart() error {
 	var err error
 
@@ -61,51 +115,147 @@ func MPVStart() error {
 		return err
 	}
 
-	mpvctl, err = MPVConnect(socket, true)
-	if err != nil {
-		return err
-	}
-
-	MPVErrors = make(chan string, 100)
+	MPVErrors = make(chan PlaybackError, 100)
 	MPVFileLoaded = make(chan struct{}, 100)
-	MPVPlaylistData = make(chan []map[string]interface{}, 10)
-	go mpvctl.eventListener()
+	MPVResumed = make(chan int64, 10)
+	MPVPlaylistData = make(chan []PlaylistEntry, 10)
 
 	mpvInfoChan = make(chan int, 100)
-	mpvErrorChan = make(chan int, 100)
+	mpvErrorChan = make(chan PlaybackError, 100)
 	monitorMap = make(map[int]string)
+
+	mpvctl = &Connector{}
+	mpvReady = make(chan struct{})
+
+	go mpvInit()
+
+	return nil
+}
+
+// MPVWait waits until mpv has started, and returns
+// an error if mpv could not be started.
+func MPVWait() error {
+	<-mpvReady
+
+	return mpvErr
+}
+
+// mpvInit starts mpv and sets up the connection to it.
+func mpvInit() {
+	defer close(mpvReady)
+
+	ctl, err := MPVConnect(socket, true)
+	if err != nil {
+		LogError("mpv could not be started", "error", err)
+		mpvErr = err
+		return
+	}
+
+	mpvLock.Lock()
+	stopped := mpvStopped
+	if !stopped {
+		setConnector(ctl)
+	}
+	mpvLock.Unlock()
+
+	if stopped {
+		ctl.MPVStop(true)
+		mpvErr = fmt.Errorf("mpv was stopped")
+
+		return
+	}
+
+	logInfo("mpv started", "socket", socket)
+
 	go monitorStart()
+}
+
+// MPVReconnect attempts to reconnect to the socket after the connection
+// to mpv was lost, if mpv is still running or was restarted on the socket.
+func MPVReconnect() error {
+	ctl, err := connectSocket(socket, reconnectRetries)
+	if err != nil {
+		LogError("mpv reconnection failed", "error", err)
+		return err
+	}
+
+	mpvLock.Lock()
+	defer mpvLock.Unlock()
+
+	if mpvStopped {
+		return fmt.Errorf("mpv was stopped")
+	}
+
+	setConnector(ctl)
+	logInfo("mpv reconnected", "socket", socket)
+
+	return nil
+}
+
+// setConnector sets the active mpvipc instance, and starts listening
+// for its events. It must be called with mpvLock held.
+func setConnector(ctl *Connector) {
+	mpvctl = ctl
+
+	go mpvctl.eventListener()
 
 	mpvctl.Call("keybind", "q", "")
 	mpvctl.Call("keybind", "Ctrl+q", "")
 	mpvctl.Call("keybind", "Shift+q", "")
 
-	return nil
+	applyEqualizer(mpvctl)
+	applyNormalization(mpvctl)
 }
 
 // MPVConnect attempts to connect to the mpv instance.
 func MPVConnect(socket string, mpvexec bool) (*Connector, error) {
 	if mpvexec {
-		mpvcmd = exec.Command(
-			mpvpath,
+		args := []string{
 			"--idle",
 			"--keep-open",
 			"--no-terminal",
 			"--really-quiet",
 			"--no-input-terminal",
-			"--user-agent="+userAgent,
-			"--input-ipc-server="+socket,
-			"--script-opts=ytdl_hook-ytdl_path="+ytdlpath,
-		)
+			"--user-agent=" + userAgent,
+			"--input-ipc-server=" + socket,
+			"--script-opts=ytdl_hook-ytdl_path=" + ytdlpath,
+		}
+		if mpvProfile != "" {
+			args = append(args, "--profile="+mpvProfile)
+		}
+		if audioDevice != "" {
+			args = append(args, "--audio-device="+audioDevice)
+		}
+		if strings.HasPrefix(proxyURL, "http://") {
+			args = append(args, "--http-proxy="+proxyURL, "--ytdl-raw-options=proxy="+proxyURL)
+		}
+		if debugMode {
+			args = append(args, "--log-file="+filepath.Join(cachePath, "mpv.log"))
+		}
+		mpvLock.Lock()
+		args = append(args, mpvArgs...)
+		mpvLock.Unlock()
+
+		cmd := exec.Command(mpvpath, args...)
 
-		err := mpvcmd.Start()
+		err := cmd.Start()
 		if err != nil {
 			return nil, fmt.Errorf("Could not start mpv")
 		}
+
+		mpvLock.Lock()
+		mpvcmd = cmd
+		mpvLock.Unlock()
 	}
 
+	return connectSocket(socket, connretries)
+}
+
+// connectSocket attempts to connect to the socket, retrying
+// every second for the given number of retries.
+func connectSocket(socket string, retries int) (*Connector, error) {
 	conn := mpvipc.NewConnection(socket)
-	for i := 1; i < connretries; i++ {
+	for i := 1; i < retries; i++ {
 		err := conn.Open()
 		if err != nil {
 			time.Sleep(1 * time.Second)
@@ -132,11 +282,34 @@ func CloseInstances(socket string) {
 
 // WaitUntilClosed waits until a connection is closed.
 func (c *Connector) WaitUntilClosed() {
+	if c.conn == nil {
+		return
+	}
+
 	c.conn.WaitUntilClosed()
 }
 
-// MPVStop sends a quit command to the mpv executable.
+// MPVStop sends a quit command to the mpv executable. If mpv is still
+// starting, it is stopped as soon as it has started.
 func (c *Connector) MPVStop(rm bool) {
+	if c.conn == nil {
+		mpvLock.Lock()
+		defer mpvLock.Unlock()
+
+		mpvStopped = true
+		if mpvcmd != nil && mpvcmd.Process != nil {
+			mpvcmd.Process.Kill()
+		}
+
+		return
+	}
+
+	mpvLock.Lock()
+	if c == mpvctl {
+		mpvStopped = true
+	}
+	mpvLock.Unlock()
+
 	if c.IsClosed() {
 		return
 	}
@@ -157,6 +330,7 @@ func (c *Connector) Call(args ...interface{}) (interface{}, error) {
 	}
 
 	value, err := c.conn.Call(args...)
+	logDebug("mpv call", "args", args, "value", value, "error", err)
 
 	return value, err
 }
@@ -168,6 +342,7 @@ func (c *Connector) Get(prop string) (interface{}, error) {
 	}
 
 	value, err := c.conn.Get(prop)
+	logDebug("mpv get", "property", prop, "value", value, "error", err)
 
 	return value, err
 }
@@ -179,6 +354,7 @@ func (c *Connector) Set(prop string, value interface{}) error {
 	}
 
 	err := c.conn.Set(prop, value)
+	logDebug("mpv set", "property", prop, "value", value, "error", err)
 
 	return err
 }
@@ -186,14 +362,20 @@ func (c *Connector) Set(prop string, value interface{}) error {
 // LoadFile loads the given file into mpv along with the relevant metadata.
 // If the files parameter contains more than one filename argument, it
 // will consider the first entry as the video file and the second entry as
-// the audio file, set the relevant options and pass them to mpv.
-func (c *Connector) LoadFile(title string, duration int64, liveaudio bool, files ...string) error {
+// the audio file, set the relevant options and pass them to mpv. If subtitle
+// is not empty, it is loaded as the subtitle of the file. If live is set, the
+// file is loaded as a live stream, and if liveaudio is set, its video is not played.
+func (c *Connector) LoadFile(title string, duration int64, live, liveaudio bool, subtitle string, files ...string) error {
 	options := "force-media-title=%" + strconv.Itoa(len(title)) + "%" + title
 
 	if duration > 0 {
 		options += ",length=" + strconv.FormatInt(duration, 10)
 	}
 
+	if live {
+		options += "," + liveOptions
+	}
+
 	if liveaudio {
 		options += ",vid=no"
 	}
@@ -202,6 +384,10 @@ func (c *Connector) LoadFile(title string, duration int64, liveaudio bool, files
 		options += ",audio-file=" + files[1]
 	}
 
+	if subtitle != "" {
+		options += ",sub-files-append=%" + strconv.Itoa(len(subtitle)) + "%" + subtitle
+	}
+
 	files[0] += "&options=" + url.QueryEscape(options)
 	_, err := c.Call("loadfile", files[0], "append-play", options)
 	if err != nil {
@@ -216,6 +402,10 @@ func (c *Connector) LoadFile(title string, duration int64, liveaudio bool, files
 // LoadPlaylist loads a playlist file. If replace is false, it appends the loaded
 // playlist to the current playlist, otherwise it replaces the current playlist.
 func (c *Connector) LoadPlaylist(plpath string, replace bool) error {
+	if RestrictedLocked() {
+		return fmt.Errorf("Playlist files cannot be opened in locked mode")
+	}
+
 	if replace {
 		c.Call("playlist-clear")
 		c.Call("playlist-remove", "current")
@@ -237,37 +427,71 @@ func (c *Connector) LoadPlaylist(plpath string, replace bool) error {
 	scanner.Split(bufio.ScanLines)
 
 	for scanner.Scan() {
-		var title, options string
-
 		line := scanner.Text()
 
 		if strings.HasPrefix(line, "#") || line == "" {
 			continue
 		}
 
-		data := GetDataFromURL(line)
-		if t := data.Get("title"); t != "" {
-			title = t
-		}
-		if o := data.Get("options"); o != "" {
-			options = replaceOptions(o)
-		}
-		if l := data.Get("length"); l == "Live" {
-			audio := data.Get("mediatype") == "Audio"
-			if refresh := refreshLiveURL(line, audio); refresh {
-				continue
-			}
-		}
+		queueLock.Lock()
+		c.loadEntry(line)
+		queueLock.Unlock()
+	}
+
+	return nil
+}
+
+// PlaylistInsert loads a playlist entry's filename, as stored in the playlist data,
+// and moves it to the given position in the playlist. If pos is negative, the entry
+// is appended to the end of the playlist.
+func (c *Connector) PlaylistInsert(filename string, pos int) error {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	count := c.PlaylistCount()
+
+	if err := c.loadEntry(filename); err != nil {
+		return err
+	}
+
+	if pos >= 0 && pos < count {
+		c.PlaylistMove(count, pos)
+	}
+
+	return nil
+}
 
-		if !strings.Contains(options, "force-media-title") {
-			options += ",force-media-title=%" + strconv.Itoa(len(title)) + "%" + title
+// loadEntry loads a playlist entry along with its options. If the entry is
+// an expired live video URL, the latest URL for the video is loaded instead.
+// It must be called with queueLock held.
+func (c *Connector) loadEntry(line string) error {
+	var title, options string
+
+	data := GetDataFromURL(line)
+	if t := data.Get("title"); t != "" {
+		title = t
+	}
+	if o := data.Get("options"); o != "" {
+		options = replaceOptions(o)
+	}
+	if l := data.Get("length"); l == "Live" {
+		audio := data.Get("mediatype") == "Audio"
+		if refresh := refreshLiveURL(line, audio); refresh {
+			return nil
 		}
+	}
 
-		c.Call("loadfile", line, "append-play", options)
+	if !strings.Contains(options, "force-media-title") {
+		options += ",force-media-title=%" + strconv.Itoa(len(title)) + "%" + title
+	}
 
-		addToMonitor(title)
+	_, err := c.Call("loadfile", line, "append-play", options)
+	if err != nil {
+		return fmt.Errorf("Unable to load %s", title)
 	}
 
+	addToMonitor(title)
+
 	return nil
 }
 
@@ -331,9 +555,30 @@ func (c *Connector) IsBuffering() bool {
 	return buf.(bool)
 }
 
+// BufferingPercent returns the percentage of the cache that
+// must be filled before playback resumes.
+func (c *Connector) BufferingPercent() int {
+	percent, err := c.Get("cache-buffering-state")
+	if err != nil {
+		return 0
+	}
+
+	return int(percent.(float64))
+}
+
+// CacheTime returns the position up to which the file is cached, in seconds.
+func (c *Connector) CacheTime() int64 {
+	cachetime, err := c.Get("demuxer-cache-time")
+	if err != nil {
+		return 0
+	}
+
+	return int64(cachetime.(float64))
+}
+
 // IsClosed checks if mpv has exited.
 func (c *Connector) IsClosed() bool {
-	return c.conn.IsClosed()
+	return c.conn == nil || c.conn.IsClosed()
 }
 
 // MediaType determines if currently playing file is of
@@ -411,14 +656,36 @@ func (c *Connector) Volume() int {
 	return int(vol.(float64))
 }
 
-// PlaylistData return the current playlist data.
-func (c *Connector) PlaylistData() string {
+// PlaylistEntries returns the entries of the current playlist.
+func (c *Connector) PlaylistEntries() ([]PlaylistEntry, error) {
+	var entries []PlaylistEntry
+
 	list, err := c.Call("get_property_string", "playlist")
+	if err != nil || list == nil {
+		return nil, fmt.Errorf("Could not fetch playlist")
+	}
+
+	err = json.Unmarshal([]byte(list.(string)), &entries)
 	if err != nil {
+		return nil, fmt.Errorf("Error while parsing playlist data")
+	}
+
+	return entries, nil
+}
+
+// PlayingVideoID returns the ID of the currently playing video.
+func (c *Connector) PlayingVideoID() string {
+	path, err := c.Call("get_property_string", "path")
+	if err != nil || path == nil {
 		return ""
 	}
 
-	return list.(string)
+	data := GetDataFromURL(path.(string))
+	if id := data.Get("videoid"); id != "" {
+		return id
+	}
+
+	return data.Get("id")
 }
 
 // PlaylistCount returns the total amount of files in the playlist.
@@ -431,6 +698,20 @@ func (c *Connector) PlaylistCount() int {
 	return int(count.(float64))
 }
 
+// PlaylistEntryID returns the ID of the playlist entry at the given position.
+func (c *Connector) PlaylistEntryID(pos int) (int, error) {
+	id, err := c.Get("playlist/" + strconv.Itoa(pos) + "/id")
+	if err != nil {
+		return 0, err
+	}
+
+	if id, ok := id.(float64); ok {
+		return int(id), nil
+	}
+
+	return 0, fmt.Errorf("Could not get the playlist entry ID")
+}
+
 // PlaylistPos returns the current position of the file in the playlist.
 func (c *Connector) PlaylistPos() int {
 	pos, err := c.Get("playlist-playing-pos")
@@ -529,29 +810,140 @@ func (c *Connector) Play() {
 	c.Set("pause", "no")
 }
 
+// Pause pauses the playback.
+func (c *Connector) Pause() {
+	c.Set("pause", "yes")
+}
+
 // Stop stops the playback.
 func (c *Connector) Stop() {
 	c.Call("stop")
 }
 
-// VolumeIncrease increases the volume.
-func (c *Connector) VolumeIncrease() {
-	vol := c.Volume()
-	if vol == -1 {
-		return
+// SetVolume sets the volume, within the
+// range of 0 and mpv's maximum volume.
+func (c *Connector) SetVolume(vol int) error {
+	max := 100
+	if volmax, err := c.Get("volume-max"); err == nil {
+		if v, ok := volmax.(float64); ok {
+			max = int(v)
+		}
 	}
 
-	c.Set("volume", vol+1)
+	if vol < 0 {
+		vol = 0
+	}
+	if vol > max {
+		vol = max
+	}
+
+	return c.Set("volume", vol)
 }
 
-// VolumeDecrease decreases the volume.
-func (c *Connector) VolumeDecrease() {
+// AdjustVolume changes the volume by delta.
+func (c *Connector) AdjustVolume(delta int) error {
 	vol := c.Volume()
 	if vol == -1 {
-		return
+		return fmt.Errorf("Could not get the volume")
+	}
+
+	return c.SetVolume(vol + delta)
+}
+
+// Speed returns the playback speed.
+func (c *Connector) Speed() float64 {
+	speed, err := c.Get("speed")
+	if err != nil {
+		return 1
+	}
+
+	return speed.(float64)
+}
+
+// SetSpeed sets the playback speed, within the range of
+// minSpeed and maxSpeed. The pitch of the audio is corrected,
+// so that it does not change with the speed.
+func (c *Connector) SetSpeed(speed float64) error {
+	if speed < minSpeed {
+		speed = minSpeed
+	}
+	if speed > maxSpeed {
+		speed = maxSpeed
+	}
+
+	if err := c.Set("audio-pitch-correction", "yes"); err != nil {
+		return err
+	}
+
+	return c.Set("speed", math.Round(speed*100)/100)
+}
+
+// AdjustSpeed changes the playback speed by delta.
+func (c *Connector) AdjustSpeed(delta float64) error {
+	return c.SetSpeed(c.Speed() + delta)
+}
+
+// CycleSpeed sets the playback speed to the next speed in speedPresets.
+func (c *Connector) CycleSpeed() error {
+	speed := c.Speed()
+
+	for _, preset := range speedPresets {
+		if preset > speed+0.001 {
+			return c.SetSpeed(preset)
+		}
 	}
 
-	c.Set("volume", vol-1)
+	return c.SetSpeed(speedPresets[0])
+}
+
+// AudioDevices returns the audio output devices.
+func (c *Connector) AudioDevices() ([]AudioDevice, error) {
+	var devices []AudioDevice
+
+	list, err := c.Call("get_property_string", "audio-device-list")
+	if err != nil || list == nil {
+		return nil, fmt.Errorf("Could not fetch audio devices")
+	}
+
+	err = json.Unmarshal([]byte(list.(string)), &devices)
+	if err != nil {
+		return nil, fmt.Errorf("Error while parsing audio device data")
+	}
+
+	return devices, nil
+}
+
+// AudioDevice returns the name of the selected audio output device.
+func (c *Connector) AudioDevice() string {
+	device, err := c.Get("audio-device")
+	if err != nil {
+		return ""
+	}
+
+	name, _ := device.(string)
+
+	return name
+}
+
+// SetAudioDevice switches the audio output to the device.
+func (c *Connector) SetAudioDevice(name string) error {
+	return c.Set("audio-device", name)
+}
+
+// SaveAudioDevice saves the audio output device to the config
+// file, so that it is selected when mpv is started.
+func SaveAudioDevice(name string) error {
+	return saveConfigOption("audio-device", name)
+}
+
+// VolumeIncrease increases the volume.
+func (c *Connector) VolumeIncrease() {
+	c.AdjustVolume(1)
+}
+
+// VolumeDecrease decreases the volume.
+func (c *Connector) VolumeDecrease() {
+	c.AdjustVolume(-1)
 }
 
 // SeekForward seeks the track forward.
@@ -578,22 +970,30 @@ func (c *Connector) Prev() {
 func monitorStart() {
 	for {
 		select {
-		case id, ok := <-mpvErrorChan:
+		case perr, ok := <-mpvErrorChan:
 			if !ok {
 				return
 			}
 
 			monitorMutex.Lock()
 
-			title := monitorMap[id]
-			delete(monitorMap, id)
+			perr.Title = monitorMap[perr.ID]
+			delete(monitorMap, perr.ID)
 
 			monitorMutex.Unlock()
 
-			select {
-			case MPVErrors <- title:
-			default:
-			}
+			go func(perr PlaybackError) {
+				if err := retryEntry(perr.ID); err == nil {
+					return
+				}
+
+				LogError("playback failed", "title", perr.Title, "error", perr.Error)
+
+				select {
+				case MPVErrors <- perr:
+				default:
+				}
+			}(perr)
 
 		}
 	}
@@ -612,6 +1012,17 @@ func addToMonitor(name string) {
 	}
 }
 
+// MonitorTitle returns the title which the playlist entry
+// with the given ID was loaded with, if it is being monitored.
+func MonitorTitle(id int) (string, bool) {
+	monitorMutex.Lock()
+	defer monitorMutex.Unlock()
+
+	title, ok := monitorMap[id]
+
+	return title, ok
+}
+
 // clearMonitor clears the monitor data.
 func clearMonitor() {
 	monitorMutex.Lock()
@@ -629,6 +1040,8 @@ func (c *Connector) eventListener() {
 	defer c.conn.Close()
 	defer func() { stopListening <- struct{}{} }()
 
+	var track map[string]string
+
 	c.Call("observe_property", 1, "playlist")
 
 	for {
@@ -640,15 +1053,10 @@ func (c *Connector) eventListener() {
 
 			if event.ID == 1 {
 				if data, ok := event.Data.([]interface{}); ok {
-					pldata := make([]map[string]interface{}, len(data))
-
-					for i, d := range data {
-						if p, ok := d.(map[string]interface{}); ok {
-							pldata[i] = p
-						}
-					}
+					entries := playlistEntries(data)
+					recordQueue(entries)
 
-					MPVPlaylistData <- pldata
+					MPVPlaylistData <- entries
 
 					break
 				}
@@ -666,24 +1074,70 @@ func (c *Connector) eventListener() {
 				}
 
 			case "end-file":
+				if track != nil && event.Reason == "eof" {
+					SendWebhook("track-finished", track)
+				}
+				track = nil
+				stopWatchEntry()
+				finishEpisode(event.Reason == "eof")
+				finishResume(event.Reason == "eof")
+
 				if len(event.ExtraData) > 0 {
 					err := event.ExtraData["file_error"]
 					val := event.ExtraData["playlist_entry_id"]
 
 					if err != nil && val != nil {
 						if err.(string) != "" {
-							mpvErrorChan <- int(val.(float64))
+							mpvErrorChan <- PlaybackError{
+								ID:    int(val.(float64)),
+								Error: err.(string),
+							}
 						}
 					}
 				}
 
 			case "file-loaded":
+				data := PlayingData()
+				track = trackData(data)
+				resetRetries(track["videoid"])
+				startEpisode(data)
+				if position := startResume(c, data); position > 0 {
+					MPVResumed <- position
+				}
+				startWatchEntry(track)
+				SendWebhook("track-started", track)
+				go runTrackHook(track)
+
 				MPVFileLoaded <- struct{}{}
 			}
 		}
 	}
 }
 
+// playlistEntries converts the playlist property data
+// from a property change event to playlist entries.
+func playlistEntries(data []interface{}) []PlaylistEntry {
+	entries := make([]PlaylistEntry, len(data))
+
+	for i, d := range data {
+		p, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if id, ok := p["id"].(float64); ok {
+			entries[i].ID = int(id)
+		}
+
+		entries[i].Title, _ = p["title"].(string)
+		entries[i].Filename, _ = p["filename"].(string)
+		entries[i].Current, _ = p["current"].(bool)
+		entries[i].Playing, _ = p["playing"].(bool)
+	}
+
+	return entries
+}
+
 // replaceOptions replaces the run and subprocess options from the options parameter.
 func replaceOptions(options string) string {
 	opts := strings.Split(options, ",")
@@ -0,0 +1,78 @@
+package lib
+
+import "strings"
+
+// topicSuffix is the suffix of the names of the channels which
+// Youtube generates for artists, to which their songs are uploaded.
+const topicSuffix = " - Topic"
+
+// albumPrefix is the prefix of the IDs of the playlists
+// which Youtube generates for albums.
+const albumPrefix = "OLAK5uy_"
+
+// musicTypes maps the music search types to the
+// search types which are queried from the instance.
+var musicTypes = map[string]string{
+	"song":   "video",
+	"album":  "playlist",
+	"artist": "channel",
+}
+
+// IsMusicSearch returns whether the search type is a music search type.
+func IsMusicSearch(stype string) bool {
+	_, ok := musicTypes[stype]
+
+	return ok
+}
+
+// TrackAuthor returns the name of the artist from the name of
+// an artist's generated channel, or the author as it is otherwise.
+func TrackAuthor(author string) string {
+	return strings.TrimSuffix(author, topicSuffix)
+}
+
+// queryType returns the search type which is queried
+// from the instance for the given search type.
+func queryType(stype string) string {
+	if qtype, ok := musicTypes[stype]; ok {
+		return qtype
+	}
+
+	return stype
+}
+
+// musicResults returns the results which are songs, albums or artists,
+// according to the music search type. Songs and artists are the videos
+// and channels of the channels generated for artists, and albums are the
+// playlists generated for albums. If none of the results match, they are
+// returned as they are, so that a search does not come up empty.
+func musicResults(stype string, results []SearchResult) []SearchResult {
+	var music []SearchResult
+
+	if !IsMusicSearch(stype) {
+		return results
+	}
+
+	for _, result := range results {
+		var match bool
+
+		switch stype {
+		case "song", "artist":
+			match = strings.HasSuffix(result.Author, topicSuffix)
+
+		case "album":
+			match = strings.HasPrefix(result.PlaylistID, albumPrefix)
+		}
+
+		if match {
+			result.Author = TrackAuthor(result.Author)
+			music = append(music, result)
+		}
+	}
+
+	if music == nil {
+		return results
+	}
+
+	return music
+}
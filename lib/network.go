@@ -0,0 +1,56 @@
+package lib
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// NetworkStatus is a channel to receive changes in connectivity.
+	NetworkStatus = make(chan bool, 10)
+
+	online     = true
+	onlineLock sync.Mutex
+)
+
+// IsOnline returns whether the instance was reachable during the last request.
+func IsOnline() bool {
+	onlineLock.Lock()
+	defer onlineLock.Unlock()
+
+	return online
+}
+
+// CheckConnection checks whether the instance is reachable.
+func (c *Client) CheckConnection() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := c.SetRequest(ctx, http.MethodHead, api+"stats", nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+// setOnline updates the connectivity status, and
+// sends the new status if it has changed.
+func setOnline(status bool) {
+	onlineLock.Lock()
+	defer onlineLock.Unlock()
+
+	if online == status {
+		return
+	}
+
+	online = status
+
+	select {
+	case NetworkStatus <- status:
+	default:
+	}
+}
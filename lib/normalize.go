@@ -0,0 +1,27 @@
+package lib
+
+// loudnormLabel is the label of the loudness normalization
+// filter in mpv's filter chain.
+const loudnormLabel = "@invidtui-loudnorm"
+
+// loudnormFilter normalizes the loudness of the audio to the EBU R128
+// target of -16 LUFS, which is commonly used for streaming and podcasts.
+const loudnormFilter = "lavfi=[loudnorm=I=-16:TP=-1.5:LRA=11]"
+
+// applyNormalization enables loudness normalization in the mpv instance
+// if --normalize-volume is set, and disables it otherwise. Only the loudnorm
+// filter is used, since streams from instances have no ReplayGain tags, and
+// applying ReplayGain as well would normalize tagged local files twice.
+func applyNormalization(c *Connector) {
+	// The filter is not present if normalization was not enabled,
+	// so the error of the remove command is ignored.
+	c.Call("af", "remove", loudnormLabel)
+
+	if !normalizeVolume {
+		return
+	}
+
+	if _, err := c.Call("af", "add", loudnormLabel+":"+loudnormFilter); err != nil {
+		LogError("loudness normalization could not be enabled", "error", err)
+	}
+}
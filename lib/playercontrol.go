@@ -0,0 +1,156 @@
+package lib
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// RegisterPlayerControl registers the control commands which control
+// the player and the queue. The changed function is called after the
+// player state is changed, and the quit function stops the application.
+func RegisterPlayerControl(changed, quit func()) {
+	for name, handler := range map[string]ControlHandler{
+		"help":        controlHelp,
+		"status":      controlStatus,
+		"play-toggle": controlPlayer(changed, func() { GetMPV().CyclePaused() }),
+		"next":        controlPlayer(changed, func() { GetMPV().Next() }),
+		"prev":        controlPlayer(changed, func() { GetMPV().Prev() }),
+		"volume-up":   controlPlayer(changed, func() { GetMPV().VolumeIncrease() }),
+		"volume-down": controlPlayer(changed, func() { GetMPV().VolumeDecrease() }),
+		"mute":        controlPlayer(changed, func() { GetMPV().CycleMute() }),
+		"loop":        controlPlayer(changed, func() { GetMPV().CycleLoop() }),
+		"shuffle":     controlPlayer(changed, func() { GetMPV().CycleShuffle() }),
+		"stop":        controlPlayer(changed, func() { GetMPV().Stop(); GetMPV().PlaylistClear() }),
+		"quit":        controlQuit(quit),
+		"seek":        controlSeek(changed),
+		"queue":       controlQueue(true, false),
+		"queue-audio": controlQueue(true, true),
+		"play":        controlQueue(false, false),
+		"play-audio":  controlQueue(false, true),
+	} {
+		RegisterControl(name, handler)
+	}
+}
+
+// controlHelp lists the available control commands.
+func controlHelp(args []string) (string, error) {
+	return strings.Join(ControlCommands(), "\n"), nil
+}
+
+// controlStatus returns the currently playing media and the player state.
+func controlStatus(args []string) (string, error) {
+	data := PlayingData()
+	if data == nil {
+		return "stopped", nil
+	}
+
+	state := "playing"
+	if GetMPV().IsPaused() {
+		state = "paused"
+	}
+
+	return fmt.Sprintf(
+		"%s: %s - %s [%s/%s]",
+		state, data.Get("title"), data.Get("author"),
+		FormatDuration(GetMPV().TimePosition()),
+		FormatDuration(GetMPV().Duration()),
+	), nil
+}
+
+// controlPlayer returns a handler which runs a player command.
+func controlPlayer(changed, cmd func()) ControlHandler {
+	return func(args []string) (string, error) {
+		if GetMPV().PlaylistCount() == 0 {
+			return "", fmt.Errorf("Nothing is playing")
+		}
+
+		cmd()
+		changed()
+
+		return "", nil
+	}
+}
+
+// controlQuit returns a handler which stops the application.
+func controlQuit(quit func()) ControlHandler {
+	return func(args []string) (string, error) {
+		quit()
+
+		return "", nil
+	}
+}
+
+// controlSeek returns a handler which seeks the player by
+// the provided number of seconds.
+func controlSeek(changed func()) ControlHandler {
+	return func(args []string) (string, error) {
+		if GetMPV().PlaylistCount() == 0 {
+			return "", fmt.Errorf("Nothing is playing")
+		}
+
+		if len(args) != 1 {
+			return "", fmt.Errorf("Usage: seek <[+-]seconds>")
+		}
+
+		secs, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", fmt.Errorf("%s is not a valid number of seconds", args[0])
+		}
+
+		_, err = GetMPV().Call("seek", secs, "relative")
+		if err != nil {
+			return "", err
+		}
+
+		changed()
+
+		return "", nil
+	}
+}
+
+// controlQueue returns a handler which loads a video/playlist URL or ID.
+// If queue is set, the media is appended to the queue, otherwise it is
+// played immediately.
+func controlQueue(queue, audio bool) ControlHandler {
+	return func(args []string) (string, error) {
+		if len(args) != 1 {
+			return "", fmt.Errorf("Usage: <command> <url or id>")
+		}
+
+		id, mtype, err := GetVPIDFromURL(args[0])
+		if err != nil {
+			return "", err
+		}
+
+		go func() {
+			var title string
+			var err error
+
+			JobReset("video")
+
+			if mtype == "video" {
+				title, err = LoadVideo(id, audio)
+			} else {
+				title, err = LoadPlaylist(id, audio)
+			}
+			if err != nil {
+				log.Println("Error: " + err.Error())
+				return
+			}
+
+			if !queue && mtype == "video" {
+				GetMPV().PlaylistPlayLatest()
+			}
+
+			log.Println("Added " + title)
+		}()
+
+		if queue {
+			return "Queued " + args[0], nil
+		}
+
+		return "Playing " + args[0], nil
+	}
+}
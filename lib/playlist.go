@@ -17,6 +17,7 @@ type PlaylistResult struct {
 	Description string          `json:"description"`
 	VideoCount  int             `json:"videoCount"`
 	ViewCount   int64           `json:"viewCount"`
+	IsListed    bool            `json:"isListed"`
 	Videos      []PlaylistVideo `json:"videos"`
 }
 
@@ -28,6 +29,12 @@ type PlaylistVideo struct {
 	AuthorID      string `json:"authorId"`
 	IndexID       string `json:"indexId"`
 	LengthSeconds int64  `json:"lengthSeconds"`
+
+	IsUpcoming        bool  `json:"isUpcoming"`
+	PremiereTimestamp int64 `json:"premiereTimestamp"`
+
+	LiveNow bool `json:"liveNow"`
+	Premium bool `json:"premium"`
 }
 
 var (
@@ -36,16 +43,27 @@ var (
 	plistMutex sync.Mutex
 )
 
-const playlistFields = "?fields=title,playlistId,author,description,videoCount,viewCount,videos&hl=en"
+// playlistBatchSize is the number of videos that PlaylistStream
+// decodes before passing them to its callback.
+const playlistBatchSize = 50
+
+const playlistFields = "?fields=title,playlistId,author,description,videoCount,viewCount,isListed,videos&hl=en"
 
 // Playlist gets the playlist with the given ID and returns a PlaylistResult.
 // If id is blank, it indicates that more results are to be loaded for the
 // same playlist ID (stored in plistid). If auth is true, it will load playlists
 // with an authorization token.
 func (c *Client) Playlist(id string, auth bool) (PlaylistResult, error) {
-	var authToken []string
-	var result PlaylistResult
+	return c.PlaylistStream(id, auth, nil)
+}
 
+// PlaylistStream gets the playlist like Playlist, but decodes the playlist's
+// videos incrementally. If onVideos is not nil, the videos are passed to it in
+// batches as they are decoded, along with the playlist data decoded before them,
+// so that large playlists can be shown before the entire response is read.
+func (c *Client) PlaylistStream(
+	id string, auth bool, onVideos func(PlaylistResult, []PlaylistVideo),
+) (PlaylistResult, error) {
 	if c == nil {
 		return PlaylistResult{}, nil
 	}
@@ -57,31 +75,164 @@ func (c *Client) Playlist(id string, auth bool) (PlaylistResult, error) {
 		plistid = id
 	}
 
-	query := "playlists/" + plistid + playlistFields + "&page=" + getPlistPage()
+	if IsMix(plistid) {
+		if id == "" {
+			return PlaylistResult{PlaylistID: plistid}, nil
+		}
+
+		return c.mix(plistid, onVideos)
+	}
+
+	ctx, done := JobStart("playlist")
+	defer done()
+
+	return c.playlistPage(ctx, plistid, getPlistPage(), auth, true, onVideos)
+}
+
+// playlistPage gets the given page of the playlist with the given ID,
+// and decodes its videos like PlaylistStream. If filter is set, restricted
+// videos are removed from each batch.
+func (c *Client) playlistPage(
+	ctx context.Context, id, page string, auth, filter bool,
+	onVideos func(PlaylistResult, []PlaylistVideo),
+) (PlaylistResult, error) {
+	var authToken []string
+	var result PlaylistResult
+
+	query := "playlists/" + id + playlistFields + "&page=" + page
 	if auth {
 		query = "auth/" + query
 		authToken = append(authToken, GetToken())
 	}
 
-	res, err := c.ClientRequest(PlaylistCtx(), query, authToken...)
+	res, err := c.ClientRequest(ctx, query, authToken...)
 	if err != nil {
 		return PlaylistResult{}, err
 	}
 	defer res.Body.Close()
 
-	err = json.NewDecoder(res.Body).Decode(&result)
-	if err != nil {
+	fields := make(map[string]json.RawMessage)
+	decodeFields := func() error {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+
+		videos := result.Videos
+		err = json.Unmarshal(data, &result)
+		result.Videos = videos
+
+		return err
+	}
+
+	dec := json.NewDecoder(res.Body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return PlaylistResult{}, err
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return PlaylistResult{}, err
+		}
+
+		key, _ := token.(string)
+		if key != "videos" {
+			var value json.RawMessage
+
+			if err := dec.Decode(&value); err != nil {
+				return PlaylistResult{}, err
+			}
+
+			fields[key] = value
+			continue
+		}
+
+		if err := decodeFields(); err != nil {
+			return PlaylistResult{}, err
+		}
+
+		err = streamPlaylistVideos(dec, func(videos []PlaylistVideo) {
+			if filter {
+				videos = filterRestrictedVideos(ctx, videos)
+			}
+			if onVideos != nil {
+				onVideos(result, videos)
+			}
+
+			result.Videos = append(result.Videos, videos...)
+		})
+		if err != nil {
+			return PlaylistResult{}, err
+		}
+	}
+
+	if err := decodeFields(); err != nil {
 		return PlaylistResult{}, err
 	}
 
 	return result, nil
 }
 
+// streamPlaylistVideos decodes a JSON array of playlist videos,
+// and passes the decoded videos to fn in batches.
+func streamPlaylistVideos(dec *json.Decoder, fn func([]PlaylistVideo)) error {
+	var batch []PlaylistVideo
+
+	if token, err := dec.Token(); err != nil {
+		return err
+	} else if token == nil {
+		return nil
+	} else if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("Invalid playlist data")
+	}
+
+	for dec.More() {
+		var video PlaylistVideo
+
+		if err := dec.Decode(&video); err != nil {
+			return err
+		}
+
+		batch = append(batch, video)
+		if len(batch) == playlistBatchSize {
+			fn(batch)
+			batch = nil
+		}
+	}
+
+	if len(batch) > 0 {
+		fn(batch)
+	}
+
+	_, err := dec.Token()
+
+	return err
+}
+
+// expectDelim reads the next token from the decoder, and
+// returns an error if it is not the given delimiter.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("Invalid playlist data")
+	}
+
+	return nil
+}
+
 // AuthPlaylists lists all playlists associated with an authorization token.
 func (c *Client) AuthPlaylists() ([]PlaylistResult, error) {
 	var result []PlaylistResult
 
-	res, err := c.ClientRequest(PlaylistCtx(), "auth/playlists/", GetToken())
+	ctx, done := JobStart("playlist")
+	defer done()
+
+	res, err := c.ClientRequest(ctx, "auth/playlists/", GetToken())
 	if err != nil {
 		return nil, err
 	}
@@ -106,10 +257,12 @@ func LoadPlaylist(id string, audio bool) (string, error) {
 		return "", err
 	}
 
+	ctx := JobCtx("video")
+
 	for _, p := range playlist.Videos {
 		select {
-		case <-videoCtx.Done():
-			return "", videoCtx.Err()
+		case <-ctx.Done():
+			return "", ctx.Err()
 
 		default:
 		}
@@ -120,15 +273,57 @@ func LoadPlaylist(id string, audio bool) (string, error) {
 	return playlist.Title, nil
 }
 
-// CreatePlaylist creates a new playlist.
-func (c *Client) CreatePlaylist(title, privacy string) error {
+// AllPlaylistVideos gets the playlist with the given ID, along with
+// the videos from all of its pages. The pages are requested separately
+// from the playlist which is loaded in the UI, so that its pagination
+// and its loading job are not affected.
+func (c *Client) AllPlaylistVideos(id string, auth bool) (PlaylistResult, error) {
+	if c == nil {
+		return PlaylistResult{}, nil
+	}
+
+	ctx := context.Background()
+
+	result, err := c.playlistPage(ctx, id, "1", auth, false, nil)
+	if err != nil {
+		return PlaylistResult{}, err
+	}
+
+	for page := 2; len(result.Videos) < result.VideoCount; page++ {
+		more, err := c.playlistPage(ctx, id, strconv.Itoa(page), auth, false, nil)
+		if err != nil {
+			return PlaylistResult{}, err
+		}
+		if len(more.Videos) == 0 {
+			break
+		}
+
+		result.Videos = append(result.Videos, more.Videos...)
+	}
+
+	return result, nil
+}
+
+// CreatePlaylist creates a new playlist and returns its ID.
+func (c *Client) CreatePlaylist(title, privacy string) (string, error) {
+	var result PlaylistResult
+
 	createFormat := fmt.Sprintf(
 		`{"title": "%s", "privacy": "%s"}`,
 		title, privacy,
 	)
-	_, err := c.ClientSend("auth/playlists/", createFormat, GetToken())
+	res, err := c.ClientSend("auth/playlists/", createFormat, GetToken())
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
 
-	return err
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.PlaylistID, nil
 }
 
 // EditPlaylist edits a playlist's properties.
@@ -166,12 +361,7 @@ func (c *Client) RemovePlaylistVideo(plid, index string) error {
 
 // PlaylistCtx returns the playlist context.
 func PlaylistCtx() context.Context {
-	return ClientCtx()
-}
-
-// PlaylistCancel cancels and renews the playlist context.
-func PlaylistCancel() {
-	ClientCancel()
+	return JobCtx("playlist")
 }
 
 func getPlistPage() string {
@@ -0,0 +1,189 @@
+package lib
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+)
+
+// Episode stores the playback state of an episode of a podcast channel.
+// The position of an episode is stored with the positions of other videos,
+// and is only stored in the podcast data by older versions.
+type Episode struct {
+	Position int64 `json:"position,omitempty"`
+	Played   bool  `json:"played"`
+}
+
+// podcastData stores the podcast channels and the states of their episodes.
+type podcastData struct {
+	Channels map[string]string  `json:"channels"`
+	Episodes map[string]Episode `json:"episodes"`
+}
+
+var (
+	podcasts = podcastData{
+		Channels: make(map[string]string),
+		Episodes: make(map[string]Episode),
+	}
+	podcastEpisode string
+	podcastLock    sync.Mutex
+)
+
+// SetupPodcasts loads the podcast channels and episode states.
+func SetupPodcasts() {
+	var data podcastData
+
+	podcastfile, err := DataPath("podcasts.json")
+	if err != nil {
+		return
+	}
+
+	content, err := ioutil.ReadFile(podcastfile)
+	if err != nil || len(content) == 0 {
+		return
+	}
+
+	if err := json.Unmarshal(content, &data); err != nil {
+		return
+	}
+
+	podcastLock.Lock()
+	defer podcastLock.Unlock()
+
+	if data.Channels != nil {
+		podcasts.Channels = data.Channels
+	}
+	if data.Episodes != nil {
+		podcasts.Episodes = data.Episodes
+	}
+}
+
+// SavePodcasts saves the podcast channels and episode states.
+func SavePodcasts() {
+	podcastLock.Lock()
+	defer podcastLock.Unlock()
+
+	podcastfile, err := DataPath("podcasts.json")
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(podcasts, "", " ")
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(podcastfile, data, 0664)
+}
+
+// IsPodcast returns whether the channel with the given ID is a podcast.
+func IsPodcast(channelID string) bool {
+	podcastLock.Lock()
+	defer podcastLock.Unlock()
+
+	_, ok := podcasts.Channels[channelID]
+
+	return ok
+}
+
+// TogglePodcast sets or unsets the channel with the given ID as
+// a podcast, and returns whether the channel is now a podcast.
+func TogglePodcast(channelID, name string) bool {
+	podcastLock.Lock()
+	defer podcastLock.Unlock()
+
+	if _, ok := podcasts.Channels[channelID]; ok {
+		delete(podcasts.Channels, channelID)
+		return false
+	}
+
+	podcasts.Channels[channelID] = name
+
+	return true
+}
+
+// EpisodeState returns the playback state of the episode with the given
+// video ID, along with its saved position.
+func EpisodeState(videoID string) Episode {
+	podcastLock.Lock()
+	state := podcasts.Episodes[videoID]
+	podcastLock.Unlock()
+
+	state.Position = resumedPosition(videoID)
+
+	return state
+}
+
+// MarkEpisodePlayed marks the episode with the given video ID as played,
+// if it belongs to a podcast channel, and clears its resume position.
+func MarkEpisodePlayed(videoID, channelID string) {
+	podcastLock.Lock()
+	_, ok := podcasts.Channels[channelID]
+	if ok {
+		podcasts.Episodes[videoID] = Episode{Played: true}
+	}
+	podcastLock.Unlock()
+
+	if ok {
+		clearResume(videoID)
+	}
+}
+
+// startEpisode tracks the playing entry until the next entry starts, if
+// it is an episode of a podcast channel, so that it is marked as played
+// once it ends. Its position is saved and resumed like other videos.
+func startEpisode(data map[string][]string) {
+	var videoID, channelID string
+
+	if values := data["videoid"]; len(values) > 0 {
+		videoID = values[0]
+	}
+	if values := data["authorid"]; len(values) > 0 {
+		channelID = values[0]
+	}
+
+	podcastLock.Lock()
+	defer podcastLock.Unlock()
+
+	podcastEpisode = ""
+	if _, ok := podcasts.Channels[channelID]; ok && videoID != "" {
+		podcastEpisode = videoID
+	}
+}
+
+// finishEpisode marks the episode being tracked as played, if the entry
+// played until its end, and stops tracking it.
+func finishEpisode(eof bool) {
+	podcastLock.Lock()
+	defer podcastLock.Unlock()
+
+	if podcastEpisode != "" && eof {
+		podcasts.Episodes[podcastEpisode] = Episode{Played: true}
+	}
+
+	podcastEpisode = ""
+}
+
+// episodePositions returns the positions of episodes which were saved in
+// the podcast data by older versions, and removes them from the podcast data.
+func episodePositions() map[string]int64 {
+	positions := make(map[string]int64)
+
+	podcastLock.Lock()
+	defer podcastLock.Unlock()
+
+	for videoID, state := range podcasts.Episodes {
+		if state.Position <= 0 {
+			continue
+		}
+
+		if !state.Played {
+			positions[videoID] = state.Position
+		}
+
+		state.Position = 0
+		podcasts.Episodes[videoID] = state
+	}
+
+	return positions
+}
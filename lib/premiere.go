@@ -0,0 +1,168 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Premiere stores an upcoming premiere, which is queued once it starts.
+type Premiere struct {
+	VideoID string `json:"videoId"`
+	Title   string `json:"title"`
+	Author  string `json:"author"`
+	Start   int64  `json:"start"`
+	Audio   bool   `json:"audio"`
+	Err     error  `json:"-"`
+}
+
+const (
+	// premiereCheckInterval is the interval at which a premiere which
+	// has reached its start time is checked until it becomes playable.
+	premiereCheckInterval = 30 * time.Second
+
+	// premiereWaitLimit is the time after the start time of a premiere
+	// after which it is no longer checked.
+	premiereWaitLimit = time.Hour
+)
+
+var (
+	// PremiereStatus is a channel to receive premieres which have
+	// started, and which were either queued or could not be queued.
+	PremiereStatus = make(chan Premiere, 10)
+
+	premieres     = make(map[string]Premiere)
+	premieresLock sync.Mutex
+)
+
+// IsPremiere returns whether the entry is a premiere which has not started yet.
+func IsPremiere(info SearchResult) bool {
+	return info.Type == "video" && info.IsUpcoming &&
+		info.PremiereTimestamp > time.Now().Unix()
+}
+
+// PremiereCountdown returns the time left until the start of a premiere.
+func PremiereCountdown(start int64) string {
+	left := start - time.Now().Unix()
+	if left < 0 {
+		left = 0
+	}
+
+	return FormatLength(left)
+}
+
+// SchedulePremiere queues the premiere once it becomes playable. When the
+// premiere starts, the on_premiere_start hook and the "premiere-started"
+// webhook event are sent. It returns false if the premiere was already scheduled.
+func SchedulePremiere(info SearchResult, audio bool) bool {
+	premieresLock.Lock()
+	defer premieresLock.Unlock()
+
+	if _, ok := premieres[info.VideoID]; ok {
+		return false
+	}
+
+	p := Premiere{
+		VideoID: info.VideoID,
+		Title:   info.Title,
+		Author:  info.Author,
+		Start:   info.PremiereTimestamp,
+		Audio:   audio,
+	}
+	premieres[info.VideoID] = p
+
+	go waitPremiere(p)
+
+	return true
+}
+
+// Premieres returns the scheduled premieres, ordered by their start time.
+func Premieres() []Premiere {
+	var list []Premiere
+
+	premieresLock.Lock()
+	defer premieresLock.Unlock()
+
+	for _, p := range premieres {
+		list = append(list, p)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Start < list[j].Start
+	})
+
+	return list
+}
+
+// waitPremiere waits until the premiere starts, and then checks the
+// video until it is playable, before loading it into the player.
+func waitPremiere(p Premiere) {
+	defer func() {
+		premieresLock.Lock()
+		delete(premieres, p.VideoID)
+		premieresLock.Unlock()
+
+		select {
+		case PremiereStatus <- p:
+		default:
+		}
+	}()
+
+	time.Sleep(time.Until(time.Unix(p.Start, 0)))
+
+	SendWebhook("premiere-started", p)
+	go RunHook("on_premiere_start", p)
+
+	deadline := time.Now().Add(premiereWaitLimit)
+
+	for {
+		upcoming, err := GetClient().isUpcoming(p.VideoID)
+		if err == nil && !upcoming {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			p.Err = fmt.Errorf("Premiere did not become playable")
+			return
+		}
+
+		time.Sleep(premiereCheckInterval)
+	}
+
+	if err := MPVWait(); err != nil {
+		p.Err = err
+		return
+	}
+
+	// The video job may have been canceled while the premiere was
+	// being waited for, so it is renewed before the video is loaded.
+	JobReset("video")
+
+	if _, err := LoadVideo(p.VideoID, p.Audio); err != nil {
+		p.Err = err
+	}
+}
+
+// isUpcoming checks whether the video is still an upcoming premiere. It
+// does not use the video job, so that the premiere is checked even if the
+// job is canceled, and the loading indicator is not shown for each check.
+func (c *Client) isUpcoming(id string) (bool, error) {
+	var result struct {
+		IsUpcoming bool `json:"isUpcoming"`
+	}
+
+	res, err := c.ClientRequest(context.Background(), "videos/"+id+"?fields=isUpcoming&hl=en")
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return false, err
+	}
+
+	return result.IsUpcoming, nil
+}
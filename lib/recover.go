@@ -0,0 +1,103 @@
+package lib
+
+import (
+	"fmt"
+	"sync"
+)
+
+// recoveryState stores the last known queue and playback
+// position, so that they can be restored if mpv exits.
+type recoveryState struct {
+	queue    []string
+	position int
+	time     int64
+}
+
+var (
+	recovery     = recoveryState{position: -1}
+	recoveryLock sync.Mutex
+)
+
+// MPVRestart starts a new mpv instance on the socket after mpv has exited,
+// and returns the queue, the playing entry's position in the queue, and its
+// playback time from before mpv exited.
+func MPVRestart() ([]string, int, int64, error) {
+	mpvLock.Lock()
+	stopped := mpvStopped
+	old := mpvcmd
+	mpvLock.Unlock()
+
+	if stopped {
+		return nil, -1, 0, fmt.Errorf("mpv was stopped")
+	}
+
+	// The state is copied before the new instance is connected,
+	// since its empty playlist would replace the recorded queue.
+	recoveryLock.Lock()
+	state := recovery
+	recoveryLock.Unlock()
+
+	if old != nil && old.Process != nil {
+		old.Process.Kill()
+		go old.Wait()
+	}
+
+	ctl, err := MPVConnect(socket, true)
+	if err != nil {
+		LogError("mpv could not be restarted", "error", err)
+		return nil, -1, 0, err
+	}
+
+	mpvLock.Lock()
+	if mpvStopped {
+		mpvLock.Unlock()
+		ctl.MPVStop(true)
+
+		return nil, -1, 0, fmt.Errorf("mpv was stopped")
+	}
+	setConnector(ctl)
+	mpvLock.Unlock()
+
+	clearMonitor()
+
+	logInfo("mpv restarted", "socket", socket, "entries", len(state.queue))
+
+	return state.queue, state.position, state.time, nil
+}
+
+// TrackRecovery saves the playback time of the playing entry.
+func TrackRecovery() {
+	position := GetMPV().TimePosition()
+
+	recoveryLock.Lock()
+	defer recoveryLock.Unlock()
+
+	if recovery.position >= 0 && position > 0 {
+		recovery.time = position
+	}
+}
+
+// recordQueue saves the queue and the position of the
+// playing entry, from a playlist change event.
+func recordQueue(entries []PlaylistEntry) {
+	recoveryLock.Lock()
+	defer recoveryLock.Unlock()
+
+	position := -1
+	queue := make([]string, len(entries))
+
+	for i, entry := range entries {
+		queue[i] = entry.Filename
+
+		if entry.Current || entry.Playing {
+			position = i
+		}
+	}
+
+	if position != recovery.position {
+		recovery.time = 0
+	}
+
+	recovery.queue = queue
+	recovery.position = position
+}
@@ -0,0 +1,234 @@
+package lib
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// restrictedChecks is the number of videos whose
+// restriction status is checked at the same time.
+const restrictedChecks = 5
+
+var (
+	restrictedLocked bool
+	lockedPIN        string
+	restrictedLock   sync.Mutex
+)
+
+// RestrictedMode returns whether restricted mode is enabled.
+func RestrictedMode() bool {
+	return restrictedMode
+}
+
+// RestrictedLocked returns whether locked mode is active. In locked
+// mode, restricted mode is enforced, search results and playback are
+// limited to the channels set with --restricted-channels, and the
+// history and downloads are hidden.
+func RestrictedLocked() bool {
+	restrictedLock.Lock()
+	defer restrictedLock.Unlock()
+
+	return restrictedLocked
+}
+
+// LockRestricted activates locked mode. It returns an
+// error if no PIN is set with --restricted-pin.
+func LockRestricted() error {
+	restrictedLock.Lock()
+	defer restrictedLock.Unlock()
+
+	if restrictedPIN == "" {
+		return fmt.Errorf("No PIN is set for locked mode")
+	}
+
+	restrictedLocked = true
+	lockedPIN = restrictedPIN
+
+	return nil
+}
+
+// UnlockRestricted deactivates locked mode if the PIN is
+// correct, and returns whether locked mode was deactivated.
+func UnlockRestricted(pin string) bool {
+	restrictedLock.Lock()
+	defer restrictedLock.Unlock()
+
+	if subtle.ConstantTimeCompare([]byte(pin), []byte(lockedPIN)) != 1 {
+		return false
+	}
+
+	restrictedLocked = false
+
+	return true
+}
+
+// setupRestrictedLock activates locked mode when a PIN is set, or when
+// the PIN is changed while the config is reloaded. Locked mode is never
+// deactivated by a reload, so if the PIN is removed from the config file
+// while locked, the previous PIN is still required to unlock.
+func setupRestrictedLock() {
+	restrictedLock.Lock()
+	defer restrictedLock.Unlock()
+
+	switch {
+	case restrictedPIN == lockedPIN:
+
+	case restrictedPIN != "":
+		restrictedLocked = true
+		lockedPIN = restrictedPIN
+
+	case !restrictedLocked:
+		lockedPIN = ""
+	}
+}
+
+// restrictedActive returns whether videos which are not family
+// friendly are filtered, either in restricted or locked mode.
+func restrictedActive() bool {
+	return restrictedMode || RestrictedLocked()
+}
+
+// allowedChannel returns whether videos from the channel can be
+// shown and played. In locked mode, if channels are set with
+// --restricted-channels, only those channels are allowed.
+func allowedChannel(authorID string) bool {
+	if restrictedChans == "" || !RestrictedLocked() {
+		return true
+	}
+
+	for _, id := range strings.Split(restrictedChans, ",") {
+		if strings.TrimSpace(id) == authorID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterRestricted removes videos which are not family friendly from the
+// results, if restricted or locked mode is enabled. Playlists and channels
+// are kept, since the API does not report whether they contain restricted
+// videos. In locked mode, results from channels which are not allowed are
+// removed as well.
+func filterRestricted(ctx context.Context, results []SearchResult) []SearchResult {
+	if !restrictedActive() {
+		return results
+	}
+
+	allowed := allowedResults(ctx, len(results), func(i int) (string, string) {
+		if results[i].Type != "video" {
+			return results[i].AuthorID, ""
+		}
+
+		return results[i].AuthorID, results[i].VideoID
+	})
+
+	filtered := make([]SearchResult, 0, len(results))
+	for i, result := range results {
+		if allowed[i] {
+			filtered = append(filtered, result)
+		}
+	}
+
+	return filtered
+}
+
+// filterRestrictedVideos removes the videos of a channel or playlist
+// like filterRestricted.
+func filterRestrictedVideos(ctx context.Context, videos []PlaylistVideo) []PlaylistVideo {
+	if !restrictedActive() {
+		return videos
+	}
+
+	allowed := allowedResults(ctx, len(videos), func(i int) (string, string) {
+		return videos[i].AuthorID, videos[i].VideoID
+	})
+
+	filtered := make([]PlaylistVideo, 0, len(videos))
+	for i, video := range videos {
+		if allowed[i] {
+			filtered = append(filtered, video)
+		}
+	}
+
+	return filtered
+}
+
+// filterRestrictedPlaylists removes the playlists of a channel
+// like filterRestricted.
+func filterRestrictedPlaylists(playlists []PlaylistResult) []PlaylistResult {
+	if !restrictedActive() {
+		return playlists
+	}
+
+	filtered := make([]PlaylistResult, 0, len(playlists))
+	for _, playlist := range playlists {
+		if allowedChannel(playlist.AuthorID) {
+			filtered = append(filtered, playlist)
+		}
+	}
+
+	return filtered
+}
+
+// allowedResults returns whether each of the n results is allowed. The item
+// function returns the channel ID of a result, and its video ID if it is a
+// video, which is then checked concurrently.
+func allowedResults(ctx context.Context, n int, item func(i int) (string, string)) []bool {
+	var wg sync.WaitGroup
+
+	allowed := make([]bool, n)
+	checks := make(chan struct{}, restrictedChecks)
+
+	for i := 0; i < n; i++ {
+		authorID, videoID := item(i)
+
+		if !allowedChannel(authorID) {
+			continue
+		}
+
+		if videoID == "" {
+			allowed[i] = true
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, id string) {
+			defer wg.Done()
+
+			checks <- struct{}{}
+			defer func() { <-checks }()
+
+			allowed[i] = familyFriendly(ctx, id)
+		}(i, videoID)
+	}
+
+	wg.Wait()
+
+	return allowed
+}
+
+// familyFriendly returns whether the video is marked as family friendly.
+// Videos whose status cannot be retrieved are treated as restricted.
+func familyFriendly(ctx context.Context, id string) bool {
+	var result struct {
+		IsFamilyFriendly bool `json:"isFamilyFriendly"`
+	}
+
+	res, err := GetClient().ClientRequest(ctx, "videos/"+id+"?fields=isFamilyFriendly")
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return false
+	}
+
+	return result.IsFamilyFriendly
+}
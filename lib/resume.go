@@ -0,0 +1,222 @@
+package lib
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+)
+
+// resumePosition stores the playback position of a video,
+// and when it was last updated.
+type resumePosition struct {
+	Position int64 `json:"position"`
+	Updated  int64 `json:"updated"`
+}
+
+const (
+	// resumeMinimum is the position in seconds, below which
+	// the position of a video is not saved.
+	resumeMinimum = 30
+
+	// resumeMax is the number of video positions which are saved.
+	// The positions of the least recently played videos are removed.
+	resumeMax = 500
+)
+
+var (
+	resumePositions = make(map[string]resumePosition)
+	resumeVideo     string
+	resumeLock      sync.Mutex
+
+	// resumeOverride stores the position which the next entry
+	// of a video is started at, for example after its format
+	// is switched, by video ID.
+	resumeOverride = make(map[string]int64)
+)
+
+// SetupResume loads the saved playback positions of videos, along with
+// the positions of podcast episodes which were saved by older versions.
+// It must be called after SetupPodcasts.
+func SetupResume() {
+	var positions map[string]resumePosition
+
+	resumefile, err := DataPath("positions.json")
+	if err != nil {
+		return
+	}
+
+	content, _ := ioutil.ReadFile(resumefile)
+
+	if err := json.Unmarshal(content, &positions); err != nil || positions == nil {
+		positions = make(map[string]resumePosition)
+	}
+
+	for videoID, position := range episodePositions() {
+		if _, ok := positions[videoID]; !ok {
+			positions[videoID] = resumePosition{
+				Position: position,
+				Updated:  time.Now().Unix(),
+			}
+		}
+	}
+
+	resumeLock.Lock()
+	defer resumeLock.Unlock()
+
+	resumePositions = positions
+}
+
+// SaveResume saves the playback positions of videos.
+func SaveResume() {
+	resumeLock.Lock()
+	defer resumeLock.Unlock()
+
+	resumefile, err := DataPath("positions.json")
+	if err != nil {
+		return
+	}
+
+	if len(resumePositions) > resumeMax {
+		ids := make([]string, 0, len(resumePositions))
+		for id := range resumePositions {
+			ids = append(ids, id)
+		}
+
+		sort.Slice(ids, func(i, j int) bool {
+			return resumePositions[ids[i]].Updated < resumePositions[ids[j]].Updated
+		})
+
+		for _, id := range ids[:len(ids)-resumeMax] {
+			delete(resumePositions, id)
+		}
+	}
+
+	data, err := json.MarshalIndent(resumePositions, "", " ")
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(resumefile, data, 0664)
+}
+
+// startResume seeks the playing entry to its saved position, or to the
+// position set with SeekOnLoad, and tracks its position until the next
+// entry starts. Live streams are not tracked. It returns the saved position
+// which the entry was resumed at, or 0 if it was not resumed from a saved
+// position.
+func startResume(c *Connector, data map[string][]string) int64 {
+	var videoID string
+
+	if values := data["videoid"]; len(values) > 0 {
+		videoID = values[0]
+	}
+	loadedID := videoID
+
+	if values := data["length"]; len(values) > 0 && values[0] == "Live" {
+		videoID = ""
+	}
+
+	resumeLock.Lock()
+
+	override, overridden := resumeOverride[loadedID]
+	delete(resumeOverride, loadedID)
+
+	resumeVideo = videoID
+	position := resumePositions[videoID].Position
+
+	resumeLock.Unlock()
+
+	if overridden {
+		if override > 0 {
+			c.Set("time-pos", override)
+		}
+
+		return 0
+	}
+
+	if videoID == "" || position <= 0 {
+		return 0
+	}
+
+	if err := c.Set("time-pos", position); err != nil {
+		return 0
+	}
+
+	return position
+}
+
+// resumedPosition returns the saved position of the video with the given ID.
+func resumedPosition(videoID string) int64 {
+	resumeLock.Lock()
+	defer resumeLock.Unlock()
+
+	return resumePositions[videoID].Position
+}
+
+// clearResume removes the saved position of the video with the given ID.
+func clearResume(videoID string) {
+	resumeLock.Lock()
+	defer resumeLock.Unlock()
+
+	delete(resumePositions, videoID)
+}
+
+// SeekOnLoad sets the position which the next entry of the video
+// is started at, instead of its saved position. A negative position
+// clears the position which was set.
+func SeekOnLoad(videoID string, position int64) {
+	resumeLock.Lock()
+	defer resumeLock.Unlock()
+
+	if position < 0 {
+		delete(resumeOverride, videoID)
+		return
+	}
+
+	resumeOverride[videoID] = position
+}
+
+// finishResume removes the saved position of the video being tracked,
+// if the entry played until its end, and stops tracking it.
+func finishResume(eof bool) {
+	resumeLock.Lock()
+	defer resumeLock.Unlock()
+
+	if resumeVideo != "" && eof {
+		delete(resumePositions, resumeVideo)
+	}
+
+	resumeVideo = ""
+}
+
+// TrackResume saves the position of the video being played.
+func TrackResume() {
+	resumeLock.Lock()
+	video := resumeVideo
+	resumeLock.Unlock()
+
+	if video == "" {
+		return
+	}
+
+	position := GetMPV().TimePosition()
+
+	resumeLock.Lock()
+	defer resumeLock.Unlock()
+
+	if video != resumeVideo {
+		return
+	}
+
+	if position < resumeMinimum {
+		delete(resumePositions, video)
+		return
+	}
+
+	resumePositions[video] = resumePosition{
+		Position: position,
+		Updated:  time.Now().Unix(),
+	}
+}
@@ -0,0 +1,329 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// PlaybackError stores the error for a playlist entry which could not be played.
+type PlaybackError struct {
+	ID    int
+	Title string
+	Error string
+}
+
+// entryRetries is the number of times a playlist entry which
+// failed to load is resolved again, before the error is shown.
+const entryRetries = 3
+
+var (
+	retryMap  = make(map[string]int)
+	retryLock sync.Mutex
+
+	// failedFormats stores the itags of the formats of
+	// each video which could not be played, by video ID.
+	failedFormats = make(map[string]map[string]struct{})
+)
+
+// RetryEntry resolves the video of the playlist entry with the given ID
+// again, and replaces the entry with the newly loaded video at the same
+// position in the playlist. If otherFormat is set, the video is loaded
+// with the next-best format which has not failed to play yet.
+func RetryEntry(id int, otherFormat bool) error {
+	pos, data, err := findEntry(id)
+	if err != nil {
+		return err
+	}
+
+	videoID := data.Get("videoid")
+	if videoID == "" {
+		return fmt.Errorf("Entry is not a video")
+	}
+
+	audio := data.Get("mediatype") == "Audio"
+	if !otherFormat {
+		return replaceEntry(pos, videoID, audio)
+	}
+
+	video, err := resolveVideo(videoID)
+	if err != nil {
+		return err
+	}
+
+	if video.LiveNow {
+		return fmt.Errorf("Cannot select a format for a live video")
+	}
+
+	markFailedFormat(videoID, data.Get("itag"))
+
+	format, ok := fallbackFormat(video, audio)
+	if !ok {
+		return fmt.Errorf("No other format is available")
+	}
+
+	return replaceEntryWith(pos, func() error {
+		_, err := loadVideoFormat(video, format)
+		return err
+	})
+}
+
+// PlayingFormats returns the playing video, along with the
+// itag of its format, so that another format can be selected.
+func PlayingFormats() (VideoResult, string, error) {
+	data := PlayingData()
+	if data == nil || data.Get("videoid") == "" {
+		return VideoResult{}, "", fmt.Errorf("No video is playing")
+	}
+
+	video, err := resolveVideo(data.Get("videoid"))
+	if err != nil {
+		return VideoResult{}, "", err
+	}
+
+	if video.LiveNow {
+		return VideoResult{}, "", fmt.Errorf("Cannot select a format for a live video")
+	}
+
+	return video, data.Get("itag"), nil
+}
+
+// SwitchFormat replaces the playing entry with the video loaded with the
+// given format, at the same position in the playlist, and continues
+// playback from the current time position.
+func SwitchFormat(video VideoResult, format FormatData) error {
+	c := GetMPV()
+
+	pos := c.PlaylistPos()
+	if pos < 0 || c.PlayingVideoID() != video.VideoID {
+		return fmt.Errorf("The video is not playing")
+	}
+
+	SeekOnLoad(video.VideoID, c.TimePosition())
+
+	err := replaceEntryWith(pos, func() error {
+		_, err := loadVideoFormat(video, format)
+		return err
+	})
+	if err != nil {
+		SeekOnLoad(video.VideoID, -1)
+	}
+
+	return err
+}
+
+// TogglePlayingMedia reloads the playing entry as audio if it was
+// loaded as video, and vice versa, and continues playback from the
+// current time position. It returns the media type which was loaded.
+func TogglePlayingMedia() (string, error) {
+	c := GetMPV()
+
+	pos := c.PlaylistPos()
+	data := PlayingData()
+	if pos < 0 || data == nil || data.Get("videoid") == "" {
+		return "", fmt.Errorf("No video is playing")
+	}
+
+	videoID := data.Get("videoid")
+	audio := data.Get("mediatype") != "Audio"
+
+	if !audio && ForceAudio() {
+		return "", fmt.Errorf("Cannot load video while only audio is loaded")
+	}
+
+	if data.Get("length") != "Live" {
+		SeekOnLoad(videoID, c.TimePosition())
+	}
+
+	if err := replaceEntry(pos, videoID, audio); err != nil {
+		SeekOnLoad(videoID, -1)
+		return "", err
+	}
+
+	if audio {
+		return "audio", nil
+	}
+
+	return "video", nil
+}
+
+// RemoveEntry removes the playlist entry with the given ID.
+func RemoveEntry(id int) error {
+	pos, _, err := findEntry(id)
+	if err != nil {
+		return err
+	}
+
+	GetMPV().PlaylistDelete(pos)
+
+	return nil
+}
+
+// retryEntry retries the playlist entry with the given ID, if it
+// has not already been retried entryRetries times. The entry is
+// loaded with the next-best format which has not failed yet, or with
+// the same format if no other format is available.
+func retryEntry(id int) error {
+	pos, data, err := findEntry(id)
+	if err != nil {
+		return err
+	}
+
+	videoID := data.Get("videoid")
+	if videoID == "" {
+		return fmt.Errorf("Entry is not a video")
+	}
+
+	retryLock.Lock()
+	retryMap[videoID]++
+	attempt := retryMap[videoID]
+	if attempt > entryRetries {
+		delete(retryMap, videoID)
+		delete(failedFormats, videoID)
+	}
+	retryLock.Unlock()
+
+	if attempt <= entryRetries {
+		markFailedFormat(videoID, data.Get("itag"))
+	}
+
+	if attempt > entryRetries {
+		return fmt.Errorf("Retries exhausted for %s", videoID)
+	}
+
+	audio := data.Get("mediatype") == "Audio"
+
+	video, err := resolveVideo(videoID)
+	if err == nil && !video.LiveNow {
+		if format, ok := fallbackFormat(video, audio); ok {
+			logWarn("retrying playlist entry with another format", "video", videoID, "itag", format.Itag, "attempt", attempt)
+
+			return replaceEntryWith(pos, func() error {
+				_, err := loadVideoFormat(video, format)
+				return err
+			})
+		}
+	}
+
+	logWarn("retrying playlist entry", "video", videoID, "attempt", attempt)
+
+	return replaceEntry(pos, videoID, audio)
+}
+
+// markFailedFormat marks the format with the given itag
+// as failed to play, for the video with the given ID.
+func markFailedFormat(videoID, itag string) {
+	if itag == "" {
+		return
+	}
+
+	retryLock.Lock()
+	defer retryLock.Unlock()
+
+	if failedFormats[videoID] == nil {
+		failedFormats[videoID] = make(map[string]struct{})
+	}
+
+	failedFormats[videoID][itag] = struct{}{}
+}
+
+// fallbackFormat returns the best audio or video format of the video,
+// excluding the formats which have already failed to play. It returns
+// false if no format has failed, or if every format has failed.
+func fallbackFormat(video VideoResult, audio bool) (FormatData, bool) {
+	var formats []FormatData
+
+	retryLock.Lock()
+	failed := failedFormats[video.VideoID]
+	for _, format := range append(append([]FormatData{}, video.FormatStreams...), video.AdaptiveFormats...) {
+		if _, ok := failed[format.Itag]; !ok {
+			formats = append(formats, format)
+		}
+	}
+	retryLock.Unlock()
+
+	if len(failed) == 0 {
+		return FormatData{}, false
+	}
+
+	if audio {
+		return preferredAudio(formats)
+	}
+
+	return preferredVideo(formats)
+}
+
+// findEntry returns the position of the playlist entry with the
+// given ID, and the media data stored in its filename.
+func findEntry(id int) (int, url.Values, error) {
+	entries, err := GetMPV().PlaylistEntries()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	for i, entry := range entries {
+		if entry.ID == id {
+			return i, GetDataFromURL(entry.Filename), nil
+		}
+	}
+
+	return -1, nil, fmt.Errorf("Entry not found in playlist")
+}
+
+// replaceEntry loads the video with the given ID, and replaces
+// the playlist entry at pos with it.
+func replaceEntry(pos int, videoID string, audio bool) error {
+	return replaceEntryWith(pos, func() error {
+		_, err := loadVideo(videoID, audio)
+		return err
+	})
+}
+
+// replaceEntryWith appends an entry to the playlist using the
+// load function, and replaces the playlist entry at pos with it.
+// The load function is called with queueLock held, so that no
+// other entry is appended before the new entry is moved.
+func replaceEntryWith(pos int, load func() error) error {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	c := GetMPV()
+
+	// If mpv has skipped to the next entry after the failed one,
+	// or if the entry is playing, the new entry is played once
+	// it is in place.
+	current := c.PlaylistPos()
+	skipped := current == pos+1
+	playing := current == pos
+
+	count := c.PlaylistCount()
+	if err := load(); err != nil {
+		return err
+	}
+
+	c.PlaylistMove(count, pos)
+
+	// The playing entry is switched before the old entry is
+	// deleted, so that mpv does not skip to the next entry.
+	if playing {
+		c.SetPlaylistPos(pos)
+	}
+
+	c.PlaylistDelete(pos + 1)
+
+	if skipped {
+		c.SetPlaylistPos(pos)
+	}
+
+	return nil
+}
+
+// resetRetries clears the number of retries for a video,
+// once it has been loaded successfully.
+func resetRetries(videoID string) {
+	retryLock.Lock()
+	defer retryLock.Unlock()
+
+	delete(retryMap, videoID)
+	delete(failedFormats, videoID)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"sort"
 	"strconv"
 	"sync"
 )
@@ -18,12 +19,19 @@ type SearchResult struct {
 	Author        string `json:"author"`
 	IndexID       string `json:"indexId"`
 	PublishedText string `json:"publishedText"`
+	Published     int64  `json:"published"`
 	Duration      string `json:"duration"`
 	Description   string `json:"description"`
 	VideoCount    int    `json:"videoCount"`
 	SubCount      int    `json:"subCount"`
 	LengthSeconds int64  `json:"lengthSeconds"`
 	LiveNow       bool   `json:"liveNow"`
+
+	IsUpcoming        bool  `json:"isUpcoming"`
+	PremiereTimestamp int64 `json:"premiereTimestamp"`
+
+	AuthorVerified bool `json:"authorVerified"`
+	Premium        bool `json:"premium"`
 }
 
 // SuggestResult stores the search suggestions.
@@ -32,23 +40,38 @@ type SuggestResult struct {
 	Suggestions []string `json:"suggestions"`
 }
 
+// searchPrefetch stores the results of the search pages
+// which are being fetched in the background.
+type searchPrefetch struct {
+	query   string
+	page    int
+	results []SearchResult
+	err     error
+	done    chan struct{}
+	cancel  context.CancelFunc
+}
+
 var (
 	page      int
 	pageMutex sync.Mutex
 
+	prefetch     *searchPrefetch
+	prefetchLock sync.Mutex
+
 	paramMutex   sync.Mutex
 	searchParams map[string]string
 )
 
-const searchField = "&fields=type,title,videoId,playlistId,author,authorId,publishedText,description,videoCount,subCount,lengthSeconds,videos,liveNow&hl=en"
+const searchField = "&fields=type,title,videoId,playlistId,author,authorId,publishedText,published,description,videoCount,subCount,lengthSeconds,videos,liveNow,isUpcoming,premiereTimestamp,authorVerified,premium&hl=en"
 
 // Search searches for the given string and returns a SearchResult slice.
 // It queries for two pages of results, and keeps a track of the number of
 // pages currently returned. If the getmore parameter is true, it will add
 // two more pages to the already tracked page number, and return the result.
+// The next two pages are prefetched in the background, so that they can be
+// returned immediately when more results are requested.
 func (c *Client) Search(stype, text string, getmore bool, chanid ...string) ([]SearchResult, error) {
-	var oldpg, newpg int
-	var results []SearchResult
+	var oldpg int
 
 	setpg := func(i int) {
 		if chanid != nil {
@@ -66,7 +89,8 @@ func (c *Client) Search(stype, text string, getmore bool, chanid ...string) ([]S
 		return getPage()
 	}
 
-	SearchCancel()
+	_, done := JobStart("search")
+	defer done()
 
 	if !getmore {
 		setpg(0)
@@ -74,44 +98,135 @@ func (c *Client) Search(stype, text string, getmore bool, chanid ...string) ([]S
 		oldpg = getpg()
 	}
 
-	for newpg = oldpg + 1; newpg <= oldpg+2; newpg++ {
-		var s []SearchResult
+	query := searchQuery(queryType(stype), text, chanid...)
 
-		query := "?q=" + url.QueryEscape(text) + searchField +
-			"&page=" + strconv.Itoa(newpg)
+	results, ok := takePrefetch(SearchCtx(), query, oldpg+1)
+	if !ok {
+		var err error
 
-		if chanid != nil {
-			query = "channels/search/" + chanid[0] + query
-		} else {
-			query = "search" + query + "&type=" + stype
+		results, err = c.searchPages(SearchCtx(), query, oldpg+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	setpg(oldpg + 3)
+	c.startPrefetch(query, oldpg+4)
 
-			for param, val := range searchParams {
-				if val == "" {
-					continue
-				}
+	results = musicResults(stype, results)
 
-				query += "&" + param + "=" + val
-			}
+	results = searchResultsHook(filterRestricted(SearchCtx(), results))
+	listColumnsHook(results, !getmore)
+
+	return results, nil
+}
+
+// searchQuery returns the search query without the page parameter.
+func searchQuery(stype, text string, chanid ...string) string {
+	query := "?q=" + url.QueryEscape(text) + searchField
+
+	if chanid != nil {
+		return "channels/search/" + chanid[0] + query
+	}
+
+	query = "search" + query + "&type=" + stype
+
+	params := GetSearchParams()
+
+	names := make([]string, 0, len(params))
+	for param := range params {
+		names = append(names, param)
+	}
+	sort.Strings(names)
+
+	for _, param := range names {
+		if params[param] == "" {
+			continue
 		}
 
-		res, err := c.ClientRequest(SearchCtx(), query)
+		query += "&" + param + "=" + params[param]
+	}
+
+	return query
+}
+
+// searchPages fetches two pages of results for the query, starting from page.
+func (c *Client) searchPages(ctx context.Context, query string, page int) ([]SearchResult, error) {
+	var results []SearchResult
+
+	for pg := page; pg <= page+1; pg++ {
+		var s []SearchResult
+
+		res, err := c.ClientRequest(ctx, query+"&page="+strconv.Itoa(pg))
 		if err != nil {
 			return nil, err
 		}
 
 		err = json.NewDecoder(res.Body).Decode(&s)
+		res.Body.Close()
 		if err != nil {
 			return nil, err
 		}
 
 		results = append(results, s...)
+	}
 
-		res.Body.Close()
+	return results, nil
+}
+
+// startPrefetch starts fetching the results for the query from page in the
+// background, replacing and canceling any prefetch that is in progress.
+func (c *Client) startPrefetch(query string, page int) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &searchPrefetch{
+		query:  query,
+		page:   page,
+		done:   make(chan struct{}),
+		cancel: cancel,
 	}
 
-	setpg(newpg)
+	prefetchLock.Lock()
+	if prefetch != nil {
+		prefetch.cancel()
+	}
+	prefetch = p
+	prefetchLock.Unlock()
 
-	return results, nil
+	go func() {
+		defer close(p.done)
+
+		p.results, p.err = c.searchPages(ctx, query, page)
+	}()
+}
+
+// takePrefetch returns the prefetched results for the query and page, and
+// waits for them if the prefetch is still in progress. A prefetch for any
+// other query or page is canceled.
+func takePrefetch(ctx context.Context, query string, page int) ([]SearchResult, bool) {
+	prefetchLock.Lock()
+	p := prefetch
+	prefetch = nil
+	prefetchLock.Unlock()
+
+	if p == nil {
+		return nil, false
+	}
+
+	if p.query != query || p.page != page {
+		p.cancel()
+		return nil, false
+	}
+
+	select {
+	case <-p.done:
+
+	case <-ctx.Done():
+		p.cancel()
+		return nil, false
+	}
+
+	return p.results, p.err == nil
 }
 
 // Suggestions gets the search suggestions.
@@ -120,8 +235,10 @@ func (c *Client) Suggestions(text string) (SuggestResult, error) {
 
 	query := "search/suggestions?q=" + url.QueryEscape(text)
 
-	SearchCancel()
-	res, err := c.ClientRequest(SearchCtx(), query)
+	ctx, done := JobStart("search")
+	defer done()
+
+	res, err := c.ClientRequest(ctx, query)
 	if err != nil {
 		return SuggestResult{}, err
 	}
@@ -137,12 +254,7 @@ func (c *Client) Suggestions(text string) (SuggestResult, error) {
 
 // SearchCtx returns the search context.
 func SearchCtx() context.Context {
-	return ClientCtx()
-}
-
-// SearchCancel cancels and renews the search context.
-func SearchCancel() {
-	ClientCancel()
+	return JobCtx("search")
 }
 
 // SetSearchParams sets the search parameters.
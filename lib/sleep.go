@@ -0,0 +1,70 @@
+package lib
+
+import "time"
+
+const (
+	// sleepCheckInterval is the interval at which the
+	// wall clock is checked for a system suspend.
+	sleepCheckInterval = 2 * time.Second
+
+	// sleepThreshold is the difference between the elapsed wall
+	// clock and monotonic time, above which the system is assumed
+	// to have been suspended.
+	sleepThreshold = 10 * time.Second
+)
+
+// pauseOnSuspend pauses the playback, unless --no-suspend-pause
+// is set. It returns whether the playback was paused.
+func pauseOnSuspend() bool {
+	if noSuspendPause {
+		return false
+	}
+
+	c := GetMPV()
+	if c == nil || c.IsPaused() {
+		return false
+	}
+
+	c.Pause()
+
+	return true
+}
+
+// watchSystemSleep pauses the playback when the system suspends, and calls
+// onResume after the system resumes, with whether the playback was paused.
+// The suspend is announced by logind where it is available, otherwise it
+// is detected by watchSleep after the system resumes.
+func watchSystemSleep(onResume func(paused bool)) {
+	if err := watchLogind(onResume); err != nil {
+		logInfo("cannot monitor logind for suspends, checking the clock instead", "error", err)
+	}
+
+	watchSleep(onResume)
+}
+
+// watchSleep detects when the system resumes from a suspend, and pauses
+// the playback. The monotonic clock does not advance while the system is
+// suspended, so a suspend is detected when the wall clock advances further
+// than the monotonic clock. onResume is called after the system resumes,
+// with whether the playback was paused.
+func watchSleep(onResume func(paused bool)) {
+	t := time.NewTicker(sleepCheckInterval)
+	defer t.Stop()
+
+	last := time.Now()
+
+	for now := range t.C {
+		wall := now.Round(0).Sub(last.Round(0))
+		elapsed := now.Sub(last)
+
+		last = now
+
+		if wall-elapsed < sleepThreshold {
+			continue
+		}
+
+		logInfo("system resumed from suspend", "duration", wall.String())
+
+		onResume(pauseOnSuspend())
+	}
+}
@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package lib
+
+import (
+	"bufio"
+	"errors"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// logindSleepMatch matches the PrepareForSleep signal of logind, which is
+// sent with "true" just before the system suspends, and with "false" after
+// the system resumes.
+const logindSleepMatch = "type='signal',interface='org.freedesktop.login1.Manager',member='PrepareForSleep'"
+
+// watchLogind pauses the playback when logind announces that the system
+// is about to suspend, and calls onResume after the system resumes, with
+// whether the playback was paused. It returns when the signal can no longer
+// be monitored.
+func watchLogind(onResume func(paused bool)) error {
+	var paused, sleeping bool
+
+	cmd := exec.Command("dbus-monitor", "--system", logindSleepMatch)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer cmd.Wait()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.Contains(line, "member=PrepareForSleep") {
+			sleeping = true
+			continue
+		}
+
+		if !sleeping {
+			continue
+		}
+		sleeping = false
+
+		switch strings.TrimSpace(line) {
+		case "boolean true":
+			logInfo("system is suspending")
+			paused = pauseOnSuspend()
+
+		case "boolean false":
+			logInfo("system resumed from suspend")
+			onResume(paused)
+			paused = false
+		}
+	}
+
+	return errors.New("dbus-monitor exited")
+}
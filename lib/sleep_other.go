@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package lib
+
+import "errors"
+
+// watchLogind is only supported in Linux.
+func watchLogind(onResume func(paused bool)) error {
+	return errors.New("logind is only available in Linux")
+}
@@ -14,7 +14,10 @@ type SubResult []struct {
 func (c *Client) Subscriptions() (SubResult, error) {
 	var result SubResult
 
-	res, err := c.ClientRequest(ClientCtx(), "auth/subscriptions/", GetToken())
+	ctx, done := JobStart("subscriptions")
+	defer done()
+
+	res, err := c.ClientRequest(ctx, "auth/subscriptions/", GetToken())
 	if err != nil {
 		return SubResult{}, err
 	}
@@ -4,14 +4,36 @@
 package lib
 
 import (
+	"os"
+	"os/signal"
 	"syscall"
 
 	"github.com/gdamore/tcell/v2"
 )
 
-// SuspendApp suspends the application.
+// SuspendApp pauses the playback and suspends the application.
 func SuspendApp(t tcell.Screen) {
+	pauseOnSuspend()
+
 	t.Suspend()
 	syscall.Kill(syscall.Getpid(), syscall.SIGSTOP)
 	t.Resume()
 }
+
+// WatchSuspend calls onStop when a SIGTSTP is received, for example when
+// the terminal is detached or the process is stopped by the shell, so that
+// the application can be suspended cleanly. It also watches for system
+// suspends, and calls onResume after the system resumes.
+func WatchSuspend(onStop func(), onResume func(paused bool)) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTSTP)
+
+	go func() {
+		for range sig {
+			logInfo("received SIGTSTP, suspending")
+			onStop()
+		}
+	}()
+
+	go watchSystemSleep(onResume)
+}
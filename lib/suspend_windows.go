@@ -8,3 +8,9 @@ import "github.com/gdamore/tcell/v2"
 // SuspendApp is disabled in Windows.
 func SuspendApp(t tcell.Screen) {
 }
+
+// WatchSuspend watches for system suspends, and calls onResume after the
+// system resumes. onStop is never called, since there is no SIGTSTP in Windows.
+func WatchSuspend(onStop func(), onResume func(paused bool)) {
+	go watchSystemSleep(onResume)
+}
@@ -0,0 +1,233 @@
+package lib
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyncState stores the playback state that is shared
+// between instances in a sync session.
+type SyncState struct {
+	VideoID  string `json:"videoId"`
+	Audio    bool   `json:"audio"`
+	Position int64  `json:"position"`
+	Paused   bool   `json:"paused"`
+}
+
+var (
+	syncListener net.Listener
+	syncConn     net.Conn
+	syncGuests   map[net.Conn]struct{}
+	syncStates   chan SyncState
+	syncLock     sync.Mutex
+)
+
+// StartSync starts a sync session. If --sync-host is set, the player's state
+// is sent to all guests that connect to the address and send the sync token.
+// If --sync-join is set, the state received from the host is sent on the
+// channel returned by SyncStates.
+func StartSync() error {
+	switch {
+	case syncHost != "":
+		return startSyncHost(syncHost)
+
+	case syncJoin != "":
+		return joinSync(syncJoin)
+	}
+
+	return nil
+}
+
+// StopSync stops the sync session.
+func StopSync() {
+	syncLock.Lock()
+	defer syncLock.Unlock()
+
+	if syncListener != nil {
+		syncListener.Close()
+		syncListener = nil
+	}
+
+	for guest := range syncGuests {
+		guest.Close()
+	}
+	syncGuests = nil
+
+	if syncConn != nil {
+		syncConn.Close()
+		syncConn = nil
+	}
+}
+
+// SyncMode returns "host" or "guest" if a sync session is active.
+func SyncMode() string {
+	switch {
+	case syncHost != "":
+		return "host"
+
+	case syncJoin != "":
+		return "guest"
+	}
+
+	return ""
+}
+
+// SyncGuests returns the number of guests connected to the session.
+func SyncGuests() int {
+	syncLock.Lock()
+	defer syncLock.Unlock()
+
+	return len(syncGuests)
+}
+
+// SyncStates returns a channel which receives the host's playback state.
+func SyncStates() chan SyncState {
+	return syncStates
+}
+
+// startSyncHost listens for guests on the address, and sends
+// the player's state to them every second.
+func startSyncHost(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("Cannot start a sync session on %s", addr)
+	}
+
+	syncLock.Lock()
+	syncListener = listener
+	syncGuests = make(map[net.Conn]struct{})
+	syncLock.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go addSyncGuest(conn)
+		}
+	}()
+
+	go func() {
+		t := time.NewTicker(time.Second)
+		defer t.Stop()
+
+		for range t.C {
+			syncLock.Lock()
+			if syncListener == nil {
+				syncLock.Unlock()
+				return
+			}
+			syncLock.Unlock()
+
+			broadcastSyncState()
+		}
+	}()
+
+	return nil
+}
+
+// addSyncGuest adds the guest to the session if it sends the sync token,
+// and closes the connection otherwise.
+func addSyncGuest(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	token, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(syncToken)) != 1 {
+		conn.Close()
+		return
+	}
+
+	conn.SetReadDeadline(time.Time{})
+
+	syncLock.Lock()
+	defer syncLock.Unlock()
+
+	if syncListener == nil {
+		conn.Close()
+		return
+	}
+
+	syncGuests[conn] = struct{}{}
+}
+
+// broadcastSyncState sends the player's state to all guests, and
+// removes the guests which have disconnected.
+func broadcastSyncState() {
+	state := SyncState{}
+
+	if !GetMPV().IsIdle() {
+		state = SyncState{
+			VideoID:  GetMPV().PlayingVideoID(),
+			Audio:    PlayingData().Get("mediatype") == "Audio",
+			Position: GetMPV().TimePosition(),
+			Paused:   GetMPV().IsPaused(),
+		}
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	syncLock.Lock()
+	defer syncLock.Unlock()
+
+	for guest := range syncGuests {
+		guest.SetWriteDeadline(time.Now().Add(5 * time.Second))
+
+		if _, err := guest.Write(append(data, '\n')); err != nil {
+			guest.Close()
+			delete(syncGuests, guest)
+		}
+	}
+}
+
+// joinSync connects to the host at the address, and sends the
+// received states on the sync states channel.
+func joinSync(addr string) error {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("Cannot join the sync session at %s", addr)
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := fmt.Fprintln(conn, syncToken); err != nil {
+		conn.Close()
+		return fmt.Errorf("Cannot join the sync session at %s", addr)
+	}
+	conn.SetWriteDeadline(time.Time{})
+
+	syncLock.Lock()
+	syncConn = conn
+	syncStates = make(chan SyncState, 1)
+	syncLock.Unlock()
+
+	go func() {
+		defer close(syncStates)
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var state SyncState
+
+			if err := json.Unmarshal(scanner.Bytes(), &state); err != nil {
+				continue
+			}
+
+			select {
+			case syncStates <- state:
+
+			default:
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// thumbnailCacheLimit is the maximum size of the thumbnail cache, in bytes.
+const thumbnailCacheLimit = 50 << 20
+
+var thumbnailLock sync.Mutex
+
+// Thumbnail returns the path to the thumbnail of the video with the given ID.
+// Thumbnails are cached in the cache directory, and the least recently used
+// thumbnails are removed when the cache exceeds its size limit.
+func Thumbnail(ctx context.Context, id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("No video ID provided")
+	}
+
+	dir := filepath.Join(cachePath, "thumbnails")
+	path := filepath.Join(dir, filepath.Base(id)+".jpg")
+
+	thumbnailLock.Lock()
+	_, err := os.Stat(path)
+	if err == nil {
+		now := time.Now()
+		os.Chtimes(path, now, now)
+	}
+	thumbnailLock.Unlock()
+
+	recordCache("thumbnail", err == nil)
+	if err == nil {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	client := GetClient()
+	if client == nil {
+		return "", fmt.Errorf("No instance selected")
+	}
+
+	// The thumbnail is fetched into a temporary file without holding the
+	// lock, so that a slow request does not block the other thumbnails.
+	// If the same thumbnail is fetched concurrently, the last rename wins.
+	res, err := client.GetRequest(ctx, "/vi/"+id+"/mqdefault.jpg")
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	file, err := ioutil.TempFile(dir, "thumbnail")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(file, res.Body)
+	file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	thumbnailLock.Lock()
+	defer thumbnailLock.Unlock()
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	evictThumbnails(dir)
+
+	return path, nil
+}
+
+// evictThumbnails removes the least recently used thumbnails
+// until the cache is within its size limit.
+func evictThumbnails(dir string) {
+	var size int64
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		size += file.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	for _, file := range files {
+		if size <= thumbnailCacheLimit {
+			break
+		}
+
+		// Temporary files of thumbnails which are being fetched are kept.
+		if filepath.Ext(file.Name()) != ".jpg" {
+			continue
+		}
+
+		if os.Remove(filepath.Join(dir, file.Name())) == nil {
+			size -= file.Size()
+		}
+	}
+}
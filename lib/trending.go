@@ -0,0 +1,111 @@
+package lib
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// trendingCache stores the trending videos of a category,
+// and the number of videos which have been shown.
+type trendingCache struct {
+	videos  []SearchResult
+	shown   int
+	fetched time.Time
+}
+
+const (
+	// trendingPageSize is the number of trending videos shown per page.
+	trendingPageSize = 20
+
+	// trendingCacheTime is the time after which the
+	// trending videos of a category are fetched again.
+	trendingCacheTime = 30 * time.Minute
+)
+
+var (
+	trending     = make(map[string]*trendingCache)
+	trendingLock sync.Mutex
+)
+
+// TrendingCategories lists the categories of the trending videos.
+// The empty category shows the videos which are trending overall.
+var TrendingCategories = []string{"", "music", "gaming", "movies"}
+
+// Trending gets the trending videos of the category. Each category is
+// cached and paginated separately, so if getmore is set, the next page
+// of the category is returned, otherwise its first page is returned. If
+// refresh is set, the category is fetched again even if it is cached.
+func (c *Client) Trending(category string, getmore, refresh bool) ([]SearchResult, error) {
+	trendingLock.Lock()
+	cache, ok := trending[category]
+	trendingLock.Unlock()
+
+	if !ok || refresh || time.Since(cache.fetched) > trendingCacheTime {
+		if getmore {
+			return nil, nil
+		}
+
+		recordCache("trending", false)
+
+		videos, err := c.fetchTrending(category)
+		if err != nil {
+			return nil, err
+		}
+
+		cache = &trendingCache{
+			videos:  videos,
+			fetched: time.Now(),
+		}
+	} else {
+		recordCache("trending", true)
+	}
+
+	trendingLock.Lock()
+	defer trendingLock.Unlock()
+
+	if !getmore {
+		cache.shown = 0
+	}
+
+	start := cache.shown
+	end := start + trendingPageSize
+	if end > len(cache.videos) {
+		end = len(cache.videos)
+	}
+
+	cache.shown = end
+	trending[category] = cache
+
+	return cache.videos[start:end], nil
+}
+
+// fetchTrending fetches the trending videos of the category.
+func (c *Client) fetchTrending(category string) ([]SearchResult, error) {
+	var videos []SearchResult
+
+	ctx, done := JobStart("trending")
+	defer done()
+
+	query := "trending?hl=en"
+	if category != "" {
+		query += "&type=" + category
+	}
+
+	res, err := c.ClientRequest(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	err = json.NewDecoder(res.Body).Decode(&videos)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range videos {
+		videos[i].Type = "video"
+	}
+
+	return filterRestricted(ctx, videos), nil
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/mattn/go-runewidth"
 )
 
 // FormatDuration takes a duration as seconds and returns a hh:mm:ss string.
@@ -58,10 +61,67 @@ func FormatDuration(duration int64) string {
 	return durationtext
 }
 
-// FormatPublished takes a duration in the format: "1 day ago",
-// and returns it in the format: "1d".
-func FormatPublished(published string) string {
-	ptext := strings.Split(published, " ")
+// ParseDuration takes a hh:mm:ss or mm:ss string and returns the duration in seconds.
+func ParseDuration(text string) (int64, error) {
+	var duration int64
+
+	for _, part := range strings.Split(text, ":") {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		duration = duration*60 + n
+	}
+
+	return duration, nil
+}
+
+// FormatLength formats a video's duration for display in lists,
+// according to the duration format setting.
+func FormatLength(duration int64) string {
+	if durationFormat != "text" {
+		return FormatDuration(duration)
+	}
+
+	var text []string
+
+	d := time.Duration(duration) * time.Second
+	for _, unit := range []struct {
+		dur    time.Duration
+		suffix string
+	}{
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	} {
+		if n := d / unit.dur; n > 0 || (unit.dur == time.Second && text == nil) {
+			text = append(text, strconv.Itoa(int(n))+unit.suffix)
+			d -= n * unit.dur
+		}
+	}
+
+	return strings.Join(text, " ")
+}
+
+// FormatPublished formats a publish date according to the date format setting.
+// By default, it takes a duration in the format: "1 day ago", and returns it
+// in the format: "1d". If the date format is a time layout and the publish
+// timestamp is available, the absolute date is returned.
+func FormatPublished(publishedText string, published int64) string {
+	switch dateFormat {
+	case "relative":
+		return publishedText
+
+	case "short", "":
+
+	default:
+		if published > 0 {
+			return time.Unix(published, 0).Format(dateFormat)
+		}
+	}
+
+	ptext := strings.Split(publishedText, " ")
 
 	if len(ptext) > 1 {
 		return ptext[0] + string(ptext[1][0])
@@ -70,11 +130,15 @@ func FormatPublished(published string) string {
 	return ptext[0]
 }
 
-// FormatNumber takes a number and represents it in the
-// billions(B), millions(M), or thousands(K) format, with
-// one decimal place. If there is a zero after the decimal,
-// it is removed.
+// FormatNumber takes a number and, according to the number format setting,
+// either represents it in the billions(B), millions(M), or thousands(K) format
+// with one decimal place, or in full with grouped digits. If there is a zero
+// after the decimal, it is removed.
 func FormatNumber(num int) string {
+	if numberFormat == "full" {
+		return groupDigits(num)
+	}
+
 	for i, n := range []int{
 		1000000000,
 		1000000,
@@ -95,13 +159,56 @@ func FormatNumber(num int) string {
 	return strconv.Itoa(num)
 }
 
+// groupDigits returns the number with its digits grouped in thousands,
+// using the separator of the user's locale.
+func groupDigits(num int) string {
+	digits := strconv.Itoa(num)
+	sign := ""
+
+	if num < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	sep := localeSeparator()
+
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + sep + digits[i:]
+	}
+
+	return sign + digits
+}
+
+// localeSeparator returns the thousands separator for the locale
+// set in the LC_ALL, LC_NUMERIC or LANG environment variables.
+func localeSeparator() string {
+	var locale string
+
+	for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if locale = os.Getenv(env); locale != "" {
+			break
+		}
+	}
+
+	lang := strings.SplitN(locale, "_", 2)[0]
+
+	switch lang {
+	case "de", "es", "it", "nl", "pt", "id", "tr", "da", "el":
+		return "."
+
+	case "fr", "ru", "pl", "cs", "sv", "fi", "nb", "uk", "hu", "sk":
+		return " "
+	}
+
+	return ","
+}
+
 // GetProgress renders a progress bar and media data.
 //
 //gocyclo:ignore
 func GetProgress(width int) (string, string, []string, error) {
 	var lhs, rhs string
 	var states []string
-	var state, mtype, totaltime, vol string
+	var state, mtype, totaltime, vol, speed string
 
 	ppos := GetMPV().PlaylistPos()
 	if ppos == -1 {
@@ -116,9 +223,11 @@ func GetProgress(width int) (string, string, []string, error) {
 	loop := GetMPV().LoopType()
 	mute := GetMPV().IsMuted()
 	volume := GetMPV().Volume()
+	rate := GetMPV().Speed()
 
 	duration := GetMPV().Duration()
 	timepos := GetMPV().TimePosition()
+	cachetime := GetMPV().CacheTime()
 	currtime := FormatDuration(timepos)
 
 	if volume < 0 {
@@ -129,6 +238,12 @@ func GetProgress(width int) (string, string, []string, error) {
 	states = append(states, "volume "+vol)
 	vol += "%"
 
+	if rate != 1 {
+		speed = strconv.FormatFloat(rate, 'f', -1, 64)
+		states = append(states, "speed "+speed)
+		speed += "x "
+	}
+
 	if timepos < 0 {
 		timepos = 0
 	}
@@ -141,13 +256,23 @@ func GetProgress(width int) (string, string, []string, error) {
 		timepos = duration
 	}
 
+	if cachetime < timepos {
+		cachetime = timepos
+	}
+
+	if cachetime > duration {
+		cachetime = duration
+	}
+
 	data := GetDataFromURL(title)
 	if data != nil {
 		if t := data.Get("title"); t != "" {
 			title = t
 		}
 
-		if l := data.Get("length"); l != "" {
+		if l := data.Get("length"); l == "Live" {
+			totaltime = "LIVE"
+		} else if l != "" {
 			totaltime = l
 		} else {
 			totaltime = FormatDuration(duration)
@@ -165,14 +290,6 @@ func GetProgress(width int) (string, string, []string, error) {
 
 	mtype = "(" + mtype + ")"
 
-	width /= 2
-	length := width * int(timepos) / int(duration)
-
-	endlength := width - length
-	if endlength < 0 {
-		endlength = width
-	}
-
 	if shuffle {
 		lhs += " S"
 		states = append(states, "shuffle")
@@ -202,14 +319,36 @@ func GetProgress(width int) (string, string, []string, error) {
 			state = "||"
 		}
 	} else if buffering {
-		state = "B"
+		state = "B " + string(`|/-\`[time.Now().Unix()%4]) + " " + strconv.Itoa(GetMPV().BufferingPercent()) + "%"
 	} else {
 		state = ">"
 	}
 
-	rhs = " " + vol + " " + mtype
+	if ScreenReaderMode() {
+		return title, plainProgress(state, currtime, totaltime, vol, mtype, states), states, nil
+	}
+
+	rhs = " " + speed + vol + " " + mtype
 	lhs = loop + lhs + " " + state + " "
-	progress := currtime + " |" + strings.Repeat("█", length) + strings.Repeat(" ", endlength) + "| " + totaltime
+
+	if ExpandedPlayer() {
+		width -= runewidth.StringWidth(lhs + rhs + currtime + totaltime + " || ")
+	} else {
+		width /= 2
+	}
+	if width < 0 {
+		width = 0
+	}
+
+	length := width * int(timepos) / int(duration)
+	cachelength := width*int(cachetime)/int(duration) - length
+
+	endlength := width - length - cachelength
+	if endlength < 0 {
+		endlength = width
+	}
+
+	progress := currtime + " |" + strings.Repeat("█", length) + strings.Repeat("░", cachelength) + strings.Repeat(" ", endlength) + "| " + totaltime
 
 	strings.TrimPrefix(lhs, " ")
 	strings.TrimPrefix(rhs, " ")
@@ -217,6 +356,45 @@ func GetProgress(width int) (string, string, []string, error) {
 	return title, (lhs + progress + rhs), states, nil
 }
 
+// PlayingData returns the media data of the currently playing entry.
+func PlayingData() url.Values {
+	ppos := GetMPV().PlaylistPos()
+	if ppos == -1 {
+		return nil
+	}
+
+	return GetDataFromURL(GetMPV().PlaylistTitle(ppos))
+}
+
+// plainProgress renders the media data as plain text labels, without
+// any symbols or progress bar characters.
+func plainProgress(state, currtime, totaltime, vol, mtype string, states []string) string {
+	var labels []string
+
+	switch {
+	case state == "[]":
+		state = "stopped"
+
+	case state == "||":
+		state = "paused"
+
+	case strings.HasPrefix(state, "B"):
+		fields := strings.Fields(state)
+		state = "buffering " + fields[len(fields)-1]
+
+	default:
+		state = "playing"
+	}
+
+	labels = append(labels, state, currtime+" of "+totaltime, "volume "+vol)
+
+	for _, s := range states[1:] {
+		labels = append(labels, strings.ReplaceAll(s, "-", " "))
+	}
+
+	return strings.Join(append(labels, mtype), ", ")
+}
+
 // IsValidURL checks if a URL is valid.
 func IsValidURL(uri string) (*url.URL, error) {
 	u, err := url.ParseRequestURI(uri)
@@ -265,6 +443,25 @@ func GetLinks(info SearchResult) (string, string) {
 	return invlink, ytlink
 }
 
+// GetShareLink returns a link to the video which starts at pos seconds.
+// The link is a short YouTube link, or an Invidious link to the selected
+// instance, as set with --share-link.
+func GetShareLink(id string, pos int64) string {
+	sep := "?"
+
+	link := "https://youtu.be/" + id
+	if shareLink == "invidious" {
+		link, _ = GetLinks(SearchResult{Type: "video", VideoID: id})
+		sep = "&"
+	}
+
+	if pos > 0 {
+		link += sep + "t=" + strconv.FormatInt(pos, 10)
+	}
+
+	return link
+}
+
 // GetVPIDFromURL gets the video/playlist ID from a URL.
 func GetVPIDFromURL(uri string) (string, string, error) {
 	mediaURL := uri
@@ -278,6 +475,10 @@ func GetVPIDFromURL(uri string) (string, string, error) {
 		return "", "", err
 	}
 
+	if list := u.Query().Get("list"); IsMix(list) {
+		return list, "playlist", nil
+	}
+
 	if strings.Contains(uri, "youtu.be") {
 		return strings.TrimLeft(u.Path, "/"), "video", nil
 	} else if strings.Contains(uri, "watch?v=") {
@@ -295,6 +496,10 @@ func GetVPIDFromURL(uri string) (string, string, error) {
 		return uri, "playlist", nil
 	}
 
+	if IsMix(uri) && len(uri) >= 13 {
+		return uri, "playlist", nil
+	}
+
 	return uri, "video", nil
 }
 
@@ -3,6 +3,7 @@ package lib
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -15,14 +16,25 @@ import (
 
 // VideoResult stores the video data.
 type VideoResult struct {
-	Title           string       `json:"title"`
-	Author          string       `json:"author"`
-	VideoID         string       `json:"videoId"`
-	HlsURL          string       `json:"hlsUrl"`
-	LengthSeconds   int64        `json:"lengthSeconds"`
-	LiveNow         bool         `json:"liveNow"`
-	FormatStreams   []FormatData `json:"formatStreams"`
-	AdaptiveFormats []FormatData `json:"adaptiveFormats"`
+	Title           string        `json:"title"`
+	Author          string        `json:"author"`
+	AuthorID        string        `json:"authorId"`
+	VideoID         string        `json:"videoId"`
+	HlsURL          string        `json:"hlsUrl"`
+	LengthSeconds   int64         `json:"lengthSeconds"`
+	LiveNow         bool          `json:"liveNow"`
+	IsUpcoming      bool          `json:"isUpcoming"`
+	PremiereTime    int64         `json:"premiereTimestamp"`
+	FamilyFriendly  bool          `json:"isFamilyFriendly"`
+	ViewCount       int64         `json:"viewCount"`
+	Published       int64         `json:"published"`
+	FormatStreams   []FormatData  `json:"formatStreams"`
+	AdaptiveFormats []FormatData  `json:"adaptiveFormats"`
+	Captions        []CaptionData `json:"captions"`
+
+	// host is the instance which served the video, against
+	// which the URLs of its streams are built.
+	host string
 }
 
 // FormatData stores the media format data.
@@ -40,23 +52,44 @@ type FormatData struct {
 	AudioChannels   int    `json:"audioChannels"`
 }
 
-var (
-	videoCtx     context.Context
-	videoCancel  context.CancelFunc
-	videoCtxLock sync.Mutex
-)
-
-const videoFields = "?fields=title,videoId,author,hlsUrl,publishedText,lengthSeconds,formatStreams,adaptiveFormats,liveNow&hl=en"
+const videoFields = "?fields=title,videoId,author,authorId,hlsUrl,publishedText,lengthSeconds,isUpcoming,premiereTimestamp,formatStreams,adaptiveFormats,liveNow,premium,isFamilyFriendly,captions&hl=en"
 
 // Video gets the video with the given ID and returns a VideoResult.
 func (c *Client) Video(id string) (VideoResult, error) {
 	var result VideoResult
 
-	if videoCtx == nil {
-		return VideoResult{}, fmt.Errorf("No video context found")
+	ctx, done := JobRun("video")
+	defer done()
+
+	res, err := c.ClientRequest(ctx, "videos/"+id+videoFields)
+	if err != nil {
+		return VideoResult{}, err
+	}
+	defer res.Body.Close()
+
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return VideoResult{}, err
+	}
+
+	if restrictedActive() && !result.FamilyFriendly {
+		return VideoResult{}, fmt.Errorf("%s is not available in restricted mode", result.Title)
 	}
 
-	res, err := c.ClientRequest(videoCtx, "videos/"+id+videoFields)
+	if !allowedChannel(result.AuthorID) {
+		return VideoResult{}, fmt.Errorf("%s is not available in locked mode", result.Title)
+	}
+
+	result.host = c.host
+
+	return result, nil
+}
+
+// LiveStats gets the current viewer count and start time of a live stream.
+func (c *Client) LiveStats(id string) (VideoResult, error) {
+	var result VideoResult
+
+	res, err := c.ClientRequest(context.Background(), "videos/"+id+"?fields=viewCount,published,liveNow&hl=en")
 	if err != nil {
 		return VideoResult{}, err
 	}
@@ -70,16 +103,70 @@ func (c *Client) Video(id string) (VideoResult, error) {
 	return result, nil
 }
 
+var forceAudioLock sync.Mutex
+
+// ForceAudio returns whether only the audio stream of videos is loaded.
+func ForceAudio() bool {
+	forceAudioLock.Lock()
+	defer forceAudioLock.Unlock()
+
+	return forceAudio
+}
+
+// ToggleForceAudio toggles loading only the audio stream of
+// videos, and returns whether only audio is loaded.
+func ToggleForceAudio() bool {
+	forceAudioLock.Lock()
+	defer forceAudioLock.Unlock()
+
+	forceAudio = !forceAudio
+
+	return forceAudio
+}
+
 // LoadVideo takes a video ID, determines whether to play
 // video or just audio (according to the audio parameter), and
-// appropriately loads the URLs into mpv.
+// appropriately loads the URLs into mpv. If only audio is forced,
+// the audio stream is loaded regardless of the audio parameter.
 func LoadVideo(id string, audio bool) (string, error) {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	return loadVideo(id, audio)
+}
+
+// QueueVideo loads the video into mpv like LoadVideo, and returns
+// its title and the ID of the playlist entry which it was loaded into.
+func QueueVideo(id string, audio bool) (string, int, error) {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	count := GetMPV().PlaylistCount()
+
+	title, err := loadVideo(id, audio)
+	if err != nil {
+		return "", 0, err
+	}
+
+	entryID, err := GetMPV().PlaylistEntryID(count)
+
+	return title, entryID, err
+}
+
+// loadVideo loads the video into mpv. It must be called with queueLock held.
+func loadVideo(id string, audio bool) (string, error) {
 	var err error
 	var liveaudio bool
 	var mtype, lentext, audioUrl, videoUrl string
 
-	video, err := GetClient().Video(id)
+	audio = audio || ForceAudio()
+
+	video, err := resolveVideo(id)
 	if err != nil {
+		if errors.Is(err, errAgeRestricted) {
+			return loadYoutubeDL(id, audio)
+		}
+
 		return "", err
 	}
 
@@ -90,15 +177,76 @@ func LoadVideo(id string, audio bool) (string, error) {
 	}
 
 	if video.LiveNow {
-		liveaudio = audio && video.LiveNow
+		liveaudio = audio
 		audio = false
 		lentext = "Live"
-		videoUrl, audioUrl = getLiveVideo(video, audio)
+		videoUrl, audioUrl = getLiveVideo(video, liveaudio)
+		if videoUrl == "" {
+			return "", fmt.Errorf("Could not find a stream for the live video")
+		}
 	} else {
 		lentext = FormatDuration(video.LengthSeconds)
 		videoUrl, audioUrl = getVideoByItag(video, audio)
 	}
 
+	return loadVideoURLs(video, mtype, lentext, liveaudio, audio, videoUrl, audioUrl)
+}
+
+// LoadVideoFormat loads the video with the given ID into mpv with the
+// given format, instead of the format selected by the --video-res,
+// --video-codec and --audio-bitrate settings. Video formats which
+// do not contain audio are loaded with the preferred audio stream.
+func LoadVideoFormat(id string, format FormatData) (string, error) {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	video, err := resolveVideo(id)
+	if err != nil {
+		return "", err
+	}
+
+	return loadVideoFormat(video, format)
+}
+
+// loadVideoFormat loads the video into mpv with the given format.
+// It must be called with queueLock held.
+func loadVideoFormat(video VideoResult, format FormatData) (string, error) {
+	var videoUrl, audioUrl string
+
+	if video.LiveNow {
+		return "", fmt.Errorf("Cannot select a format for a live video")
+	}
+
+	audio := IsAudioFormat(format)
+	mtype := "Video"
+
+	switch {
+	case audio:
+		mtype = "Audio"
+		audioUrl = getLatestURL(videoHost(video), video.VideoID, format.Itag)
+
+	case isMuxedFormat(video, format):
+		videoUrl = getLatestURL(videoHost(video), video.VideoID, format.Itag)
+
+	default:
+		videoUrl = getLatestURL(videoHost(video), video.VideoID, format.Itag)
+
+		if aformat, ok := preferredAudio(video.AdaptiveFormats); ok {
+			audioUrl = getLatestURL(videoHost(video), video.VideoID, aformat.Itag)
+		}
+	}
+
+	return loadVideoURLs(video, mtype, FormatDuration(video.LengthSeconds), false, audio, videoUrl, audioUrl)
+}
+
+// loadVideoURLs loads the audio URL of the video into mpv if audio is
+// set, or its video URL along with the audio URL otherwise.
+func loadVideoURLs(
+	video VideoResult, mtype, lentext string,
+	liveaudio, audio bool, videoUrl, audioUrl string,
+) (string, error) {
+	var err error
+
 	if audio && audioUrl == "" {
 		return "", fmt.Errorf("Could not find an audio stream")
 	}
@@ -107,13 +255,21 @@ func LoadVideo(id string, audio bool) (string, error) {
 		return "", fmt.Errorf("Could not find a video stream")
 	}
 
+	if !video.LiveNow {
+		if err := checkStreamHost(video, videoUrl, audioUrl); err != nil {
+			return "", err
+		}
+	}
+
 	// A data parameter is appended to audioUrl/videoUrl so that
 	// updatePlaylist() can display media data.
 	// MPV does not return certain track data like author and duration.
 	titleparam := "&title=" + url.QueryEscape(video.Title)
-	titleparam += "&author=" + url.QueryEscape(video.Author)
+	titleparam += "&author=" + url.QueryEscape(TrackAuthor(video.Author))
 	titleparam += "&mediatype=" + url.QueryEscape(mtype)
 	titleparam += "&length=" + url.QueryEscape(lentext)
+	titleparam += "&videoid=" + url.QueryEscape(video.VideoID)
+	titleparam += "&authorid=" + url.QueryEscape(video.AuthorID)
 
 	if audio {
 		_, err = IsValidURL(audioUrl + titleparam)
@@ -126,7 +282,8 @@ func LoadVideo(id string, audio bool) (string, error) {
 		err = GetMPV().LoadFile(
 			video.Title,
 			video.LengthSeconds,
-			liveaudio,
+			false, false,
+			"",
 			audioUrl)
 
 	} else {
@@ -140,7 +297,8 @@ func LoadVideo(id string, audio bool) (string, error) {
 		err = GetMPV().LoadFile(
 			video.Title,
 			video.LengthSeconds,
-			liveaudio,
+			video.LiveNow, liveaudio,
+			preferredCaption(video),
 			videoUrl, audioUrl)
 	}
 	if err != nil {
@@ -150,26 +308,9 @@ func LoadVideo(id string, audio bool) (string, error) {
 	return video.Title, nil
 }
 
-// VideoNewCtx renews the video's context.
-func VideoNewCtx() {
-	videoCtxLock.Lock()
-	defer videoCtxLock.Unlock()
-
-	videoCtx, videoCancel = context.WithCancel(context.Background())
-}
-
-// VideoCancel cancels the video's context.
-func VideoCancel() {
-	videoCtxLock.Lock()
-	defer videoCtxLock.Unlock()
-
-	if videoCtx != nil {
-		videoCancel()
-	}
-}
-
 // refreshLiveURL gets the video ID from an expired live video URL,
-// and loads the latest URL for the live video.
+// and loads the latest URL for the live video. It must be called
+// with queueLock held.
 func refreshLiveURL(uri string, audio bool) bool {
 	var id string
 
@@ -191,51 +332,114 @@ func refreshLiveURL(uri string, audio bool) bool {
 		}
 	}
 
-	VideoNewCtx()
+	// The video ID is stored in the media data if the
+	// uri is the hls playlist of the live video.
+	if id == "" {
+		if id = GetDataFromURL(uri).Get("videoid"); id == "" {
+			return false
+		}
+	}
+
+	JobReset("video")
 
-	LoadVideo(id, audio)
+	loadVideo(id, audio)
 
 	return true
 }
 
-// getLiveVideo gets the hls playlist, parses and finds the appropriate
-// live video stream.
+// getLiveVideo gets the hls playlist of a live video, and returns the URL of
+// the stream with the preferred resolution. If the playlist cannot be parsed,
+// the URL of the playlist itself is returned, so that mpv selects a stream.
 func getLiveVideo(video VideoResult, audio bool) (string, string) {
-	var videoUrl, audioUrl string
-
 	if video.HlsURL == "" {
 		return "", ""
 	}
 
-	url, _ := IsValidURL(video.HlsURL)
-	res, err := GetClient().GetRequest(context.Background(), url.RequestURI())
+	hlsUrl, err := IsValidURL(video.HlsURL)
 	if err != nil {
 		return "", ""
 	}
+
+	res, err := NewClient(videoHost(video)).GetRequest(context.Background(), hlsUrl.RequestURI())
+	if err != nil {
+		logWarn("live playlist could not be fetched", "video", video.VideoID, "error", err)
+		return liveURL(video.HlsURL), ""
+	}
 	defer res.Body.Close()
 
 	pl, err := m3u8.Read(res.Body)
 	if err != nil {
-		return "", ""
+		logWarn("live playlist could not be parsed", "video", video.VideoID, "error", err)
+		return liveURL(video.HlsURL), ""
+	}
+
+	stream := selectLiveStream(pl.Playlists(), audio)
+	if stream == nil {
+		return liveURL(video.HlsURL), ""
 	}
 
-	for _, p := range pl.Playlists() {
-		height := strconv.Itoa(p.Resolution.Height) + "p"
+	streamUrl, err := IsValidURL(stream.URI)
+	if err != nil {
+		return liveURL(video.HlsURL), ""
+	}
 
-		// Since the retrieved HLS playlist is sorted in ascending order of resolutions,
-		// for the audio stream, we grab the first stream (with the lowest quality),
-		// and instruct mpv not to play video for the audio stream. For the video stream,
-		// we grab the stream where the playlist entry's resolution and the required
-		// resolution are equal.
-		if audio || (!audio && height == videoResolution) {
-			url, _ := IsValidURL(p.URI)
-			videoUrl = "https://manifest.googlevideo.com" + url.RequestURI() + "/?"
+	return liveURL("https://manifest.googlevideo.com" + streamUrl.RequestURI()), ""
+}
 
-			break
+// selectLiveStream selects a stream from the hls playlist. For the audio stream,
+// the stream with the lowest bandwidth is selected, and mpv is instructed not
+// to play its video. For the video stream, the stream with the preferred
+// resolution is selected, or the highest resolution below it if there is none.
+func selectLiveStream(streams []*m3u8.PlaylistItem, audio bool) *m3u8.PlaylistItem {
+	var selected *m3u8.PlaylistItem
+
+	preferred, _ := strconv.Atoi(strings.TrimSuffix(videoResolution, "p"))
+
+	for _, stream := range streams {
+		if stream == nil || stream.URI == "" {
+			continue
+		}
+
+		if audio {
+			if selected == nil || stream.Bandwidth < selected.Bandwidth {
+				selected = stream
+			}
+
+			continue
+		}
+
+		if stream.Resolution == nil {
+			continue
+		}
+
+		height := stream.Resolution.Height
+		if height == preferred {
+			return stream
+		}
+
+		switch {
+		case selected == nil:
+			selected = stream
+
+		case height < preferred && (selected.Resolution.Height > preferred || height > selected.Resolution.Height):
+			selected = stream
+
+		case height > preferred && selected.Resolution.Height > preferred && height < selected.Resolution.Height:
+			selected = stream
 		}
 	}
 
-	return videoUrl, audioUrl
+	return selected
+}
+
+// liveURL returns the URL of a live stream, with a query
+// separator so that the media data can be appended to it.
+func liveURL(uri string) string {
+	if strings.Contains(uri, "?") {
+		return uri
+	}
+
+	return strings.TrimSuffix(uri, "/") + "/?"
 }
 
 // getVideoByItag gets the appropriate itag of the video format, and
@@ -247,7 +451,7 @@ func getVideoByItag(video VideoResult, audio bool) (string, string) {
 		audio, video,
 
 		func(v VideoResult, f FormatData) string {
-			return getLatestURL(v.VideoID, f.Itag)
+			return getLatestURL(videoHost(v), v.VideoID, f.Itag)
 		},
 
 		func(v VideoResult, f FormatData) string {
@@ -277,94 +481,82 @@ func getVideoByFormatURL(video VideoResult, audio bool) (string, string) {
 	return videoUrl, audioUrl
 }
 
-// videoWithResolution returns a video URL that corresponds to the
-// videoResolution setting (passed via command line option --video-res=), and
-// the resolutions listed in a video's AdaptiveFormats.
+// videoWithResolution returns a video URL from a video's AdaptiveFormats,
+// according to the --video-res and --video-codec settings.
 func videoWithResolution(video VideoResult, vtype string) string {
-	var prevData string
-
-	vq := videoResolution
-
-	for _, format := range video.AdaptiveFormats {
-		q := format.Resolution
-		if len(q) <= 0 {
-			continue
-		}
-
-		switch vtype {
-		case "url":
-			if q == vq {
-				return format.URL
-			}
-
-			prevData = format.URL
-
-		case "itag":
-			if q == vq {
-				return getLatestURL(video.VideoID, format.Itag)
-			}
+	format, ok := preferredVideo(video.AdaptiveFormats)
+	if !ok {
+		return ""
+	}
 
-			prevData = getLatestURL(video.VideoID, format.Itag)
-		}
+	if vtype == "url" {
+		return format.URL
 	}
 
-	return prevData
+	return getLatestURL(videoHost(video), video.VideoID, format.Itag)
 }
 
-// loopFormats loops over a video's AdaptiveFormats data and gets the
-// audio/video URL according to the values returned by afunc/vfunc.
+// loopFormats selects the audio/video formats from a video's format data, and
+// gets the audio/video URL according to the values returned by afunc/vfunc.
 func loopFormats(
 	audio bool, video VideoResult,
 	afunc, vfunc func(video VideoResult, format FormatData) string,
 ) (string, string) {
-	var ftype, videoUrl, audioUrl string
+	var videoUrl, audioUrl string
 
 	// For videos, we loop through FormatStreams first and get the videoUrl.
 	// This works mainly for 720p, 360p and 144p video streams.
 	if !audio {
 		for _, format := range video.FormatStreams {
-			if format.Resolution == videoResolution {
-				videoUrl = getLatestURL(video.VideoID, format.Itag)
+			if format.Resolution == videoResolution && matchesCodec(format) {
+				videoUrl = getLatestURL(videoHost(video), video.VideoID, format.Itag)
 				return videoUrl, audioUrl
 			}
 		}
 	}
 
-	// If the required resolution wasn't found in FormatStreams, we loop through
-	// AdaptiveFormats and get a video of the required resolution, along with the
-	// audio stream so that MPV can merge them and play. Or if only audio is required,
-	// return a blank videoUrl and a non-empty audioUrl.
-	for _, format := range video.AdaptiveFormats {
-		v := strings.Split(format.Type, ";")
-		p := strings.Split(v[0], "/")
+	// If the required resolution wasn't found in FormatStreams, we get a video
+	// from AdaptiveFormats, along with the preferred audio stream so that MPV
+	// can merge them and play. Or if only audio is required, return a blank
+	// videoUrl and a non-empty audioUrl.
+	if format, ok := preferredAudio(video.AdaptiveFormats); ok {
+		audioUrl = afunc(video, format)
+	}
 
-		if (audio && audioUrl != "") || (!audio && videoUrl != "") {
-			break
-		}
+	if !audio {
+		videoUrl = vfunc(video, FormatData{})
+	}
 
-		if ftype == "" {
-			ftype = p[1]
-		}
+	return videoUrl, audioUrl
+}
 
-		if p[1] == ftype {
-			if p[0] == "audio" {
-				audioUrl = afunc(video, format)
-			} else if p[0] == "video" {
-				videoUrl = vfunc(video, format)
-			}
+// isMuxedFormat returns whether the format is one of the video's
+// FormatStreams, which contain both video and audio.
+func isMuxedFormat(video VideoResult, format FormatData) bool {
+	for _, f := range video.FormatStreams {
+		if f.Itag == format.Itag {
+			return true
 		}
 	}
 
-	return videoUrl, audioUrl
+	return false
 }
 
-// getLatestURL appends the latest_version query to the current client's host URL.
+// getLatestURL appends the latest_version query to the host URL.
 // For example: https://invidious.snopyta.org/latest_version?id=mWDOxRWcoPE&itag=22&local=true
-func getLatestURL(id, itag string) string {
-	host := GetClient().host
-
+func getLatestURL(host, id, itag string) string {
 	idstr := "id=" + id
 	itagstr := "&itag=" + itag
 
 	return host + "/latest_version?" + idstr + itagstr + "&local=true"
 }
+
+// videoHost returns the instance which served the video,
+// or the current instance if it is not known.
+func videoHost(video VideoResult) string {
+	if video.host != "" {
+		return video.host
+	}
+
+	return GetClient().host
+}
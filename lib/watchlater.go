@@ -0,0 +1,253 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+// watchLaterData stores the Watch Later list, the ID of the account
+// playlist it is synced with, and the IDs of the videos which were
+// in the list after the last sync.
+type watchLaterData struct {
+	Videos   []SearchResult `json:"videos"`
+	Playlist string         `json:"playlist"`
+	Synced   []string       `json:"synced"`
+}
+
+var (
+	watchLater     watchLaterData
+	watchLaterLock sync.Mutex
+	watchLaterSync sync.Mutex
+)
+
+// SetupWatchLater loads the Watch Later list.
+func SetupWatchLater() {
+	var data watchLaterData
+
+	wlfile, err := DataPath("watchlater.json")
+	if err != nil {
+		return
+	}
+
+	content, err := ioutil.ReadFile(wlfile)
+	if err != nil || len(content) == 0 {
+		return
+	}
+
+	if err := json.Unmarshal(content, &data); err != nil {
+		return
+	}
+
+	watchLaterLock.Lock()
+	watchLater = data
+	watchLaterLock.Unlock()
+}
+
+// SaveWatchLater saves the Watch Later list.
+func SaveWatchLater() {
+	watchLaterLock.Lock()
+	defer watchLaterLock.Unlock()
+
+	wlfile, err := DataPath("watchlater.json")
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(watchLater, "", " ")
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(wlfile, data, 0664)
+}
+
+// WatchLater returns the videos in the Watch Later list.
+func WatchLater() []SearchResult {
+	watchLaterLock.Lock()
+	defer watchLaterLock.Unlock()
+
+	return append([]SearchResult{}, watchLater.Videos...)
+}
+
+// AddWatchLater adds a video to the Watch Later list. It returns
+// false if the video is already in the list.
+func AddWatchLater(info SearchResult) bool {
+	watchLaterLock.Lock()
+	defer watchLaterLock.Unlock()
+
+	if watchLaterIndex(info.VideoID) >= 0 {
+		return false
+	}
+
+	watchLater.Videos = append(watchLater.Videos, info)
+
+	return true
+}
+
+// RemoveWatchLater removes a video from the Watch Later list.
+func RemoveWatchLater(videoID string) {
+	watchLaterLock.Lock()
+	defer watchLaterLock.Unlock()
+
+	if i := watchLaterIndex(videoID); i >= 0 {
+		watchLater.Videos = append(watchLater.Videos[:i], watchLater.Videos[i+1:]...)
+	}
+}
+
+// SyncWatchLater syncs the Watch Later list with the account playlist
+// set with --watch-later-playlist, which is created if it does not exist.
+// Videos which were added to either list since the last sync are added
+// to the other list, and videos which were removed from either list are
+// removed from the other, so that a removal is not undone by the sync.
+// If the playlist was not synced before, the lists are merged. It returns
+// the number of videos added to and removed from the local list.
+func SyncWatchLater() (int, int, error) {
+	var added, removed int
+	var both []string
+
+	if !IsAuthInstance() {
+		return 0, 0, fmt.Errorf("Watch Later can only be synced when logged in")
+	}
+
+	watchLaterSync.Lock()
+	defer watchLaterSync.Unlock()
+
+	id, err := watchLaterPlaylist()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	remote, err := GetClient().AllPlaylistVideos(id, true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	watchLaterLock.Lock()
+	local := append([]SearchResult{}, watchLater.Videos...)
+	synced := make(map[string]struct{}, len(watchLater.Synced))
+	for _, videoID := range watchLater.Synced {
+		synced[videoID] = struct{}{}
+	}
+	watchLaterLock.Unlock()
+
+	inLocal := make(map[string]struct{}, len(local))
+	for _, info := range local {
+		inLocal[info.VideoID] = struct{}{}
+	}
+
+	inRemote := make(map[string]struct{}, len(remote.Videos))
+	for _, video := range remote.Videos {
+		inRemote[video.VideoID] = struct{}{}
+
+		if _, ok := inLocal[video.VideoID]; ok {
+			both = append(both, video.VideoID)
+			continue
+		}
+
+		if _, ok := synced[video.VideoID]; ok {
+			if err := GetClient().RemovePlaylistVideo(id, video.IndexID); err != nil {
+				return added, removed, err
+			}
+
+			delete(inRemote, video.VideoID)
+			continue
+		}
+
+		AddWatchLater(SearchResult{
+			Type:          "video",
+			Title:         video.Title,
+			VideoID:       video.VideoID,
+			Author:        video.Author,
+			AuthorID:      video.AuthorID,
+			LengthSeconds: video.LengthSeconds,
+		})
+		added++
+
+		both = append(both, video.VideoID)
+	}
+
+	for _, info := range local {
+		if _, ok := inRemote[info.VideoID]; ok {
+			continue
+		}
+
+		if _, ok := synced[info.VideoID]; ok {
+			RemoveWatchLater(info.VideoID)
+			removed++
+
+			continue
+		}
+
+		if err := GetClient().AddPlaylistVideo(id, info.VideoID); err != nil {
+			return added, removed, err
+		}
+
+		both = append(both, info.VideoID)
+	}
+
+	// Videos which were added to the local list during the sync
+	// are not stored, so that they are added to the playlist
+	// by the next sync instead of being removed.
+	watchLaterLock.Lock()
+	watchLater.Synced = both
+	watchLaterLock.Unlock()
+
+	return added, removed, nil
+}
+
+// watchLaterPlaylist returns the ID of the account playlist which the Watch
+// Later list is synced with. The playlist is looked up by its ID from the
+// last sync and then by its title, and is created if it is not found.
+func watchLaterPlaylist() (string, error) {
+	playlists, err := GetClient().AuthPlaylists()
+	if err != nil {
+		return "", err
+	}
+
+	watchLaterLock.Lock()
+	id := watchLater.Playlist
+	watchLaterLock.Unlock()
+
+	found := ""
+	for _, playlist := range playlists {
+		if playlist.PlaylistID == id && playlist.Title == watchLaterTitle {
+			return id, nil
+		}
+
+		if found == "" && playlist.Title == watchLaterTitle {
+			found = playlist.PlaylistID
+		}
+	}
+
+	if found == "" {
+		found, err = GetClient().CreatePlaylist(watchLaterTitle, "private")
+		if err != nil {
+			return "", err
+		}
+		if found == "" {
+			return "", fmt.Errorf("Could not create the %s playlist", watchLaterTitle)
+		}
+	}
+
+	watchLaterLock.Lock()
+	watchLater.Playlist = found
+	watchLater.Synced = nil
+	watchLaterLock.Unlock()
+
+	return found, nil
+}
+
+// watchLaterIndex returns the position of the video in the Watch Later
+// list, or -1 if it is not in the list. It must be called with
+// watchLaterLock held.
+func watchLaterIndex(videoID string) int {
+	for i, info := range watchLater.Videos {
+		if info.VideoID == videoID {
+			return i
+		}
+	}
+
+	return -1
+}
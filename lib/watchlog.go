@@ -0,0 +1,337 @@
+package lib
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WatchEntry stores a single playback of a video.
+type WatchEntry struct {
+	VideoID   string `json:"videoId"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	MediaType string `json:"mediaType"`
+	Started   int64  `json:"started"`
+	Seconds   int64  `json:"seconds"`
+}
+
+// WatchStat stores the aggregated playback data of a channel, video or day.
+type WatchStat struct {
+	Name    string `json:"name"`
+	Plays   int    `json:"plays"`
+	Seconds int64  `json:"seconds"`
+}
+
+// WatchCount stores the number of plays of a video, and when it was last played.
+type WatchCount struct {
+	Plays      int
+	LastPlayed int64
+}
+
+// WatchStats stores the aggregated playback statistics.
+type WatchStats struct {
+	Plays           int         `json:"plays"`
+	TotalSeconds    int64       `json:"totalSeconds"`
+	WatchedSeconds  int64       `json:"watchedSeconds"`
+	ListenedSeconds int64       `json:"listenedSeconds"`
+	Channels        []WatchStat `json:"channels"`
+	Videos          []WatchStat `json:"videos"`
+	Days            []WatchStat `json:"days"`
+	Weeks           []WatchStat `json:"weeks"`
+}
+
+var (
+	watchLog     []WatchEntry
+	watchActive  bool
+	watchTime    time.Duration
+	watchUpdated time.Time
+	watchLock    sync.Mutex
+)
+
+const watchLogMax = 50000
+
+// SetupWatchLog loads the watch log.
+func SetupWatchLog() {
+	var entries []WatchEntry
+
+	watchfile, err := DataPath("watchlog.json")
+	if err != nil {
+		return
+	}
+
+	data, err := ioutil.ReadFile(watchfile)
+	if err != nil || len(data) == 0 {
+		return
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return
+	}
+
+	watchLock.Lock()
+	watchLog = entries
+	watchLock.Unlock()
+}
+
+// SaveWatchLog saves the watch log.
+func SaveWatchLog() {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+
+	watchfile, err := DataPath("watchlog.json")
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(watchLog, "", " ")
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(watchfile, data, 0664)
+}
+
+// WatchLog returns a copy of the watch log.
+func WatchLog() []WatchEntry {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+
+	return append([]WatchEntry{}, watchLog...)
+}
+
+// TrackWatchTime adds the time elapsed since it was last called to the
+// playing entry in the watch log, if the player is not paused.
+func TrackWatchTime() {
+	playing := !GetMPV().IsPaused() && !GetMPV().IsIdle()
+
+	watchLock.Lock()
+	defer watchLock.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(watchUpdated)
+	watchUpdated = now
+
+	if !watchActive || !playing || len(watchLog) == 0 || elapsed > 5*time.Second {
+		return
+	}
+
+	watchTime += elapsed
+	watchLog[len(watchLog)-1].Seconds = int64(watchTime.Seconds())
+}
+
+// GetWatchStats aggregates the watch log into total playback time, split
+// into time watched as video and listened as audio, and playback time per
+// channel, video, day and week.
+func GetWatchStats() WatchStats {
+	var stats WatchStats
+
+	channels := make(map[string]*WatchStat)
+	videos := make(map[string]*WatchStat)
+	days := make(map[string]*WatchStat)
+	weeks := make(map[string]*WatchStat)
+
+	add := func(stats map[string]*WatchStat, key, name string, seconds int64) {
+		stat, ok := stats[key]
+		if !ok {
+			stat = &WatchStat{Name: name}
+			stats[key] = stat
+		}
+
+		stat.Plays++
+		stat.Seconds += seconds
+	}
+
+	for _, entry := range WatchLog() {
+		stats.Plays++
+		stats.TotalSeconds += entry.Seconds
+
+		if entry.MediaType == "Audio" {
+			stats.ListenedSeconds += entry.Seconds
+		} else {
+			stats.WatchedSeconds += entry.Seconds
+		}
+
+		started := time.Unix(entry.Started, 0)
+		day := started.Format("2006-01-02")
+
+		year, week := started.ISOWeek()
+		weekName := fmt.Sprintf("%d-W%02d", year, week)
+
+		add(channels, entry.Author, entry.Author, entry.Seconds)
+		add(videos, entry.VideoID, entry.Title, entry.Seconds)
+		add(days, day, day, entry.Seconds)
+		add(weeks, weekName, weekName, entry.Seconds)
+	}
+
+	stats.Channels = sortedStats(channels, func(a, b WatchStat) bool {
+		return a.Seconds > b.Seconds
+	})
+	stats.Videos = sortedStats(videos, func(a, b WatchStat) bool {
+		if a.Plays == b.Plays {
+			return a.Seconds > b.Seconds
+		}
+
+		return a.Plays > b.Plays
+	})
+	stats.Days = sortedStats(days, func(a, b WatchStat) bool {
+		return a.Name < b.Name
+	})
+	stats.Weeks = sortedStats(weeks, func(a, b WatchStat) bool {
+		return a.Name < b.Name
+	})
+
+	return stats
+}
+
+// WatchCounts returns the number of plays of each video in
+// the watch log, and when it was last played, by video ID.
+func WatchCounts() map[string]WatchCount {
+	counts := make(map[string]WatchCount)
+
+	for _, entry := range WatchLog() {
+		count := counts[entry.VideoID]
+
+		count.Plays++
+		if entry.Started > count.LastPlayed {
+			count.LastPlayed = entry.Started
+		}
+
+		counts[entry.VideoID] = count
+	}
+
+	return counts
+}
+
+// PurgeWatchLog removes the playbacks which started within the
+// time range from the watch log, and returns the number removed.
+func PurgeWatchLog(from, to time.Time) int {
+	var kept []WatchEntry
+
+	watchLock.Lock()
+	defer watchLock.Unlock()
+
+	for i, entry := range watchLog {
+		started := time.Unix(entry.Started, 0)
+		if !started.Before(from) && started.Before(to) {
+			// The playing entry is the last one, and its
+			// playback time must not be added to another entry.
+			if i == len(watchLog)-1 {
+				watchActive = false
+			}
+
+			continue
+		}
+
+		kept = append(kept, entry)
+	}
+
+	purged := len(watchLog) - len(kept)
+	watchLog = kept
+
+	return purged
+}
+
+// ExportWatchLog writes the watch log as CSV.
+func ExportWatchLog(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	cw.Write([]string{"videoId", "title", "author", "mediaType", "started", "seconds"})
+
+	for _, entry := range WatchLog() {
+		cw.Write([]string{
+			entry.VideoID,
+			entry.Title,
+			entry.Author,
+			entry.MediaType,
+			time.Unix(entry.Started, 0).Format(time.RFC3339),
+			strconv.FormatInt(entry.Seconds, 10),
+		})
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ExportWatchStats writes the watch statistics as CSV.
+func ExportWatchStats(w io.Writer, stats WatchStats) error {
+	cw := csv.NewWriter(w)
+
+	cw.Write([]string{"type", "name", "plays", "seconds"})
+	cw.Write([]string{"total", "", strconv.Itoa(stats.Plays), strconv.FormatInt(stats.TotalSeconds, 10)})
+	cw.Write([]string{"watched", "", "", strconv.FormatInt(stats.WatchedSeconds, 10)})
+	cw.Write([]string{"listened", "", "", strconv.FormatInt(stats.ListenedSeconds, 10)})
+
+	for _, section := range []struct {
+		name  string
+		stats []WatchStat
+	}{
+		{"channel", stats.Channels},
+		{"video", stats.Videos},
+		{"day", stats.Days},
+		{"week", stats.Weeks},
+	} {
+		for _, stat := range section.stats {
+			cw.Write([]string{
+				section.name,
+				stat.Name,
+				strconv.Itoa(stat.Plays),
+				strconv.FormatInt(stat.Seconds, 10),
+			})
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// startWatchEntry adds a new entry for the loaded track to the watch log.
+func startWatchEntry(track map[string]string) {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+
+	watchLog = append(watchLog, WatchEntry{
+		VideoID:   track["videoid"],
+		Title:     track["title"],
+		Author:    track["author"],
+		MediaType: track["mediatype"],
+		Started:   time.Now().Unix(),
+	})
+	if len(watchLog) > watchLogMax {
+		watchLog = watchLog[len(watchLog)-watchLogMax:]
+	}
+
+	watchActive = true
+	watchTime = 0
+	watchUpdated = time.Now()
+}
+
+// stopWatchEntry stops tracking the playback time of the current entry.
+func stopWatchEntry() {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+
+	watchActive = false
+}
+
+// sortedStats returns the aggregated statistics as a sorted slice.
+func sortedStats(stats map[string]*WatchStat, less func(a, b WatchStat) bool) []WatchStat {
+	sorted := make([]WatchStat, 0, len(stats))
+	for _, stat := range stats {
+		sorted = append(sorted, *stat)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
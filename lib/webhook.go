@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookPayload stores the data that is sent to webhooks.
+type WebhookPayload struct {
+	Event string      `json:"event"`
+	Time  int64       `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// SendWebhook sends the event and its data as JSON to the
+// webhook URLs set with --webhook, in the background.
+func SendWebhook(event string, data interface{}) {
+	if webhooks == "" {
+		return
+	}
+
+	payload, err := json.Marshal(WebhookPayload{
+		Event: event,
+		Time:  time.Now().Unix(),
+		Data:  data,
+	})
+	if err != nil {
+		return
+	}
+
+	for _, hook := range strings.Split(webhooks, ",") {
+		hook = strings.TrimSpace(hook)
+		if hook == "" {
+			continue
+		}
+
+		go postWebhook(hook, payload)
+	}
+}
+
+// postWebhook posts the payload to the URL.
+func postWebhook(hook string, payload []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "invidtui")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	res.Body.Close()
+}
+
+// trackData returns the webhook data for a playing track.
+func trackData(data map[string][]string) map[string]string {
+	track := make(map[string]string)
+
+	for _, key := range []string{"title", "author", "videoid", "mediatype", "length"} {
+		if values, ok := data[key]; ok && len(values) > 0 {
+			track[key] = values[0]
+		}
+	}
+
+	return track
+}
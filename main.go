@@ -31,6 +31,25 @@ func main() {
 		return
 	}
 
+	err = lib.SetupDebug()
+	if err != nil {
+		errMessage(err.Error())
+		return
+	}
+
+	if cmd := lib.SendCommand(); cmd != "" {
+		reply, err := lib.SendControl(cmd)
+		if err != nil {
+			errMessage(err.Error())
+			return
+		}
+		if reply != "" {
+			fmt.Println(reply)
+		}
+
+		return
+	}
+
 	list, err := lib.ListInstances()
 	if err != nil {
 		errMessage(err.Error())
@@ -41,6 +60,28 @@ func main() {
 		return
 	}
 
+	opened, err := lib.OpenURL()
+	if err != nil {
+		errMessage(err.Error())
+		return
+	}
+	if opened {
+		return
+	}
+
+	if lib.IsCommand() {
+		output, err := lib.RunCommand()
+		if err != nil {
+			errMessage(err.Error())
+			return
+		}
+		if output != "" {
+			fmt.Println(output)
+		}
+
+		return
+	}
+
 	infoMessage("Authenticating...")
 	link, err := lib.CheckAuthConfig()
 	if err != nil {
@@ -52,7 +93,6 @@ func main() {
 		return
 	}
 
-	infoMessage("Starting MPV instance...")
 	err = lib.MPVStart()
 	if err != nil {
 		errMessage(err.Error())
@@ -62,6 +102,7 @@ func main() {
 	infoMessage("Querying invidious instances...")
 	err = lib.UpdateClient()
 	if err != nil {
+		lib.MPVWait()
 		lib.GetMPV().MPVStop(true)
 		errMessage(err.Error())
 		return
@@ -70,9 +111,27 @@ func main() {
 	infoMessage("")
 
 	lib.SetupHistory()
+	lib.SetupWatchLog()
+	lib.SetupPodcasts()
+	lib.SetupResume()
+	lib.SetupBrowser()
+	lib.SetupWatchLater()
 
-	ui.SetupUI()
+	if lib.DaemonMode() {
+		err = lib.RunDaemon()
+	} else {
+		err = ui.SetupUI()
+	}
+	if err != nil {
+		errMessage(err.Error())
+		return
+	}
 
 	lib.SaveHistory()
+	lib.SaveWatchLog()
+	lib.SavePodcasts()
+	lib.SaveResume()
+	lib.SaveBrowser()
+	lib.SaveWatchLater()
 	lib.SaveAuth()
 }
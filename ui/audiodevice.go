@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showAudioDevices shows a popup with the audio output devices of mpv,
+// to switch the audio output to the selected device.
+func showAudioDevices() {
+	devices, err := lib.GetMPV().AudioDevices()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	current := lib.GetMPV().AudioDevice()
+
+	App.QueueUpdateDraw(func() {
+		deviceTitle := tview.NewTextView()
+		deviceTitle.SetDynamicColors(true)
+		deviceTitle.SetTextAlign(tview.AlignCenter)
+		deviceTitle.SetText("[white::bu]Audio devices")
+		deviceTitle.SetBackgroundColor(tcell.ColorDefault)
+
+		devicePopup := tview.NewTable()
+		devicePopup.SetBorders(false)
+		devicePopup.SetSelectorWrap(true)
+		devicePopup.SetSelectable(true, false)
+		devicePopup.SetBackgroundColor(tcell.ColorDefault)
+		devicePopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			captureSendPlayerEvent(event)
+
+			switch event.Key() {
+			case tcell.KeyEscape:
+				exitFocus()
+
+			case tcell.KeyEnter:
+				row, _ := devicePopup.GetSelection()
+				exitFocus()
+
+				if device, ok := devicePopup.GetCell(row, 0).GetReference().(lib.AudioDevice); ok {
+					go setAudioDevice(device)
+				}
+			}
+
+			return event
+		})
+
+		for i, device := range devices {
+			name := "[blue::b]" + tview.Escape(device.Description)
+			if device.Name == current {
+				name = "[green::b]* " + tview.Escape(device.Description)
+			}
+
+			devicePopup.SetCell(i, 0, tview.NewTableCell(name).
+				SetExpansion(1).
+				SetReference(device).
+				SetSelectedStyle(mainStyle),
+			)
+
+			devicePopup.SetCell(i, 1, tview.NewTableCell("[pink]"+tview.Escape(device.Name)).
+				SetSelectable(true).
+				SetAlign(tview.AlignRight).
+				SetSelectedStyle(auxStyle),
+			)
+
+			if device.Name == current {
+				devicePopup.Select(i, 0)
+			}
+		}
+
+		deviceFlex := tview.NewFlex().
+			AddItem(deviceTitle, 1, 0, false).
+			AddItem(devicePopup, 10, 10, false).
+			SetDirection(tview.FlexRow)
+
+		MPage.AddAndSwitchToPage(
+			"audiodevices",
+			statusmodal(deviceFlex, devicePopup),
+			true,
+		).ShowPage("ui")
+
+		App.SetFocus(devicePopup)
+	})
+}
+
+// setAudioDevice switches the audio output to the device, and
+// saves it to the config file so that it is used on the next start.
+func setAudioDevice(device lib.AudioDevice) {
+	if err := lib.GetMPV().SetAudioDevice(device.Name); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if err := lib.SaveAudioDevice(device.Name); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Switched audio output to "+device.Description, false)
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"github.com/darkhz/invidtui/lib"
+	"github.com/gdamore/tcell/v2"
+)
+
+// toggleForceAudio toggles loading only the audio
+// stream of videos for all entries.
+func toggleForceAudio() {
+	lib.ToggleForceAudio()
+	setAudioOnlyIndicator()
+
+	if lib.ForceAudio() {
+		InfoMessage("Only audio will be loaded for videos", false)
+	} else {
+		InfoMessage("Videos will be loaded as selected", false)
+	}
+}
+
+// togglePlayingMedia switches the playing entry between
+// audio and video, without losing the playback position.
+func togglePlayingMedia() {
+	InfoMessage("Switching media type of the playing entry", true)
+
+	media, err := lib.TogglePlayingMedia()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Playing entry switched to "+media, false)
+}
+
+// setAudioOnlyIndicator shows an indicator in the status
+// bar if only audio is loaded for videos.
+func setAudioOnlyIndicator() {
+	var text string
+
+	if lib.ForceAudio() {
+		text = "[teal::b]AUDIO ONLY[-:-:-]"
+	}
+
+	setStatusIndicator("audio", text)
+}
+
+// isAudioPlayKey returns whether the key is Alt+Enter, which plays the
+// selected entry only as audio, once. Lists which load more results on
+// Enter ignore it.
+func isAudioPlayKey(event *tcell.EventKey) bool {
+	return event.Key() == tcell.KeyEnter && event.Modifiers()&tcell.ModAlt != 0
+}
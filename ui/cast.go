@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+var castPaused bool
+
+// ShowCastDevices searches for DLNA media renderers on the network, and
+// shows a popup to select the device to cast the selected video to.
+// Chromecast devices are not supported.
+func ShowCastDevices() {
+	var err error
+	var info lib.SearchResult
+	var entries []lib.SearchResult
+
+	App.QueueUpdateDraw(func() {
+		entries, err = getSelectedEntries()
+		if err == nil {
+			entries = filterEntries(entries, "video")
+			if entries != nil {
+				info = entries[0]
+			}
+		}
+	})
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if info.Type != "video" {
+		ErrorMessage(fmt.Errorf("Select a video to cast"))
+		return
+	}
+
+	InfoMessage("Searching for DLNA renderers", true)
+
+	devices, err := lib.DiscoverCastDevices(3 * time.Second)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Found "+fmt.Sprint(len(devices))+" DLNA renderer(s)", false)
+
+	App.QueueUpdateDraw(func() {
+		devicesTable := tview.NewTable()
+		devicesTable.SetSelectorWrap(true)
+		devicesTable.SetSelectable(true, false)
+		devicesTable.SetBackgroundColor(tcell.ColorDefault)
+		devicesTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEnter:
+				row, _ := devicesTable.GetSelection()
+				if device, ok := devicesTable.GetCell(row, 0).GetReference().(lib.CastDevice); ok {
+					go castVideo(device, info)
+				}
+
+				fallthrough
+
+			case tcell.KeyEscape:
+				exitFocus()
+			}
+
+			return event
+		})
+
+		for row, device := range devices {
+			devicesTable.SetCell(row, 0, tview.NewTableCell("[blue::b]"+tview.Escape(device.Name)).
+				SetExpansion(1).
+				SetReference(device).
+				SetSelectedStyle(mainStyle),
+			)
+
+			devicesTable.SetCell(row, 1, tview.NewTableCell("[pink]"+lib.GetHostname(device.Location)).
+				SetSelectedStyle(auxStyle),
+			)
+		}
+
+		title := tview.NewTextView()
+		title.SetDynamicColors(true)
+		title.SetTextAlign(tview.AlignCenter)
+		title.SetText("[::bu]Cast " + tview.Escape(info.Title) + " to")
+		title.SetBackgroundColor(tcell.ColorDefault)
+
+		devicesFlex := tview.NewFlex().
+			AddItem(title, 1, 0, false).
+			AddItem(devicesTable, 10, 10, true).
+			SetDirection(tview.FlexRow)
+
+		MPage.AddAndSwitchToPage(
+			"cast",
+			statusmodal(devicesFlex, devicesTable),
+			true,
+		).ShowPage("ui")
+
+		App.SetFocus(devicesTable)
+	})
+}
+
+// castVideo casts the video to the device, and pauses the local player.
+func castVideo(device lib.CastDevice, info lib.SearchResult) {
+	InfoMessage("Casting "+tview.Escape(info.Title)+" to "+tview.Escape(device.Name), true)
+
+	title, err := lib.CastVideo(device, info.VideoID, false)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if isPlaying() && !lib.GetMPV().IsPaused() {
+		lib.GetMPV().CyclePaused()
+		sendPlayerEvent()
+	}
+
+	castPaused = false
+	updateCastIndicator(device.Name, title)
+
+	InfoMessage("Casting to "+tview.Escape(device.Name), false)
+}
+
+// captureCastEvent handles the pause and stop keys while casting,
+// and returns whether the event was handled.
+func captureCastEvent(event *tcell.EventKey) bool {
+	device, title := lib.CastingTo()
+	if device == nil {
+		return false
+	}
+
+	switch event.Rune() {
+	case ' ':
+		go func() {
+			if err := lib.CastPause(!castPaused); err != nil {
+				ErrorMessage(err)
+				return
+			}
+
+			castPaused = !castPaused
+			updateCastIndicator(device.Name, title)
+		}()
+
+	case 'S':
+		go func() {
+			if err := lib.CastStop(); err != nil {
+				ErrorMessage(err)
+			}
+
+			updateCastIndicator("", "")
+			InfoMessage("Stopped casting", false)
+		}()
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// updateCastIndicator shows the device and title being cast in the status bar.
+func updateCastIndicator(name, title string) {
+	var text string
+
+	if name != "" {
+		state := "Playing"
+		if castPaused {
+			state = "Paused"
+		}
+
+		text = "[blue::b]Cast: " + tview.Escape(name) + " - " + tview.Escape(title) + " (" + state + ")[-:-:-]"
+	}
+
+	App.QueueUpdateDraw(func() {
+		setStatusIndicator("cast", text)
+	})
+}
@@ -5,10 +5,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 )
 
 var (
@@ -19,11 +21,14 @@ var (
 	chViewFlex    *tview.Flex
 	chPageMark    *tview.TextView
 	chVideoTable  *tview.Table
+	chVideoList   *listContent
 	chPlistTable  *tview.Table
 	chSearchTable *tview.Table
+	chAbout       *tview.TextView
 	chPrevItem    tview.Primitive
 
 	chanID           string
+	chName           string
 	currType         string
 	chPrevPage       string
 	chSearchString   string
@@ -31,9 +36,14 @@ var (
 	chVideoLoaded    bool
 	chPlaylistLoaded bool
 	chSearchLoaded   bool
+	chAboutLoaded    bool
 	chLock           sync.Mutex
 )
 
+// channelTabs is the text of the channel tabs, with a
+// placeholder for the name of the videos tab.
+const channelTabs = `[::b]Channel[-:-:-] ["video"][darkcyan]%s[""] ["playlist"][darkcyan]Playlists[""] ["search"][darkcyan]Search[""] ["about"][darkcyan]About[""]`
+
 // setupViewChannel sets up the channel view.
 func setupViewChannel() {
 	var tables []*tview.Table
@@ -56,7 +66,10 @@ func setupViewChannel() {
 		tables = append(tables, table)
 	}
 
+	chVideoList = newListContent()
+
 	chVideoTable = tables[0]
+	chVideoTable.SetContent(chVideoList)
 	chPlistTable = tables[1]
 	chSearchTable = tables[2]
 
@@ -70,21 +83,39 @@ func setupViewChannel() {
 	chDesc.SetTextAlign(tview.AlignCenter)
 	chDesc.SetBackgroundColor(tcell.ColorDefault)
 
+	chAbout = tview.NewTextView()
+	chAbout.SetWrap(true)
+	chAbout.SetWordWrap(true)
+	chAbout.SetDynamicColors(true)
+	chAbout.SetBackgroundColor(tcell.ColorDefault)
+	chAbout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		captureSendPlayerEvent(event)
+
+		switch event.Key() {
+		case tcell.KeyTab:
+			switchChannelTabs()
+
+		case tcell.KeyEscape:
+			exitChannelView()
+		}
+
+		return event
+	})
+
 	chPageMark = tview.NewTextView()
 	chPageMark.SetWrap(false)
 	chPageMark.SetRegions(true)
 	chPageMark.SetDynamicColors(true)
 	chPageMark.SetBackgroundColor(tcell.ColorDefault)
-	chPageMark.SetText(
-		`[::b]Channel[-:-:-] ["video"][darkcyan]Videos[""] ["playlist"][darkcyan]Playlists[""] ["search"][darkcyan]Search[""]`,
-	)
+	chPageMark.SetText(fmt.Sprintf(channelTabs, "Videos"))
 
 	chVbox = getVbox()
 
 	chPages = tview.NewPages().
 		AddPage("video", chVideoTable, true, false).
 		AddPage("playlist", chPlistTable, true, false).
-		AddPage("search", chSearchTable, true, false)
+		AddPage("search", chSearchTable, true, false).
+		AddPage("about", chAbout, true, false)
 
 	chViewFlex = tview.NewFlex().
 		AddItem(chPageMark, 1, 0, false).
@@ -129,11 +160,13 @@ func ViewChannel(vtype string, newlist, noload bool) error {
 		chVideoTable.Clear()
 		chPlistTable.Clear()
 		chSearchTable.Clear()
+		chAbout.Clear()
 
 		for _, v := range []string{
 			"video",
 			"playlist",
 			"search",
+			"about",
 		} {
 			setChPageLoaded(v, false)
 		}
@@ -145,6 +178,7 @@ func ViewChannel(vtype string, newlist, noload bool) error {
 
 	chPrevPage, chPrevItem = VPage.GetFrontPage()
 
+	setChannelTabs()
 	chPageMark.Highlight(vtype)
 	chPages.SwitchToPage(vtype)
 
@@ -219,16 +253,16 @@ func viewChannel(info lib.SearchResult, vtype string, newlist bool) {
 			return
 		}
 
-		_, item := chPages.GetFrontPage()
-		chTable := item.(*tview.Table)
+		chTable := getChTable(vtype)
 
 		_, _, width, _ := ResultsList.GetRect()
 
 		if newlist {
 			desc := strings.ReplaceAll(result.Description, "\n", " ")
-			desclen := len(desc)
+			desclen := runewidth.StringWidth(desc)
 
 			rmdesc()
+			chDescHeight = 0
 
 			if desclen > 0 {
 				s := 2
@@ -238,9 +272,14 @@ func viewChannel(info lib.SearchResult, vtype string, newlist bool) {
 					s--
 				}
 
+				chDescHeight = s
+
 				insdesc(s)
+				resizeDescription(chViewFlex, chDesc, chVbox, s)
 			}
 
+			chName = result.Author
+
 			chDesc.SetText(desc)
 			chTitle.SetText("[::bu]" + result.Author)
 
@@ -299,31 +338,25 @@ func listChannelVideos(info lib.SearchResult, pos, rows, width int, result lib.C
 			pos = (rows + i) - skipped
 		}
 
-		if v.LengthSeconds == 0 {
+		if v.LengthSeconds == 0 && !v.IsUpcoming {
 			skipped++
 			continue
 		}
 
 		sref := lib.SearchResult{
-			Type:     "video",
-			Title:    v.Title,
-			VideoID:  v.VideoID,
-			AuthorID: result.ChannelID,
-			Author:   result.Author,
+			Type:              "video",
+			Title:             v.Title,
+			VideoID:           v.VideoID,
+			AuthorID:          result.ChannelID,
+			Author:            result.Author,
+			LengthSeconds:     v.LengthSeconds,
+			IsUpcoming:        v.IsUpcoming,
+			PremiereTimestamp: v.PremiereTimestamp,
+			LiveNow:           v.LiveNow,
+			Premium:           v.Premium,
 		}
 
-		chVideoTable.SetCell((rows+i)-skipped, 0, tview.NewTableCell("[blue::b]"+tview.Escape(v.Title)).
-			SetExpansion(1).
-			SetReference(sref).
-			SetMaxWidth((width / 4)).
-			SetSelectedStyle(mainStyle),
-		)
-
-		chVideoTable.SetCell((rows+i)-skipped, 1, tview.NewTableCell("[pink]"+lib.FormatDuration(v.LengthSeconds)).
-			SetSelectable(true).
-			SetAlign(tview.AlignRight).
-			SetSelectedStyle(auxStyle),
-		)
+		chVideoList.Append(sref)
 	}
 
 	InfoMessage("Video entries loaded", false)
@@ -528,16 +561,15 @@ func chTableEvents(event *tcell.EventKey) {
 
 	switch event.Key() {
 	case tcell.KeyEnter:
-		loadMoreChannelResults()
+		if !isAudioPlayKey(event) {
+			loadMoreChannelResults()
+		}
 
 	case tcell.KeyTab:
 		switchChannelTabs()
 
 	case tcell.KeyEscape:
-		setChExited(true)
-		VPage.SwitchToPage(chPrevPage)
-		App.SetFocus(chPrevItem)
-		ResultsList.SetSelectable(true, false)
+		exitChannelView()
 	}
 
 	switch event.Rune() {
@@ -558,6 +590,51 @@ func chTableEvents(event *tcell.EventKey) {
 
 	case 'C':
 		ShowComments()
+
+	case 'E':
+		togglePodcast()
+	}
+}
+
+// togglePodcast sets or unsets the channel as a podcast, and reloads
+// its videos, which are listed oldest first as episodes for podcasts.
+func togglePodcast() {
+	if lib.TogglePodcast(chanID, chName) {
+		InfoMessage("Listing "+chName+" as a podcast", false)
+	} else {
+		InfoMessage("Listing "+chName+" as a channel", false)
+	}
+
+	setChannelTabs()
+
+	chVideoTable.Clear()
+	setChPageLoaded("video", false)
+
+	if getCurrType() != "video" {
+		return
+	}
+
+	info := lib.SearchResult{
+		Type:     "channel",
+		AuthorID: chanID,
+	}
+
+	go viewChannel(info, "video", true)
+}
+
+// setChannelTabs sets the channel tabs, naming the videos
+// tab after episodes if the channel is a podcast.
+func setChannelTabs() {
+	highlights := chPageMark.GetHighlights()
+
+	if lib.IsPodcast(chanID) {
+		chPageMark.SetText(fmt.Sprintf(channelTabs, "Episodes"))
+	} else {
+		chPageMark.SetText(fmt.Sprintf(channelTabs, "Videos"))
+	}
+
+	if highlights != nil {
+		chPageMark.Highlight(highlights...)
 	}
 }
 
@@ -594,6 +671,9 @@ func switchChannelTabs() {
 		ctype = "search"
 
 	case "search":
+		ctype = "about"
+
+	case "about":
 		ctype = "video"
 	}
 
@@ -602,8 +682,17 @@ func switchChannelTabs() {
 	chPageMark.Highlight(ctype)
 	chPages.SwitchToPage(ctype)
 
-	_, item := chPages.GetFrontPage()
-	table := item.(*tview.Table)
+	if ctype == "about" {
+		App.SetFocus(chAbout)
+
+		if !isChPageLoaded(ctype) {
+			go viewChannelAbout(chanID)
+		}
+
+		return
+	}
+
+	table := getChTable(ctype)
 
 	App.SetFocus(table)
 	table.SetSelectable(true, false)
@@ -623,6 +712,75 @@ func switchChannelTabs() {
 	}
 }
 
+// viewChannelAbout loads and displays information about the channel.
+func viewChannelAbout(id string) {
+	InfoMessage("Loading channel information", true)
+
+	result, err := lib.GetClient().ChannelAbout(id)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Loaded channel information", false)
+
+	var text strings.Builder
+
+	for _, field := range [][]string{
+		{"Subscribers", lib.FormatNumber(result.SubCount)},
+		{"Total views", lib.FormatNumber(int(result.TotalViews))},
+		{"Joined", time.Unix(result.Joined, 0).Format("January 2, 2006")},
+		{"URL", "https://www.youtube.com/channel/" + result.ChannelID},
+	} {
+		text.WriteString("[::b]" + field[0] + ":[-:-:-] " + tview.Escape(field[1]) + "\n")
+	}
+
+	if links := lib.ChannelLinks(result.Description); links != nil {
+		text.WriteString("\n[::bu]Links[-:-:-]\n")
+
+		for _, link := range links {
+			text.WriteString(tview.Escape(link) + "\n")
+		}
+	}
+
+	if result.Description != "" {
+		text.WriteString("\n[::bu]Description[-:-:-]\n")
+		text.WriteString(tview.Escape(result.Description))
+	}
+
+	App.QueueUpdateDraw(func() {
+		if id != chanID {
+			return
+		}
+
+		chAbout.SetText(text.String())
+		chAbout.ScrollToBeginning()
+
+		setChPageLoaded("about", true)
+	})
+}
+
+// exitChannelView exits the channel view and returns to the previous page.
+func exitChannelView() {
+	setChExited(true)
+	VPage.SwitchToPage(chPrevPage)
+	App.SetFocus(chPrevItem)
+	ResultsList.SetSelectable(true, false)
+}
+
+// getChTable returns the table for the provided channel page type.
+func getChTable(vtype string) *tview.Table {
+	switch vtype {
+	case "playlist":
+		return chPlistTable
+
+	case "search":
+		return chSearchTable
+	}
+
+	return chVideoTable
+}
+
 func focusChTable(focus bool, chTable *tview.Table) {
 	if !focus {
 		return
@@ -646,6 +804,9 @@ func isChPageLoaded(vtype string) bool {
 
 	case "search":
 		return chSearchLoaded
+
+	case "about":
+		return chAboutLoaded
 	}
 
 	return false
@@ -664,6 +825,9 @@ func setChPageLoaded(vtype string, loaded bool) {
 
 	case "search":
 		chSearchLoaded = loaded
+
+	case "about":
+		chAboutLoaded = loaded
 	}
 }
 
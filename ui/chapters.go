@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showChapters shows a popup with the chapters of the playing
+// file, to seek to the start of the selected chapter.
+func showChapters() {
+	chapters := lib.GetMPV().ChapterList()
+	if len(chapters) == 0 {
+		InfoMessage("No chapters found", false)
+		return
+	}
+
+	current := lib.CurrentChapter(chapters, lib.GetMPV().TimePosition())
+
+	App.QueueUpdateDraw(func() {
+		chapterTitle := tview.NewTextView()
+		chapterTitle.SetDynamicColors(true)
+		chapterTitle.SetTextAlign(tview.AlignCenter)
+		chapterTitle.SetText("[white::bu]Chapters")
+		chapterTitle.SetBackgroundColor(tcell.ColorDefault)
+
+		chapterPopup := tview.NewTable()
+		chapterPopup.SetBorders(false)
+		chapterPopup.SetSelectorWrap(true)
+		chapterPopup.SetSelectable(true, false)
+		chapterPopup.SetBackgroundColor(tcell.ColorDefault)
+		chapterPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			captureSendPlayerEvent(event)
+
+			switch event.Key() {
+			case tcell.KeyEscape:
+				exitFocus()
+
+			case tcell.KeyEnter:
+				row, _ := chapterPopup.GetSelection()
+				exitFocus()
+
+				if chapter, ok := chapterPopup.GetCell(row, 0).GetReference().(lib.Chapter); ok {
+					go seekChapter(chapter)
+				}
+			}
+
+			return event
+		})
+
+		for i, chapter := range chapters {
+			title := "[blue::b]" + tview.Escape(chapter.Title)
+			if i == current {
+				title = "[green::b]* " + tview.Escape(chapter.Title)
+			}
+
+			chapterPopup.SetCell(i, 0, tview.NewTableCell(title).
+				SetExpansion(1).
+				SetReference(chapter).
+				SetSelectedStyle(mainStyle),
+			)
+
+			chapterPopup.SetCell(i, 1, tview.NewTableCell("[pink]"+lib.FormatDuration(int64(chapter.Start))).
+				SetSelectable(true).
+				SetAlign(tview.AlignRight).
+				SetSelectedStyle(auxStyle),
+			)
+		}
+
+		if current >= 0 {
+			chapterPopup.Select(current, 0)
+		}
+
+		chapterFlex := tview.NewFlex().
+			AddItem(chapterTitle, 1, 0, false).
+			AddItem(chapterPopup, 10, 10, false).
+			SetDirection(tview.FlexRow)
+
+		MPage.AddAndSwitchToPage(
+			"chapters",
+			statusmodal(chapterFlex, chapterPopup),
+			true,
+		).ShowPage("ui")
+
+		App.SetFocus(chapterPopup)
+	})
+}
+
+// seekChapter seeks to the start of the chapter.
+func seekChapter(chapter lib.Chapter) {
+	if err := lib.GetMPV().SeekChapter(chapter); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Playing chapter "+chapter.Title, false)
+}
+
+// switchChapter seeks to the start of the next chapter,
+// or the previous chapter if prev is set.
+func switchChapter(prev bool) {
+	var err error
+
+	if prev {
+		err = lib.GetMPV().PrevChapter()
+	} else {
+		err = lib.GetMPV().NextChapter()
+	}
+	if err != nil {
+		InfoMessage(err.Error(), false)
+	}
+}
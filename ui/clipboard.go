@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// startClipboardWatcher queues the video and playlist
+// URLs which are copied to the clipboard, if --watch-clipboard is set.
+func startClipboardWatcher() {
+	if !lib.WatchClipboard() {
+		return
+	}
+
+	if err := lib.WatchClipboardURLs(queueClipboardURL); err != nil {
+		ErrorMessage(fmt.Errorf("Cannot watch the clipboard: %s", err.Error()))
+		return
+	}
+
+	setStatusIndicator("clipboard", "[blue::b]CLIPBOARD[-:-:-]")
+}
+
+// queueClipboardURL adds the media from a URL copied to the clipboard
+// to the queue. The queued entries can be removed again with Undo.
+func queueClipboardURL(uri string) {
+	info, err := urlMediaInfo(uri)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Loading "+info.Type+" from the clipboard", true)
+
+	if err := addRateLimit.Acquire(context.Background(), 1); err != nil {
+		return
+	}
+	defer addRateLimit.Release(1)
+
+	if err := lib.MPVWait(); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	lib.JobReset("video")
+
+	prev := lib.GetMPV().PlaylistCount()
+
+	if err := loadEntry(info, false, false); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	list := updatePlaylist()
+	if prev > len(list) {
+		prev = len(list)
+	}
+
+	added := list[prev:]
+	if len(added) == 0 {
+		return
+	}
+
+	title := added[0].Title
+	if len(added) > 1 {
+		title = info.Type + " with " + strconv.Itoa(len(added)) + " entries"
+	}
+
+	addClipboardUndo(title, added)
+
+	InfoMessage("Queued "+title+" from the clipboard (Ctrl+U to undo)", false)
+}
+
+// addClipboardUndo stores the entries which were queued from the
+// clipboard, so that they can be removed from the queue again.
+func addClipboardUndo(title string, added []PlaylistData) {
+	filenames := make(map[string]int, len(added))
+	for _, entry := range added {
+		filenames[entry.Filename]++
+	}
+
+	addUndo("queueing of "+title, func() error {
+		list := updatePlaylist()
+
+		for i := len(list) - 1; i >= 0; i-- {
+			if filenames[list[i].Filename] > 0 {
+				filenames[list[i].Filename]--
+				lib.GetMPV().PlaylistDelete(i)
+			}
+		}
+
+		return nil
+	})
+}
+
+// copyShareLink copies a link to the playing video, which
+// starts at the current playback position, to the clipboard.
+func copyShareLink() {
+	id := lib.GetMPV().PlayingVideoID()
+	if id == "" {
+		ErrorMessage(fmt.Errorf("No video is playing"))
+		return
+	}
+
+	link := lib.GetShareLink(id, lib.GetMPV().TimePosition())
+
+	if err := lib.CopyToClipboard(link); err != nil {
+		ErrorMessage(fmt.Errorf("%s, link: %s", err.Error(), link))
+		return
+	}
+
+	InfoMessage("Copied "+link, false)
+}
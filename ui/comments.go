@@ -63,7 +63,7 @@ func showComments() {
 		Foreground(tcell.Color16).
 		Background(tcell.ColorWhite),
 	)
-	CommentsView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	CommentsView.SetInputCapture(contextCapture("comments", func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyEscape:
 			closeCommentView()
@@ -75,10 +75,18 @@ func showComments() {
 			if node.GetLevel() > 2 {
 				node.GetParent().SetExpanded(!node.GetParent().IsExpanded())
 			}
+
+		case 'g':
+			jumpComment(CommentsView, false)
+			return nil
+
+		case 'G':
+			jumpComment(CommentsView, true)
+			return nil
 		}
 
 		return event
-	})
+	}))
 	CommentsView.SetSelectedFunc(func(node *tview.TreeNode) {
 		var selectedNode, removeNode *tview.TreeNode
 
@@ -191,22 +199,33 @@ func closeCommentView() {
 
 	exitFocus()
 	popupStatus(false)
-	lib.CommentCancel()
+	lib.JobCancel("comments")
 }
 
 // addCommentNode adds a comment node.
 func addCommentNode(node *tview.TreeNode, comment lib.CommentsInfo) *tview.TreeNode {
 	authorInfo := "- [purple::bu]" + comment.Author + "[-:-:-]"
-	authorInfo += " [grey::b]" + lib.FormatPublished(comment.PublishedText) + "[-:-:-]"
+	if comment.AuthorIsChannelOwner {
+		authorInfo = "- [black:plum:b] " + comment.Author + " [-:-:-]"
+	}
+
+	authorInfo += " [grey::b]" + lib.FormatPublished(comment.PublishedText, comment.Published) + "[-:-:-]"
+	if comment.IsEdited {
+		authorInfo += " [grey::i](edited)[-:-:-]"
+	}
 	if comment.Verified {
 		authorInfo += " [aqua::b](Verified)[-:-:-]"
 	}
-	if comment.AuthorIsChannelOwner {
-		authorInfo += " [plum::b](Owner)"
+	if comment.IsPinned {
+		authorInfo += " [yellow::b](Pinned)[-:-:-]"
+	}
+	if comment.CreatorHeart != nil {
+		authorInfo += " [red::b]♥ by " + tview.Escape(comment.CreatorHeart.CreatorName) + "[-:-:-]"
 	}
-	authorInfo += " [red::b](" + strconv.Itoa(comment.LikeCount) + " likes)"
+	authorInfo += " [red::b](" + lib.FormatNumber(comment.LikeCount) + " likes)"
 
-	commentNode := tview.NewTreeNode(authorInfo)
+	commentNode := tview.NewTreeNode(authorInfo).
+		SetReference(comment)
 	for _, line := range splitLines(comment.Content) {
 		commentNode.AddChild(
 			tview.NewTreeNode(" " + line).
@@ -231,6 +250,32 @@ func addCommentNode(node *tview.TreeNode, comment lib.CommentsInfo) *tview.TreeN
 	return commentNode
 }
 
+// jumpComment selects the first or last comment of the
+// thread which contains the selected comment.
+func jumpComment(view *tview.TreeView, bottom bool) {
+	node := view.GetCurrentNode()
+	if node == nil || node.GetParent() == nil {
+		return
+	}
+
+	var jump *tview.TreeNode
+
+	for _, child := range node.GetParent().GetChildren() {
+		if _, ok := child.GetReference().(lib.CommentsInfo); !ok {
+			continue
+		}
+
+		jump = child
+		if !bottom {
+			break
+		}
+	}
+
+	if jump != nil {
+		view.SetCurrentNode(jump)
+	}
+}
+
 // addCommentContinuation checks if there are more comments and adds a continuation button.
 func addCommentContinuation(node *tview.TreeNode, comments lib.CommentResult) {
 	if comments.Continuation == "" {
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"github.com/darkhz/invidtui/lib"
+	"github.com/gdamore/tcell/v2"
+)
+
+// setupStyles sets up the styles of the selected list entries.
+func setupStyles() {
+	mainStyle = tcell.Style{}.
+		Foreground(tcell.GetColor(lib.ThemeColor("selected-foreground", "blue"))).
+		Background(tcell.GetColor(lib.ThemeColor("selected-background", "white"))).
+		Attributes(tcell.AttrBold)
+
+	auxStyle = tcell.Style{}.
+		Attributes(tcell.AttrBold)
+}
+
+// watchConfig reloads the config file whenever it is modified.
+func watchConfig() {
+	lib.WatchConfig(func() {
+		App.QueueUpdateDraw(reloadConfig)
+	})
+}
+
+// reloadConfig applies the modified config file. The theme colors
+// apply to entries which are loaded after the reload.
+func reloadConfig() {
+	var remaps map[string]map[keyBinding]keyBinding
+
+	err := lib.ReloadConfig(func(keybinds map[string]map[string]string) error {
+		var err error
+
+		remaps, err = parseKeybinds(keybinds)
+
+		return err
+	})
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	keyRemaps = remaps
+	setupStyles()
+
+	InfoMessage("Config reloaded", false)
+}
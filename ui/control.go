@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// setupControl registers the control commands and starts
+// listening on the control socket. The stop, queue and play commands
+// are handled by the interface, so that the player view, the undo
+// history and the play history are updated.
+func setupControl() error {
+	lib.RegisterPlayerControl(sendPlayerEvent, func() {
+		App.QueueUpdate(func() {
+			StopUI(false)
+		})
+	})
+
+	for name, handler := range map[string]lib.ControlHandler{
+		"stop":        controlStop,
+		"queue":       controlQueue(true, false),
+		"queue-audio": controlQueue(true, true),
+		"play":        controlQueue(false, false),
+		"play-audio":  controlQueue(false, true),
+	} {
+		lib.RegisterControl(name, handler)
+	}
+
+	return lib.StartControl()
+}
+
+// controlStop stops the player and clears the queue.
+func controlStop(args []string) (string, error) {
+	if !isPlaying() {
+		return "", fmt.Errorf("Nothing is playing")
+	}
+
+	addQueueClearUndo()
+	SetPlayer(false)
+	sendPlaylistExit()
+
+	return "", nil
+}
+
+// controlQueue returns a handler which loads a video/playlist URL or ID.
+// If queue is set, the media is appended to the queue, otherwise it is
+// played immediately.
+func controlQueue(queue, audio bool) lib.ControlHandler {
+	return func(args []string) (string, error) {
+		if len(args) != 1 {
+			return "", fmt.Errorf("Usage: <command> <url or id>")
+		}
+
+		info, err := urlMediaInfo(args[0])
+		if err != nil {
+			return "", err
+		}
+
+		go PlaySelected(audio, !queue, info)
+
+		if queue {
+			return "Queued " + args[0], nil
+		}
+
+		return "Playing " + args[0], nil
+	}
+}
+
+// runKeyHook runs the user-defined hook for Alt+<key>, if it exists,
+// with the selected entry as its input. It returns whether a hook was run.
+func runKeyHook(key rune) bool {
+	name := "key_alt-" + string(key)
+	if _, ok := lib.HookPath(name); !ok {
+		return false
+	}
+
+	info, _ := getListReference()
+
+	go func() {
+		if err := lib.RunKeyHook(name, info); err != nil {
+			ErrorMessage(err)
+		}
+	}()
+
+	return true
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
@@ -20,7 +21,8 @@ var (
 	dashPrevPage string
 	dashPrevItem tview.Primitive
 
-	forceload bool
+	forceload   bool
+	dashLoading bool
 )
 
 const (
@@ -42,7 +44,9 @@ func ShowDashboard() {
 
 		switch event.Key() {
 		case tcell.KeyEnter:
-			go loadFeed(true, false)
+			if !isAudioPlayKey(event) {
+				go loadFeed(true, false)
+			}
 		}
 
 		switch event.Rune() {
@@ -124,17 +128,17 @@ func ShowDashboard() {
 		case "feed":
 			App.SetFocus(dashFeed)
 			dashPages.SwitchToPage("feed")
-			go loadFeed(false, !forceload && dashFeed.GetRowCount() > 0)
+			go loadFeed(false, dashLoading || !forceload && dashFeed.GetRowCount() > 0)
 
 		case "playlist":
 			App.SetFocus(dashPlaylists)
 			dashPages.SwitchToPage("playlist")
-			go loadPlaylists(!forceload && dashPlaylists.GetRowCount() > 0)
+			go loadPlaylists(dashLoading || !forceload && dashPlaylists.GetRowCount() > 0)
 
 		case "subscription":
 			App.SetFocus(dashSubscriptions)
 			dashPages.SwitchToPage("subscription")
-			go loadSubscriptions(!forceload && dashSubscriptions.GetRowCount() > 0)
+			go loadSubscriptions(dashLoading || !forceload && dashSubscriptions.GetRowCount() > 0)
 		}
 
 		forceload = false
@@ -221,8 +225,6 @@ func ShowAuthPage() {
 
 // loadFeed loads the user's feed.
 func loadFeed(getmore, loadskip bool) {
-	var skipped int
-
 	if loadskip {
 		return
 	}
@@ -235,6 +237,16 @@ func loadFeed(getmore, loadskip bool) {
 		return
 	}
 
+	showFeed(feed, getmore)
+
+	InfoMessage("Feed loaded", false)
+}
+
+// showFeed shows the feed videos. If getmore is set, the videos
+// are appended to the ones already shown.
+func showFeed(feed lib.FeedResult, getmore bool) {
+	var skipped int
+
 	App.QueueUpdateDraw(func() {
 		if !getmore {
 			dashFeed.Clear()
@@ -246,7 +258,7 @@ func loadFeed(getmore, loadskip bool) {
 		rows := dashFeed.GetRowCount()
 
 		for i, video := range feed.Videos {
-			if video.LengthSeconds == 0 {
+			if video.LengthSeconds == 0 && !video.IsUpcoming {
 				skipped++
 			}
 
@@ -255,21 +267,27 @@ func loadFeed(getmore, loadskip bool) {
 			}
 
 			sref := lib.SearchResult{
-				Type:     "video",
-				Title:    video.Title,
-				VideoID:  video.VideoID,
-				AuthorID: video.AuthorID,
-				Author:   video.Author,
+				Type:              "video",
+				Title:             video.Title,
+				VideoID:           video.VideoID,
+				AuthorID:          video.AuthorID,
+				Author:            video.Author,
+				LengthSeconds:     video.LengthSeconds,
+				IsUpcoming:        video.IsUpcoming,
+				PremiereTimestamp: video.PremiereTimestamp,
+				LiveNow:           video.LiveNow,
+				AuthorVerified:    video.AuthorVerified,
+				Premium:           video.Premium,
 			}
 
-			dashFeed.SetCell((rows+i)-skipped, 0, tview.NewTableCell("[blue::b]"+tview.Escape(video.Title)).
+			dashFeed.SetCell((rows+i)-skipped, 0, tview.NewTableCell("[blue::b]"+tview.Escape(video.Title)+entryBadges(sref)).
 				SetExpansion(1).
 				SetReference(sref).
 				SetMaxWidth((width / 4)).
 				SetSelectedStyle(mainStyle),
 			)
 
-			dashFeed.SetCell((rows+i)-skipped, 1, tview.NewTableCell("[pink]"+lib.FormatDuration(video.LengthSeconds)).
+			dashFeed.SetCell((rows+i)-skipped, 1, tview.NewTableCell("[pink]"+entryLength(sref)).
 				SetSelectable(true).
 				SetAlign(tview.AlignRight).
 				SetSelectedStyle(auxStyle),
@@ -282,8 +300,6 @@ func loadFeed(getmore, loadskip bool) {
 			dashFeed.Select(pos, 0)
 		}
 	})
-
-	InfoMessage("Feed loaded", false)
 }
 
 // loadPlaylists loads the user's playlist.
@@ -300,9 +316,17 @@ func loadPlaylists(loadskip bool) {
 		return
 	}
 
+	showPlaylists(playlists)
+
+	InfoMessage("Playlists loaded", false)
+}
+
+// showPlaylists shows the user's playlists.
+func showPlaylists(playlists []lib.PlaylistResult) {
 	App.QueueUpdateDraw(func() {
 		_, _, width, _ := VPage.GetRect()
 
+		dashPlaylists.Clear()
 		dashPlaylists.SetSelectable(false, false)
 
 		for i, playlist := range playlists {
@@ -330,8 +354,6 @@ func loadPlaylists(loadskip bool) {
 
 		dashPlaylists.SetSelectable(true, false)
 	})
-
-	InfoMessage("Playlists loaded", false)
 }
 
 // loadSubscriptions loads the user's subscriptions.
@@ -348,9 +370,17 @@ func loadSubscriptions(loadskip bool) {
 		return
 	}
 
+	showSubscriptions(subscriptions)
+
+	InfoMessage("Subscriptions loaded", false)
+}
+
+// showSubscriptions shows the user's subscriptions.
+func showSubscriptions(subscriptions lib.SubResult) {
 	App.QueueUpdateDraw(func() {
 		_, _, width, _ := VPage.GetRect()
 
+		dashSubscriptions.Clear()
 		dashSubscriptions.SetSelectable(false, false)
 
 		for i, subscription := range subscriptions {
@@ -370,8 +400,79 @@ func loadSubscriptions(loadskip bool) {
 
 		dashSubscriptions.SetSelectable(true, false)
 	})
+}
 
-	InfoMessage("Subscriptions loaded", false)
+// loadDashboard loads the feed, playlists and subscriptions concurrently,
+// showing each section as soon as it is loaded.
+func loadDashboard() {
+	var loaded int
+	var errs []error
+	var lock sync.Mutex
+
+	sections := []func() error{
+		func() error {
+			feed, err := lib.GetClient().Feed(false)
+			if err == nil {
+				showFeed(feed, false)
+			}
+
+			return err
+		},
+		func() error {
+			playlists, err := lib.GetClient().AuthPlaylists()
+			if err == nil {
+				showPlaylists(playlists)
+			}
+
+			return err
+		},
+		func() error {
+			subscriptions, err := lib.GetClient().Subscriptions()
+			if err == nil {
+				showSubscriptions(subscriptions)
+			}
+
+			return err
+		},
+	}
+
+	InfoMessage("Loading dashboard (0/"+strconv.Itoa(len(sections))+")", true)
+
+	var wg sync.WaitGroup
+	for _, load := range sections {
+		wg.Add(1)
+
+		go func(load func() error) {
+			defer wg.Done()
+
+			err := load()
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			loaded++
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			if loaded < len(sections) {
+				InfoMessage("Loading dashboard ("+strconv.Itoa(loaded)+"/"+strconv.Itoa(len(sections))+")", true)
+			}
+		}(load)
+	}
+
+	wg.Wait()
+
+	App.QueueUpdateDraw(func() {
+		dashLoading = false
+	})
+
+	if errs != nil {
+		ErrorMessage(errs[0])
+		return
+	}
+
+	InfoMessage("Dashboard loaded", false)
 }
 
 // checkAuth checks whether the instance is authenticated.
@@ -406,12 +507,16 @@ func checkToken(input *tview.InputField) {
 	setDashboard()
 }
 
-// setDashboard sets the dashboard tabs.
+// setDashboard sets the dashboard tabs, and loads the dashboard.
 func setDashboard() {
 	App.QueueUpdateDraw(func() {
+		dashLoading = true
+
 		dashPageMark.SetText(dashMark + dashTabs)
 		dashPageMark.Highlight("feed")
 	})
+
+	loadDashboard()
 }
 
 // dashTableEvents handles the input events for the
@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/darkhz/invidtui/lib"
@@ -59,6 +59,7 @@ func ShowDownloadView() {
 }
 
 // ShowDownloadOptions shows the download options for the video.
+// If videos are marked, the selected option is applied to all of them.
 //
 //gocyclo:ignore
 func ShowDownloadOptions() {
@@ -66,10 +67,17 @@ func ShowDownloadOptions() {
 	var vpg, mpg string
 	var skipped, length int
 	var info lib.SearchResult
+	var entries []lib.SearchResult
 	var vtable tview.Primitive
 
 	App.QueueUpdateDraw(func() {
-		info, err = getListReference()
+		entries, err = getSelectedEntries()
+		if err == nil {
+			entries = filterEntries(entries, "video")
+			if entries != nil {
+				info = entries[0]
+			}
+		}
 
 		vpg, vtable = VPage.GetFrontPage()
 		mpg, _ = MPage.GetFrontPage()
@@ -79,7 +87,7 @@ func ShowDownloadOptions() {
 		return
 	}
 
-	if lib.DownloadFolder() == "" {
+	if lib.DownloadDir(false) == "" {
 		ErrorMessage(fmt.Errorf("No download folder specified"))
 		return
 	}
@@ -90,7 +98,7 @@ func ShowDownloadOptions() {
 
 	InfoMessage("Getting download options", true)
 
-	lib.VideoNewCtx()
+	lib.JobReset("video")
 
 	video, err := lib.GetClient().Video(info.VideoID)
 	if err != nil {
@@ -103,6 +111,11 @@ func ShowDownloadOptions() {
 		return
 	}
 
+	if lib.AutoDownloadFormat() {
+		downloadPreferred(entries, video)
+		return
+	}
+
 	optionsPopup := tview.NewTable()
 	optionsPopup.SetBorder(true)
 	optionsPopup.SetSelectorWrap(true)
@@ -110,25 +123,38 @@ func ShowDownloadOptions() {
 	optionsPopup.SetTitle(" [::b]Select download option ")
 	optionsPopup.SetBackgroundColor(tcell.ColorDefault)
 	optionsPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		exit := func() {
+			VPage.RemovePage("dloption")
+
+			if mpg != "ui" {
+				App.SetFocus(popup.primitive)
+			} else {
+				App.SetFocus(vtable)
+			}
+		}
+
+		row, _ := optionsPopup.GetSelection()
+		format, ok := optionsPopup.GetCell(row, 0).GetReference().(lib.FormatData)
+
 		switch event.Key() {
 		case tcell.KeyEnter:
-			row, _ := optionsPopup.GetSelection()
-			cell := optionsPopup.GetCell(row, 0)
-
-			if format, ok := cell.GetReference().(lib.FormatData); ok {
-				filename := info.Title + "." + format.Container
-				go startDownload(info.VideoID, format.Itag, filename)
+			if ok {
+				go downloadMatching(entries, video, format, func(_ int, entry lib.SearchResult, format lib.FormatData) string {
+					return lib.DownloadPath(lib.IsAudioFormat(format), entry.Title, entry.Author, entry.VideoID, format.Container)
+				})
 			}
 
 			fallthrough
 
 		case tcell.KeyEscape:
-			VPage.RemovePage("dloption")
+			exit()
+		}
 
-			if mpg != "ui" {
-				App.SetFocus(popup.primitive)
-			} else {
-				App.SetFocus(vtable)
+		switch event.Rune() {
+		case 's':
+			if ok {
+				exit()
+				saveDownload(entries, video, format)
 			}
 		}
 
@@ -143,14 +169,9 @@ func ShowDownloadOptions() {
 			rows := optionsPopup.GetRowCount()
 
 			for row, format := range formatData {
-				var minfo, size string
-				var optionInfo []string
-
-				if i != 0 {
-					minfo = " only"
-				} else {
-					minfo = " + audio"
+				var size string
 
+				if i == 0 {
 					clen := lib.GetDataFromURL(format.URL).Get("clen")
 					format.ContentLength, err = strconv.ParseInt(clen, 10, 64)
 					if err != nil {
@@ -158,37 +179,18 @@ func ShowDownloadOptions() {
 					}
 				}
 
-				mtype := strings.Split(strings.Split(format.Type, ";")[0], "/")
-				if (mtype[0] == "audio" && (format.Container == "" || format.Encoding == "")) ||
-					(mtype[0] == "video" && format.FPS == 0) {
-					skipped++
-					continue
-				}
-
 				if format.ContentLength == 0 {
 					size = "-"
 				} else {
 					size = strconv.FormatFloat(float64(format.ContentLength)/1024/1024, 'f', 2, 64)
 				}
 
-				optionInfo = []string{
-					"[red::b]" + mtype[0] + minfo + "[-:-:-]",
-					"[blue::b]" + size + " MB[-:-:-]",
-					"[purple::b]" + format.Container + "/" + format.Encoding + "[-:-:-]",
-				}
-				if mtype[0] != "audio" {
-					optionInfo = append(optionInfo, []string{
-						"[green::b]" + format.Resolution + "[-:-:-]",
-						"[yellow::b]" + strconv.Itoa(format.FPS) + "fps[-:-:-]",
-					}...)
-				} else {
-					optionInfo = append(optionInfo, []string{
-						"[lightpink::b]" + strconv.Itoa(format.AudioSampleRate) + "kHz[-:-:-]",
-						"[grey::b]" + strconv.Itoa(format.AudioChannels) + "ch[-:-:-]",
-					}...)
+				option, ok := formatOption(format, i == 0, size+" MB")
+				if !ok {
+					skipped++
+					continue
 				}
 
-				option := strings.Join(optionInfo, ", ")
 				optionLength := tview.TaggedStringWidth(option) + 6
 
 				if optionLength > length {
@@ -230,11 +232,99 @@ func ShowDownloadOptions() {
 	InfoMessage("Download options loaded", false)
 }
 
+// saveDownload shows the file browser to select where the selected
+// format of the entries is saved. The browser starts in the directory set
+// for the download type, or in the directory which was last used for it.
+// The first entry is saved with the entered file name, and other entries
+// are saved in the same directory.
+func saveDownload(entries []lib.SearchResult, video lib.VideoResult, format lib.FormatData) {
+	audio := lib.IsAudioFormat(format)
+
+	filename := func(entry lib.SearchResult, format lib.FormatData) string {
+		return lib.DownloadFilename(audio, entry.Title, entry.Author, entry.VideoID, format.Container)
+	}
+
+	context := "video-downloads"
+	if audio {
+		context = "audio-downloads"
+	}
+
+	ShowFileBrowser(context, "Save as:", func(path string) {
+		dir := filepath.Dir(path)
+
+		go downloadMatching(entries, video, format, func(i int, entry lib.SearchResult, format lib.FormatData) string {
+			if i == 0 {
+				return path
+			}
+
+			return filepath.Join(dir, filename(entry, format))
+		})
+	}, plFbExit, lib.DownloadDir(audio))
+
+	InputBox.SetText(filename(entries[0], format))
+}
+
+// downloadMatching downloads the selected format of the video, and the
+// closest format to it for each of the other entries. The path function
+// returns the file name for the entry at the given index.
+func downloadMatching(
+	entries []lib.SearchResult, video lib.VideoResult, format lib.FormatData,
+	path func(int, lib.SearchResult, lib.FormatData) string,
+) {
+	for i, entry := range entries {
+		entryFormat := format
+
+		if entry.VideoID != video.VideoID {
+			entryVideo, err := lib.GetClient().Video(entry.VideoID)
+			if err != nil {
+				ErrorMessage(err)
+				continue
+			}
+
+			match, ok := lib.MatchFormat(video, entryVideo, format)
+			if !ok || entryVideo.LiveNow {
+				ErrorMessage(fmt.Errorf("No matching format found for %s", entry.Title))
+				continue
+			}
+
+			entryFormat = match
+		}
+
+		go startDownload(entry.VideoID, entryFormat.Itag, path(i, entry, entryFormat))
+	}
+}
+
+// downloadPreferred downloads the preferred format of each entry, without
+// showing the download options. The first entry's video data is already loaded.
+func downloadPreferred(entries []lib.SearchResult, video lib.VideoResult) {
+	var err error
+
+	for i, entry := range entries {
+		if i > 0 {
+			video, err = lib.GetClient().Video(entry.VideoID)
+			if err != nil {
+				ErrorMessage(err)
+				continue
+			}
+		}
+
+		format, ok := lib.DownloadFormat(video, false)
+		if !ok || video.LiveNow {
+			ErrorMessage(fmt.Errorf("No downloadable format found for %s", entry.Title))
+			continue
+		}
+
+		go startDownload(entry.VideoID, format.Itag, lib.DownloadPath(false, entry.Title, entry.Author, entry.VideoID, format.Container))
+	}
+
+	InfoMessage("Download started", false)
+}
+
 // startDownload starts the download and tracks its progress.
 func startDownload(id, itag, filename string) {
 	var download DownloadProgress
 
-	InfoMessage("Starting download for "+tview.Escape(filename), true)
+	InfoMessage("Starting download for "+tview.Escape(filepath.Base(filename)), true)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -247,7 +337,7 @@ func startDownload(id, itag, filename string) {
 	defer res.Body.Close()
 	defer file.Close()
 
-	download.desc = tview.NewTableCell("[::b]" + tview.Escape(filename)).
+	download.desc = tview.NewTableCell("[::b]" + tview.Escape(filepath.Base(filename))).
 		SetExpansion(1).
 		SetSelectable(true).
 		SetAlign(tview.AlignLeft)
@@ -307,12 +397,23 @@ func startDownload(id, itag, filename string) {
 	})
 	defer download.removeDownload()
 
-	InfoMessage("Download started for "+tview.Escape(filename), false)
+	InfoMessage("Download started for "+tview.Escape(filepath.Base(filename)), false)
+
+	lib.DownloadStarted()
 
-	_, err = io.Copy(io.MultiWriter(file, download.progressBar), res.Body)
+	size, err := io.Copy(io.MultiWriter(file, download.progressBar), res.Body)
+	lib.DownloadFinished(size, err)
 	if err != nil {
 		ErrorMessage(err)
+		return
 	}
+
+	lib.SendWebhook("download-completed", map[string]interface{}{
+		"videoId": id,
+		"itag":    itag,
+		"file":    file.Name(),
+		"size":    size,
+	})
 }
 
 // removeDownload removes the download from the download view.
@@ -338,6 +439,8 @@ func (d *DownloadProgress) removeDownload() {
 			}
 		}
 
+		updateDownloadIndicator()
+
 		if downloadView.GetRowCount() == 0 {
 			downloadView.InputHandler()(tcell.NewEventKey(tcell.KeyEscape, ' ', tcell.ModNone), nil)
 		}
@@ -348,7 +451,58 @@ func (d *DownloadProgress) removeDownload() {
 func (d *DownloadProgress) Write(b []byte) (int, error) {
 	App.QueueUpdateDraw(func() {
 		d.progress.SetText(string(b))
+
+		updateDownloadIndicator()
 	})
 
 	return 0, nil
 }
+
+// updateDownloadIndicator shows the number of active downloads,
+// the overall progress and the total download speed in the status bar.
+func updateDownloadIndicator() {
+	var active int
+	var speed float64
+	var current, total int64
+
+	if downloadView == nil {
+		return
+	}
+
+	for row := 0; row < downloadView.GetRowCount(); row++ {
+		download, ok := downloadView.GetCell(row, 0).GetReference().(*DownloadProgress)
+		if !ok || download.progressBar == nil {
+			continue
+		}
+
+		state := download.progressBar.State()
+
+		active++
+		speed += state.KBsPerSecond
+		current += int64(state.CurrentBytes)
+
+		if max := download.progressBar.GetMax64(); max > 0 && total >= 0 {
+			total += max
+		} else {
+			total = -1
+		}
+	}
+
+	if active == 0 {
+		setStatusIndicator("downloads", "")
+		return
+	}
+
+	text := "[::b]" + strconv.Itoa(active) + " downloading[-:-:-]"
+	if total > 0 {
+		text += " " + strconv.FormatInt(current*100/total, 10) + "%"
+	}
+
+	if speed >= 1024 {
+		text += fmt.Sprintf(" %.1f MB/s", speed/1024)
+	} else {
+		text += fmt.Sprintf(" %.1f KB/s", speed)
+	}
+
+	setStatusIndicator("downloads", text)
+}
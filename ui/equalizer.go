@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// equalizerGainStep is the step in dB by which a band's gain is changed.
+const equalizerGainStep = 1
+
+// showEqualizer shows a popup with the equalizer presets, and an editor
+// for the gains of each band of the custom preset. Changes are applied
+// immediately, and saved to the config file when the popup is closed.
+func showEqualizer() {
+	var changed bool
+
+	preset, gains := lib.Equalizer()
+	presets := len(lib.EqualizerPresets)
+
+	eqTitle := tview.NewTextView()
+	eqTitle.SetDynamicColors(true)
+	eqTitle.SetTextAlign(tview.AlignCenter)
+	eqTitle.SetText("[white::bu]Equalizer")
+	eqTitle.SetBackgroundColor(tcell.ColorDefault)
+
+	eqPopup := tview.NewTable()
+	eqPopup.SetBorders(false)
+	eqPopup.SetSelectorWrap(true)
+	eqPopup.SetSelectable(true, false)
+	eqPopup.SetBackgroundColor(tcell.ColorDefault)
+
+	apply := func() {
+		changed = true
+		setEqualizerRows(eqPopup, preset, gains)
+
+		go func(gains []float64) {
+			if err := lib.GetMPV().SetAudioEqualizer(gains); err != nil {
+				ErrorMessage(err)
+			}
+		}(append([]float64{}, gains...))
+	}
+
+	eqPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := eqPopup.GetSelection()
+		band := row - presets - 1
+
+		switch event.Key() {
+		case tcell.KeyEscape:
+			exitFocus()
+
+			if changed {
+				go saveEqualizer(preset, gains)
+			}
+
+			return event
+
+		case tcell.KeyEnter:
+			if row < presets {
+				preset = lib.EqualizerPresets[row]
+				gains = lib.EqualizerPresetGains(preset)
+
+				apply()
+			}
+
+			return event
+
+		case tcell.KeyLeft, tcell.KeyRight:
+			if band < 0 || band >= len(gains) {
+				return nil
+			}
+
+			step := float64(equalizerGainStep)
+			if event.Key() == tcell.KeyLeft {
+				step = -step
+			}
+
+			if gain := gains[band] + step; gain >= -lib.EqualizerGainLimit && gain <= lib.EqualizerGainLimit {
+				gains[band] = gain
+				preset = "custom"
+
+				apply()
+			}
+
+			return nil
+		}
+
+		captureSendPlayerEvent(event)
+
+		return event
+	})
+
+	setEqualizerRows(eqPopup, preset, gains)
+
+	eqFlex := tview.NewFlex().
+		AddItem(eqTitle, 1, 0, false).
+		AddItem(eqPopup, 10, 10, false).
+		SetDirection(tview.FlexRow)
+
+	MPage.AddAndSwitchToPage(
+		"equalizer",
+		statusmodal(eqFlex, eqPopup),
+		true,
+	).ShowPage("ui")
+
+	App.SetFocus(eqPopup)
+
+	InfoMessage("Press Enter to select a preset, and Left/Right to change the gain of a band", false)
+}
+
+// setEqualizerRows lists the equalizer presets, with the selected preset
+// marked, followed by the gains of each band.
+func setEqualizerRows(table *tview.Table, preset string, gains []float64) {
+	for i, name := range lib.EqualizerPresets {
+		text := "[blue::b]  " + name
+		if name == preset {
+			text = "[green::b]* " + name
+		}
+
+		table.SetCell(i, 0, tview.NewTableCell(text).
+			SetSelectedStyle(mainStyle),
+		)
+
+		table.SetCell(i, 1, tview.NewTableCell("").
+			SetExpansion(1).
+			SetSelectable(true),
+		)
+
+		table.SetCell(i, 2, tview.NewTableCell("").
+			SetSelectable(true),
+		)
+	}
+
+	row := len(lib.EqualizerPresets)
+
+	for col := 0; col < 3; col++ {
+		table.SetCell(row, col, tview.NewTableCell("").
+			SetSelectable(false),
+		)
+	}
+
+	for i, band := range lib.EqualizerBands {
+		frequency := strconv.Itoa(band) + " Hz"
+		if band >= 1000 {
+			frequency = strconv.Itoa(band/1000) + " kHz"
+		}
+
+		gain := strconv.FormatFloat(gains[i], 'f', -1, 64) + " dB"
+		if gains[i] > 0 {
+			gain = "+" + gain
+		}
+
+		table.SetCell(row+i+1, 0, tview.NewTableCell("[white::b]"+frequency).
+			SetSelectedStyle(mainStyle),
+		)
+
+		table.SetCell(row+i+1, 1, tview.NewTableCell(equalizerBar(gains[i])).
+			SetExpansion(1).
+			SetAlign(tview.AlignCenter).
+			SetSelectedStyle(auxStyle),
+		)
+
+		table.SetCell(row+i+1, 2, tview.NewTableCell("[pink]"+gain).
+			SetAlign(tview.AlignRight).
+			SetSelectedStyle(auxStyle),
+		)
+	}
+}
+
+// equalizerBar renders the gain of a band as a slider.
+func equalizerBar(gain float64) string {
+	pos := int(gain) + lib.EqualizerGainLimit
+
+	return "[purple::b]" + strings.Repeat("─", pos) + "●" +
+		strings.Repeat("─", 2*lib.EqualizerGainLimit-pos) + "[-:-:-]"
+}
+
+// saveEqualizer applies the equalizer preset and saves it to the config file.
+func saveEqualizer(preset string, gains []float64) {
+	if err := lib.SetEqualizer(preset, gains); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Equalizer set to "+preset, false)
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+type logEntry struct {
+	time time.Time
+	text string
+}
+
+var (
+	errorLog     []logEntry
+	errorLogLock sync.Mutex
+
+	errLogPrevPage string
+	errLogPrevItem tview.Primitive
+)
+
+const errorLogMax = 500
+
+// ShowErrorLog shows the errors that occurred during the session.
+func ShowErrorLog() {
+	var text strings.Builder
+
+	errorLogLock.Lock()
+	for _, entry := range errorLog {
+		text.WriteString("[grey]" + entry.time.Format("15:04:05") + "[-] ")
+		text.WriteString(tview.Escape(entry.text) + "\n")
+	}
+	count := len(errorLog)
+	errorLogLock.Unlock()
+
+	if count == 0 {
+		InfoMessage("No errors in this session", false)
+		return
+	}
+
+	if pg, _ := VPage.GetFrontPage(); pg == "errorlog" {
+		return
+	}
+
+	MPage.SwitchToPage("ui")
+
+	errLogPrevPage, errLogPrevItem = VPage.GetFrontPage()
+
+	title := tview.NewTextView()
+	title.SetDynamicColors(true)
+	title.SetText("[::bu]Error log")
+	title.SetTextAlign(tview.AlignLeft)
+	title.SetBackgroundColor(tcell.ColorDefault)
+
+	logView := tview.NewTextView()
+	logView.SetWrap(true)
+	logView.SetDynamicColors(true)
+	logView.SetText(text.String())
+	logView.SetBackgroundColor(tcell.ColorDefault)
+	logView.ScrollToEnd()
+	logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			VPage.SwitchToPage(errLogPrevPage)
+			App.SetFocus(errLogPrevItem)
+		}
+
+		return event
+	})
+
+	logFlex := tview.NewFlex().
+		AddItem(title, 1, 0, false).
+		AddItem(logView, 0, 10, false).
+		SetDirection(tview.FlexRow)
+
+	VPage.AddAndSwitchToPage("errorlog", logFlex, true)
+
+	App.SetFocus(logView)
+}
+
+// addToErrorLog adds an error message to the session's error log.
+func addToErrorLog(text string) {
+	errorLogLock.Lock()
+	defer errorLogLock.Unlock()
+
+	errorLog = append(errorLog, logEntry{time.Now(), text})
+	if len(errorLog) > errorLogMax {
+		errorLog = errorLog[len(errorLog)-errorLogMax:]
+	}
+}
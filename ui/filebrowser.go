@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 	"github.com/mitchellh/go-homedir"
@@ -21,11 +22,15 @@ var (
 	browserList  *tview.Table
 	browserTitle *tview.TextView
 
-	isHidden    bool
-	hideLock    sync.Mutex
-	prevDir     string
-	currentPath string
-	listLock    *semaphore.Weighted
+	isHidden       bool
+	hideLock       sync.Mutex
+	prevDir        string
+	currentPath    string
+	browserContext string
+	bookmarksShown bool
+	browserSort    string
+	browserFilter  []string
+	listLock       *semaphore.Weighted
 )
 
 // SetupFileBrowser sets up the file browser popup.
@@ -44,7 +49,7 @@ func SetupFileBrowser() {
 		AddItem(browserList, 10, 10, false).
 		SetDirection(tview.FlexRow)
 
-	browserList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	browserList.SetInputCapture(contextCapture("filebrowser", func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyLeft:
 			go changeDir("", false, true)
@@ -57,15 +62,36 @@ func SetupFileBrowser() {
 		case tcell.KeyCtrlH:
 			toggleHidden()
 			go changeDir("", false, false)
+
+		case tcell.KeyCtrlB:
+			toggleBookmark()
+
+		case tcell.KeyCtrlG:
+			showBookmarks()
+
+		case tcell.KeyCtrlN:
+			go makeDir(InputBox.GetText())
+
+		case tcell.KeyCtrlS:
+			cycleBrowserSort()
+			go changeDir("", false, false)
+
+		case tcell.KeyCtrlF:
+			setBrowserFilter(InputBox.GetText())
+			go changeDir("", false, false)
 		}
 
 		return event
-	})
+	}))
 
 	browserList.SetSelectionChangedFunc(func(row, col int) {
 		sel, _ := browserList.GetSelection()
 		cell := browserList.GetCell(sel, 0)
 
+		if browserContext != "playlist" {
+			return
+		}
+
 		if strings.Contains(cell.Text, string(os.PathSeparator)) {
 			InputBox.SetText("")
 			return
@@ -78,11 +104,22 @@ func SetupFileBrowser() {
 	listLock = semaphore.NewWeighted(1)
 }
 
-// ShowFileBrowser shows the filebrowser popup and the input area.
+// ShowFileBrowser shows the filebrowser popup and the input area. The
+// context is either "playlist", in which only playlist files are listed,
+// or "audio-downloads" or "video-downloads", in which files are saved.
+// The browser starts in startDir if it is provided, otherwise in the
+// directory which was last used in the context. The
+// filebrowser is not available in locked mode, since playlist files are
+// not checked against the restrictions.
 func ShowFileBrowser(
-	inputText string,
+	context, inputText string,
 	dofunc func(text string), exitfunc func(),
+	startDir ...string,
 ) {
+	if lockedFeature("The file browser") {
+		return
+	}
+
 	ifunc := func(e *tcell.EventKey) *tcell.EventKey {
 		switch e.Key() {
 		case tcell.KeyUp, tcell.KeyDown:
@@ -94,7 +131,8 @@ func ShowFileBrowser(
 		case tcell.KeyPgUp, tcell.KeyPgDn:
 			fallthrough
 
-		case tcell.KeyCtrlH:
+		case tcell.KeyCtrlH, tcell.KeyCtrlB, tcell.KeyCtrlG, tcell.KeyCtrlN,
+			tcell.KeyCtrlS, tcell.KeyCtrlF:
 			browserList.InputHandler()(e, nil)
 
 		case tcell.KeyEnter:
@@ -103,6 +141,8 @@ func ShowFileBrowser(
 				return e
 			}
 
+			lib.SetLastDir(browserContext, currentPath)
+
 			exitfunc()
 			go dofunc(filepath.Join(currentPath, text))
 
@@ -121,6 +161,20 @@ func ShowFileBrowser(
 
 	SetInput(inputText, 0, dofunc, ifunc)
 
+	browserContext = context
+	currentPath = lib.LastDir(context)
+
+	if startDir != nil && startDir[0] != "" {
+		if dir, err := filepath.Abs(startDir[0]); err == nil {
+			currentPath = dir
+		}
+	}
+
+	browserFilter = nil
+	if context == "playlist" {
+		browserFilter = []string{".m3u8"}
+	}
+
 	go changeDir("", false, false)
 }
 
@@ -146,10 +200,15 @@ func changeDir(entry string, cdFwd bool, cdBack bool) {
 	testPath = currentPath
 
 	switch {
+	case cdFwd && filepath.IsAbs(entry):
+		testPath = entry
+
 	case cdFwd:
 		testPath = trimPath(testPath, false)
 		testPath = filepath.Join(testPath, entry)
 
+	case cdBack && bookmarksShown:
+
 	case cdBack:
 		prevDir = filepath.Base(testPath)
 		testPath = trimPath(testPath, cdBack)
@@ -160,13 +219,7 @@ func changeDir(entry string, cdFwd bool, cdBack bool) {
 		return
 	}
 
-	sort.Slice(dlist, func(i, j int) bool {
-		if dlist[i].IsDir() != dlist[j].IsDir() {
-			return dlist[i].IsDir()
-		}
-
-		return dlist[i].Name() < dlist[j].Name()
-	})
+	sortDirList(dlist)
 
 	currentPath = testPath
 
@@ -199,11 +252,8 @@ func dirList(testPath string) ([]fs.FileInfo, bool) {
 			continue
 		}
 
-		if !entry.IsDir() {
-			ename := filepath.Join(testPath, entry.Name())
-			if filepath.Ext(ename) != ".m3u8" {
-				continue
-			}
+		if !entry.IsDir() && !matchBrowserFilter(entry.Name()) {
+			continue
 		}
 
 		dlist = append(dlist, entry)
@@ -240,7 +290,8 @@ func createDirList(dlist []fs.FileInfo, cdBack bool) {
 				SetTextColor(color))
 		}
 
-		browserTitle.SetText("[::bu]" + currentPath)
+		bookmarksShown = false
+		browserTitle.SetText(browserTitleText())
 
 		browserList.ScrollToBeginning()
 		browserList.SetSelectable(true, false)
@@ -249,6 +300,182 @@ func createDirList(dlist []fs.FileInfo, cdBack bool) {
 	})
 }
 
+// sortDirList sorts the directory entries by the sort mode of the file
+// browser, listing directories first. Entries are sorted by name, by
+// size with the largest first, or by modification time with the newest
+// first. Directories are always sorted by name when sorting by size.
+func sortDirList(dlist []fs.FileInfo) {
+	sort.SliceStable(dlist, func(i, j int) bool {
+		a, b := dlist[i], dlist[j]
+
+		if a.IsDir() != b.IsDir() {
+			return a.IsDir()
+		}
+
+		switch {
+		case browserSort == "size" && !a.IsDir() && a.Size() != b.Size():
+			return a.Size() > b.Size()
+
+		case browserSort == "mtime" && !a.ModTime().Equal(b.ModTime()):
+			return a.ModTime().After(b.ModTime())
+		}
+
+		return a.Name() < b.Name()
+	})
+}
+
+// cycleBrowserSort switches the sort mode of the file
+// browser between name, size and modification time.
+func cycleBrowserSort() {
+	switch browserSort {
+	case "", "name":
+		browserSort = "size"
+
+	case "size":
+		browserSort = "mtime"
+
+	default:
+		browserSort = "name"
+	}
+
+	InfoMessage("Sorting files by "+browserSort, false)
+}
+
+// setBrowserFilter sets the extensions of the files listed in the file
+// browser, from a list of extensions or file names separated by commas
+// or spaces, for example "mp4, webm" or "list.m3u8". If the text is
+// empty, all files are listed.
+func setBrowserFilter(text string) {
+	browserFilter = nil
+
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		ext := filepath.Ext(field)
+		if ext == "" {
+			ext = "." + field
+		}
+
+		browserFilter = append(browserFilter, strings.ToLower(ext))
+	}
+
+	if browserFilter == nil {
+		InfoMessage("Showing all files", false)
+		return
+	}
+
+	InfoMessage("Showing "+strings.Join(browserFilter, ", ")+" files", false)
+}
+
+// matchBrowserFilter returns whether the file has
+// one of the extensions of the file browser's filter.
+func matchBrowserFilter(name string) bool {
+	if browserFilter == nil {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, filter := range browserFilter {
+		if ext == filter {
+			return true
+		}
+	}
+
+	return false
+}
+
+// browserTitleText returns the title of the file browser, which shows the
+// current directory, and whether it is bookmarked or hidden files are shown.
+func browserTitleText() string {
+	title := "[::bu]" + tview.Escape(currentPath) + "[-:-:-]"
+
+	for _, bookmark := range lib.Bookmarks() {
+		if bookmark == currentPath {
+			title += " [yellow::b](bookmarked)[-:-:-]"
+			break
+		}
+	}
+
+	if !getHidden() {
+		title += " [grey::b](hidden files shown)[-:-:-]"
+	}
+
+	if browserSort != "" && browserSort != "name" {
+		title += " [grey::b](sort: " + browserSort + ")[-:-:-]"
+	}
+
+	if browserFilter != nil {
+		title += " [grey::b](filter: " + strings.Join(browserFilter, ", ") + ")[-:-:-]"
+	}
+
+	return title
+}
+
+// showBookmarks lists the bookmarked directories in the file browser.
+// Selecting a bookmark with the Right key changes to the directory,
+// and the Left key returns to the current directory.
+func showBookmarks() {
+	bookmarks := lib.Bookmarks()
+	if bookmarks == nil {
+		InfoMessage("No bookmarked directories", false)
+		return
+	}
+
+	browserList.SetSelectable(false, false)
+	browserList.Clear()
+
+	for row, bookmark := range bookmarks {
+		browserList.SetCell(row, 0, tview.NewTableCell(bookmark+string(os.PathSeparator)).
+			SetTextColor(tcell.ColorBlue))
+	}
+
+	bookmarksShown = true
+	browserTitle.SetText("[::bu]Bookmarks")
+
+	browserList.ScrollToBeginning()
+	browserList.SetSelectable(true, false)
+	browserList.Select(0, 0)
+	resizemodal()
+}
+
+// toggleBookmark bookmarks the current directory, or removes its bookmark.
+func toggleBookmark() {
+	if bookmarksShown || currentPath == "" {
+		return
+	}
+
+	if lib.ToggleBookmark(currentPath) {
+		InfoMessage("Bookmarked "+tview.Escape(currentPath), false)
+	} else {
+		InfoMessage("Removed bookmark for "+tview.Escape(currentPath), false)
+	}
+
+	browserTitle.SetText(browserTitleText())
+}
+
+// makeDir creates a directory with the given name in the
+// current directory, and changes to the new directory.
+func makeDir(name string) {
+	if bookmarksShown {
+		return
+	}
+
+	if name == "" {
+		InfoMessage("Enter a directory name to create it", false)
+		return
+	}
+
+	dir := filepath.Join(currentPath, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Created "+tview.Escape(dir), false)
+
+	changeDir(name, true, false)
+}
+
 // trimPath trims a given path and appends a path separator
 // where appropriate.
 func trimPath(testPath string, cdBack bool) string {
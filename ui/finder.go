@@ -0,0 +1,377 @@
+package ui
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// finderEntry stores an entry that can be searched in the finder.
+type finderEntry struct {
+	source   string
+	info     lib.SearchResult
+	queuePos int
+	path     string
+}
+
+// ShowFinder shows a launcher which fuzzy-searches across the queue,
+// the play history, the local playlist files, the bookmarked directories,
+// and the user's subscriptions and playlists.
+func ShowFinder() {
+	if pg, _ := MPage.GetFrontPage(); pg == "finder" {
+		return
+	}
+
+	InfoMessage("Loading finder entries", true)
+
+	entries := finderEntries()
+	if entries == nil {
+		InfoMessage("No entries to search", false)
+		return
+	}
+
+	InfoMessage("Loaded finder entries", false)
+
+	App.QueueUpdateDraw(func() {
+		var finderTable *tview.Table
+
+		finderInput := tview.NewInputField()
+		finderInput.SetLabel("[::b]Find: ")
+		finderInput.SetLabelColor(tcell.ColorWhite)
+		finderInput.SetBackgroundColor(tcell.ColorDefault)
+		finderInput.SetFieldBackgroundColor(tcell.ColorDefault)
+		finderInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				exitFocus()
+				return nil
+
+			case tcell.KeyEnter, tcell.KeyDown, tcell.KeyUp:
+				App.SetFocus(finderTable)
+				if event.Key() == tcell.KeyEnter {
+					return nil
+				}
+			}
+
+			return event
+		})
+		finderInput.SetChangedFunc(func(text string) {
+			finderTable.Clear()
+
+			for row, entry := range filterFinderEntries(entries, text) {
+				finderTable.SetCell(row, 0, tview.NewTableCell("[blue::b]"+tview.Escape(entry.info.Title)).
+					SetExpansion(1).
+					SetReference(entry.info).
+					SetSelectedStyle(mainStyle),
+				)
+
+				finderTable.SetCell(row, 1, tview.NewTableCell("[purple::b]"+tview.Escape(entry.info.Author)).
+					SetReference(entry).
+					SetSelectedStyle(auxStyle),
+				)
+
+				finderTable.SetCell(row, 2, tview.NewTableCell("[pink]"+entry.source).
+					SetSelectedStyle(auxStyle),
+				)
+			}
+
+			finderTable.ScrollToBeginning()
+
+			resizemodal()
+		})
+
+		finderTable = tview.NewTable()
+		finderTable.SetSelectorWrap(true)
+		finderTable.SetSelectable(true, false)
+		finderTable.SetBackgroundColor(tcell.ColorDefault)
+		finderTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			capturePlayerEvent(event)
+
+			switch event.Key() {
+			case tcell.KeyEscape:
+				exitFocus()
+
+			case tcell.KeyEnter:
+				if isAudioPlayKey(event) {
+					break
+				}
+
+				row, _ := finderTable.GetSelection()
+				if entry, ok := finderTable.GetCell(row, 1).GetReference().(finderEntry); ok {
+					openFinderEntry(entry)
+				}
+			}
+
+			switch event.Rune() {
+			case '/':
+				App.SetFocus(finderInput)
+				return nil
+			}
+
+			return event
+		})
+
+		finderTitle := tview.NewTextView()
+		finderTitle.SetDynamicColors(true)
+		finderTitle.SetText("[::bu]Find")
+		finderTitle.SetTextAlign(tview.AlignCenter)
+		finderTitle.SetBackgroundColor(tcell.ColorDefault)
+
+		finderFlex := tview.NewFlex().
+			AddItem(finderTitle, 1, 0, false).
+			AddItem(finderTable, 10, 10, true).
+			AddItem(getVbox(), 1, 0, false).
+			AddItem(finderInput, 1, 0, false).
+			AddItem(getVbox(), 1, 0, false).
+			AddItem(nil, 1, 0, false).
+			SetDirection(tview.FlexRow)
+
+		finderInput.SetText("")
+
+		MPage.AddAndSwitchToPage(
+			"finder",
+			statusmodal(finderFlex, finderTable),
+			true,
+		).ShowPage("ui")
+
+		App.SetFocus(finderInput)
+	})
+}
+
+// openFinderEntry jumps to the queue entry, opens the playlist, local
+// playlist file, bookmarked directory or channel, or plays the video
+// that was selected in the finder.
+func openFinderEntry(entry finderEntry) {
+	switch {
+	case entry.source == "bookmark":
+		exitFocus()
+
+		lib.SetLastDir("playlist", entry.path)
+		ShowFileBrowser("playlist", "Open playlist:", plOpenReplace, plFbExit)
+
+		return
+
+	case entry.source == "local playlist":
+		go plOpenReplace(entry.path)
+
+	case entry.source == "queue":
+		lib.GetMPV().SetPlaylistPos(entry.queuePos)
+		lib.GetMPV().Play()
+
+	case entry.info.Type == "channel":
+		ViewChannel("video", true, false)
+
+	case entry.info.Type == "playlist":
+		ViewPlaylist(true, false)
+
+	case entry.info.Type == "video":
+		PlaySelected(false, true, entry.info)
+	}
+
+	exitFocus()
+}
+
+// finderEntries gathers the entries to be searched in the finder.
+func finderEntries() []finderEntry {
+	var entries []finderEntry
+
+	if isPlaying() {
+		for i, data := range updatePlaylist() {
+			entries = append(entries, finderEntry{
+				source:   "queue",
+				queuePos: i,
+				info: lib.SearchResult{
+					Type:    "video",
+					Title:   data.Title,
+					Author:  data.Author,
+					VideoID: data.VideoID,
+				},
+			})
+		}
+	}
+
+	if !lib.RestrictedLocked() {
+		playHistoryLock.Lock()
+		for _, info := range playHistory {
+			entries = append(entries, finderEntry{source: "history", info: info})
+		}
+		playHistoryLock.Unlock()
+	}
+
+	entries = append(entries, localFinderEntries()...)
+
+	if !lib.IsAuthInstance() {
+		return entries
+	}
+
+	if subs, err := lib.GetClient().Subscriptions(); err == nil {
+		for _, sub := range subs {
+			entries = append(entries, finderEntry{
+				source: "subscription",
+				info: lib.SearchResult{
+					Type:     "channel",
+					Title:    sub.Author,
+					Author:   sub.Author,
+					AuthorID: sub.AuthorID,
+				},
+			})
+		}
+	}
+
+	if playlists, err := lib.GetClient().AuthPlaylists(); err == nil {
+		for _, playlist := range playlists {
+			entries = append(entries, finderEntry{
+				source: "playlist",
+				info: lib.SearchResult{
+					Type:       "playlist",
+					Title:      playlist.Title,
+					PlaylistID: playlist.PlaylistID,
+					AuthorID:   playlist.AuthorID,
+					Author:     playlist.Author,
+				},
+			})
+		}
+	}
+
+	return entries
+}
+
+// localFinderEntries returns the bookmarked directories, and the local
+// playlist files in them and in the directory which was last used to
+// open or save a playlist.
+func localFinderEntries() []finderEntry {
+	var entries []finderEntry
+
+	bookmarks := lib.Bookmarks()
+	for _, dir := range bookmarks {
+		entries = append(entries, finderEntry{
+			source: "bookmark",
+			path:   dir,
+			info: lib.SearchResult{
+				Title:  filepath.Base(dir),
+				Author: dir,
+			},
+		})
+	}
+
+	dirs := bookmarks
+	if dir := lib.LastDir("playlist"); dir != "" {
+		dirs = append([]string{dir}, bookmarks...)
+	}
+
+	seen := make(map[string]struct{})
+	for _, dir := range dirs {
+		if _, ok := seen[dir]; ok {
+			continue
+		}
+		seen[dir] = struct{}{}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			if file.IsDir() || filepath.Ext(file.Name()) != ".m3u8" {
+				continue
+			}
+
+			entries = append(entries, finderEntry{
+				source: "local playlist",
+				path:   filepath.Join(dir, file.Name()),
+				info: lib.SearchResult{
+					Title:  strings.TrimSuffix(file.Name(), ".m3u8"),
+					Author: dir,
+				},
+			})
+		}
+	}
+
+	return entries
+}
+
+// filterFinderEntries returns the entries which fuzzy-match the
+// provided text, sorted by their score.
+func filterFinderEntries(entries []finderEntry, text string) []finderEntry {
+	type scoredEntry struct {
+		entry finderEntry
+		score int
+	}
+
+	if text == "" {
+		return entries
+	}
+
+	var scored []scoredEntry
+
+	for _, entry := range entries {
+		score, ok := fuzzyScore(entry.info.Title+" "+entry.info.Author, text)
+		if !ok {
+			continue
+		}
+
+		scored = append(scored, scoredEntry{entry, score})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	filtered := make([]finderEntry, len(scored))
+	for i, s := range scored {
+		filtered[i] = s.entry
+	}
+
+	return filtered
+}
+
+// fuzzyScore checks whether all characters of the pattern appear
+// in order in the text, and scores the match. Consecutive matches
+// and matches at the start of words are scored higher.
+func fuzzyScore(text, pattern string) (int, bool) {
+	var score, pos int
+
+	prevMatched := false
+	runes := []rune(strings.ToLower(text))
+
+	for _, p := range strings.ToLower(pattern) {
+		if unicode.IsSpace(p) {
+			continue
+		}
+
+		matched := false
+
+		for ; pos < len(runes); pos++ {
+			if runes[pos] != p {
+				prevMatched = false
+				continue
+			}
+
+			score++
+			if prevMatched {
+				score += 2
+			}
+			if pos == 0 || unicode.IsSpace(runes[pos-1]) {
+				score += 3
+			}
+
+			pos++
+			matched = true
+			prevMatched = true
+
+			break
+		}
+
+		if !matched {
+			return 0, false
+		}
+	}
+
+	return score, true
+}
@@ -16,6 +16,9 @@ var (
 	inputBoxFunc func(text string)
 	inputChgFunc func(text string)
 	defaultIFunc func(event *tcell.EventKey) *tcell.EventKey
+
+	inputHistory    map[string][]string
+	inputHistoryPos int
 )
 
 // SetupInputBox sets up an inputbox to enter text.
@@ -64,6 +67,57 @@ func SetupInputBox() {
 	InputBox.SetFieldBackgroundColor(tcell.ColorDefault)
 }
 
+// historyInputFunc wraps the inputbox's input handler, to store the
+// entered text and recall it via the Up and Down keys.
+func historyInputFunc(label string, ifunc func(event *tcell.EventKey) *tcell.EventKey) func(event *tcell.EventKey) *tcell.EventKey {
+	if inputHistory == nil {
+		inputHistory = make(map[string][]string)
+	}
+
+	history := inputHistory[label]
+	inputHistoryPos = len(history)
+
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		history := inputHistory[label]
+
+		switch event.Key() {
+		case tcell.KeyEnter:
+			text := InputBox.GetText()
+			if text != "" && (history == nil || history[len(history)-1] != text) {
+				inputHistory[label] = append(history, text)
+			}
+
+			inputHistoryPos = len(inputHistory[label])
+
+		case tcell.KeyUp:
+			if event.Modifiers() != tcell.ModNone || inputHistoryPos == 0 {
+				break
+			}
+
+			inputHistoryPos--
+			InputBox.SetText(history[inputHistoryPos])
+
+			return nil
+
+		case tcell.KeyDown:
+			if event.Modifiers() != tcell.ModNone || inputHistoryPos >= len(history) {
+				break
+			}
+
+			inputHistoryPos++
+			if inputHistoryPos == len(history) {
+				InputBox.SetText("")
+			} else {
+				InputBox.SetText(history[inputHistoryPos])
+			}
+
+			return nil
+		}
+
+		return ifunc(event)
+	}
+}
+
 // GetInputProps returns the InputBox's current properties.
 func GetInputProps() (string, int, func(text string), func(text string), func(event *tcell.EventKey) *tcell.EventKey) {
 	return inputLabel, acceptMax, inputBoxFunc, inputChgFunc, InputBox.GetInputCapture()
@@ -95,13 +149,21 @@ func SetInput(label string,
 	InputBox.SetChangedFunc(inputChgFunc)
 
 	InputBox.SetText("")
+	InputBox.SetMaskCharacter(0)
 	InputBox.SetLabel("[::b]" + label + " ")
-	if ifunc != nil {
-		InputBox.SetInputCapture(ifunc)
-	} else {
-		InputBox.SetInputCapture(defaultIFunc)
+	if ifunc == nil {
+		ifunc = defaultIFunc
 	}
 
+	// Search prompts maintain their own persistent history,
+	// single-character prompts do not need one, and PINs
+	// must not be recalled.
+	if max != 1 && !strings.Contains(label, "Search") && !strings.Contains(label, "PIN") {
+		ifunc = historyInputFunc(label, ifunc)
+	}
+
+	InputBox.SetInputCapture(ifunc)
+
 	App.SetFocus(InputBox)
 	Status.SwitchToPage("input")
 }
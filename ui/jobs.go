@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// spinnerFrames are the frames of the spinner shown for active jobs.
+var spinnerFrames = []string{"-", "\\", "|", "/"}
+
+// monitorJobs shows a spinner in the status bar for each active job.
+func monitorJobs() {
+	var frame int
+	var shown bool
+
+	t := time.NewTicker(150 * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-sctx.Done():
+			return
+
+		case <-t.C:
+		}
+
+		jobs := lib.ActiveJobs()
+		if len(jobs) == 0 && !shown {
+			continue
+		}
+
+		shown = len(jobs) > 0
+		frame = (frame + 1) % len(spinnerFrames)
+
+		var texts []string
+		for _, name := range jobs {
+			texts = append(texts, "[yellow::b]"+spinnerFrames[frame]+" "+name+"[-:-:-]")
+		}
+
+		App.QueueUpdateDraw(func() {
+			setStatusIndicator("jobs", strings.Join(texts, " "))
+		})
+	}
+}
+
+// cancelJobs cancels the active jobs. If more than one job is active
+// and an input is not being entered, it asks which job to cancel.
+func cancelJobs() {
+	_, input := App.GetFocus().(*tview.InputField)
+
+	jobs := lib.ActiveJobs()
+	if len(jobs) <= 1 || input {
+		cancelJob("")
+		return
+	}
+
+	label := "Cancel"
+	for i, name := range jobs {
+		label += " (" + strconv.Itoa(i+1) + ") " + name
+	}
+	label += " (a) all:"
+
+	p := App.GetFocus()
+
+	SetInput(label, 1, nil, func(e *tcell.EventKey) *tcell.EventKey {
+		switch e.Key() {
+		case tcell.KeyEnter:
+			text := InputBox.GetText()
+
+			if text == "a" {
+				cancelJob("")
+			} else if n, err := strconv.Atoi(text); err == nil && n > 0 && n <= len(jobs) {
+				cancelJob(jobs[n-1])
+			}
+
+			fallthrough
+
+		case tcell.KeyEscape:
+			App.SetFocus(p)
+			Status.SwitchToPage("messages")
+		}
+
+		return e
+	})
+}
+
+// cancelJob cancels the named job, or all jobs if name is empty.
+func cancelJob(name string) {
+	if name == "" {
+		lib.JobCancelAll()
+	} else {
+		lib.JobCancel(name)
+	}
+
+	if name == "" || name == "comments" {
+		closeCommentView()
+	}
+
+	InfoMessage("Loading canceled", false)
+}
@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/gdamore/tcell/v2"
+)
+
+// keyBinding describes a key, as matched against key events.
+type keyBinding struct {
+	key  tcell.Key
+	ch   rune
+	mods tcell.ModMask
+}
+
+// keyContexts lists the contexts in which keys can be remapped. Keys in the
+// global context are remapped in every view, and keys in the player context
+// are remapped wherever the player keys are handled.
+var keyContexts = []string{"global", "results", "player", "playlist", "filebrowser", "comments"}
+
+// keyRemaps maps each context to the keys set in the config
+// file, which are mapped to the default keys.
+var keyRemaps map[string]map[keyBinding]keyBinding
+
+// keyNames maps key names in the config file to keys.
+var keyNames = map[string]tcell.Key{
+	"enter":     tcell.KeyEnter,
+	"esc":       tcell.KeyEscape,
+	"escape":    tcell.KeyEscape,
+	"tab":       tcell.KeyTab,
+	"backtab":   tcell.KeyBacktab,
+	"backspace": tcell.KeyBackspace2,
+	"up":        tcell.KeyUp,
+	"down":      tcell.KeyDown,
+	"left":      tcell.KeyLeft,
+	"right":     tcell.KeyRight,
+	"home":      tcell.KeyHome,
+	"end":       tcell.KeyEnd,
+	"pgup":      tcell.KeyPgUp,
+	"pgdn":      tcell.KeyPgDn,
+	"insert":    tcell.KeyInsert,
+	"delete":    tcell.KeyDelete,
+	"f1":        tcell.KeyF1,
+	"f2":        tcell.KeyF2,
+	"f3":        tcell.KeyF3,
+	"f4":        tcell.KeyF4,
+	"f5":        tcell.KeyF5,
+	"f6":        tcell.KeyF6,
+	"f7":        tcell.KeyF7,
+	"f8":        tcell.KeyF8,
+	"f9":        tcell.KeyF9,
+	"f10":       tcell.KeyF10,
+	"f11":       tcell.KeyF11,
+	"f12":       tcell.KeyF12,
+}
+
+// setupKeybinds parses the keybindings from the config file.
+func setupKeybinds() error {
+	remaps, err := parseKeybinds(lib.Keybindings())
+	if err != nil {
+		return err
+	}
+
+	keyRemaps = remaps
+
+	return nil
+}
+
+// parseKeybinds parses the keybindings of each context, as a map
+// of the new key to the default key, into the key remaps.
+func parseKeybinds(keybindings map[string]map[string]string) (map[string]map[keyBinding]keyBinding, error) {
+	remaps := make(map[string]map[keyBinding]keyBinding)
+
+	for context, keybinds := range keybindings {
+		if !isKeyContext(context) {
+			return nil, fmt.Errorf("%s is not a valid keybinding context", context)
+		}
+
+		remaps[context] = make(map[keyBinding]keyBinding)
+
+		for key, action := range keybinds {
+			from, err := parseKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			to, err := parseKey(action)
+			if err != nil {
+				return nil, err
+			}
+
+			remaps[context][from] = to
+		}
+	}
+
+	return remaps, nil
+}
+
+// contextCapture returns an input capture function, which remaps
+// the keys of the context before passing them to capture.
+func contextCapture(
+	context string, capture func(event *tcell.EventKey) *tcell.EventKey,
+) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		return capture(remapKey(context, event))
+	}
+}
+
+// remapKey returns the event for the default key, if the key of
+// the event has been remapped for the context in the config file.
+func remapKey(context string, event *tcell.EventKey) *tcell.EventKey {
+	remaps := keyRemaps[context]
+	if len(remaps) == 0 {
+		return event
+	}
+
+	pressed := keyBinding{key: event.Key(), mods: event.Modifiers() & (tcell.ModCtrl | tcell.ModAlt)}
+	if pressed.key == tcell.KeyRune {
+		pressed.ch = event.Rune()
+		pressed.mods &= tcell.ModAlt
+	}
+
+	to, ok := remaps[pressed]
+	if !ok {
+		return event
+	}
+
+	return tcell.NewEventKey(to.key, to.ch, to.mods)
+}
+
+// isKeyContext returns whether context is a valid keybinding context.
+func isKeyContext(context string) bool {
+	for _, c := range keyContexts {
+		if c == context {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseKey parses a key from the config file, for example
+// "a", "enter", "ctrl+f" or "alt+1".
+func parseKey(name string) (keyBinding, error) {
+	var binding keyBinding
+
+	parts := strings.Split(name, "+")
+	key := parts[len(parts)-1]
+	if key == "" && len(parts) > 1 {
+		key = "+"
+		parts = parts[:len(parts)-1]
+	}
+
+	for _, mod := range parts[:len(parts)-1] {
+		switch strings.ToLower(mod) {
+		case "ctrl":
+			binding.mods |= tcell.ModCtrl
+
+		case "alt":
+			binding.mods |= tcell.ModAlt
+
+		default:
+			return binding, fmt.Errorf("%s is not a valid key", name)
+		}
+	}
+
+	if k, ok := keyNames[strings.ToLower(key)]; ok {
+		binding.key = k
+		return binding, nil
+	}
+
+	if strings.ToLower(key) == "space" {
+		key = " "
+	}
+
+	if utf8.RuneCountInString(key) != 1 {
+		return binding, fmt.Errorf("%s is not a valid key", name)
+	}
+
+	ch, _ := utf8.DecodeRuneInString(key)
+
+	if binding.mods&tcell.ModCtrl != 0 {
+		ch = rune(strings.ToLower(string(ch))[0])
+		if ch < 'a' || ch > 'z' {
+			return binding, fmt.Errorf("%s is not a valid key", name)
+		}
+
+		binding.key = tcell.KeyCtrlA + tcell.Key(ch-'a')
+		return binding, nil
+	}
+
+	binding.key = tcell.KeyRune
+	binding.ch = ch
+
+	return binding, nil
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+)
+
+// The terminal sizes below which the compact layout is used.
+const (
+	compactWidth  = 60
+	compactHeight = 16
+)
+
+var (
+	compactLayout bool
+
+	uiSpacer *tview.Box
+
+	plDescHeight int
+	chDescHeight int
+)
+
+// layoutPrefs stores the UI state which is restored on startup.
+// The player's shuffle, loop and volume states are stored separately.
+// The columns of the lists are fixed, so they are not stored.
+type layoutPrefs struct {
+	View         string            `json:"view"`
+	SearchType   string            `json:"searchType"`
+	SearchParams map[string]string `json:"searchParams"`
+
+	LiveChatWidth int    `json:"liveChatWidth,omitempty"`
+	BrowserSort   string `json:"browserSort,omitempty"`
+	ShowHidden    bool   `json:"showHidden,omitempty"`
+}
+
+// loadLayout restores the search type and parameters, the width of
+// the live chat pane and the sort order and hidden files mode of the
+// file browser, and returns the saved layout preferences.
+func loadLayout() layoutPrefs {
+	var prefs layoutPrefs
+
+	layoutFile, err := lib.DataPath("layout.json")
+	if err != nil {
+		return prefs
+	}
+
+	file, err := os.Open(layoutFile)
+	if err != nil {
+		return prefs
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(&prefs); err != nil {
+		return prefs
+	}
+
+	switch prefs.SearchType {
+	case "video", "playlist", "channel", "song", "album", "artist":
+		stype = prefs.SearchType
+		highlightSearchType()
+	}
+
+	if prefs.SearchParams != nil {
+		lib.SetSearchParams(prefs.SearchParams)
+	}
+
+	if prefs.LiveChatWidth >= liveChatMinWidth {
+		liveChatWidth = prefs.LiveChatWidth
+	}
+
+	switch prefs.BrowserSort {
+	case "name", "size", "mtime":
+		browserSort = prefs.BrowserSort
+	}
+
+	if prefs.ShowHidden {
+		toggleHidden()
+	}
+
+	return prefs
+}
+
+// restoreLayout shows the view that was last used, if no search
+// or playback was requested from the command-line.
+func restoreLayout(prefs layoutPrefs) {
+	if _, _, err := lib.GetSearchQuery(); err == nil {
+		return
+	}
+
+	if _, _, err := lib.GetPlayParams(); err == nil {
+		return
+	}
+
+	if prefs.View == "dashboard" && lib.IsAuthInstance() {
+		go ShowDashboard()
+	}
+}
+
+// saveLayout saves the current UI state.
+func saveLayout() {
+	prefs := layoutPrefs{
+		View:         "search",
+		SearchType:   stype,
+		SearchParams: lib.GetSearchParams(),
+
+		LiveChatWidth: liveChatWidth,
+		BrowserSort:   browserSort,
+		ShowHidden:    !getHidden(),
+	}
+
+	if pg, _ := VPage.GetFrontPage(); pg == "dashboard" {
+		prefs.View = "dashboard"
+	}
+
+	layoutFile, err := lib.DataPath("layout.json")
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(prefs, "", " ")
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(layoutFile, data, 0664)
+}
+
+// resizeLayout switches to the compact layout if the terminal is smaller
+// than compactWidth or compactHeight, and back to the normal layout when
+// it is large enough again. It must be called from the UI goroutine.
+func resizeLayout(width, height int) {
+	compact := width < compactWidth || height < compactHeight
+	if compact == compactLayout {
+		return
+	}
+
+	compactLayout = compact
+
+	spacer := 1
+	if compact {
+		spacer = 0
+	}
+	UIFlex.ResizeItem(uiSpacer, spacer, 0)
+
+	// In the compact layout, only the progress line of the player is shown.
+	titleHeight, authorHeight := 1, 0
+	if compact {
+		titleHeight = 0
+	} else if lib.ExpandedPlayer() {
+		authorHeight = 1
+	}
+	Player.ResizeItem(playerTitle, titleHeight, 0)
+	Player.ResizeItem(playerAuthor, authorHeight, 0)
+	if isPlaying() {
+		UIFlex.ResizeItem(Player, playerHeight(), 0)
+	}
+
+	// The descriptions of playlists and channels are hidden.
+	resizeDescription(plViewFlex, plTableDesc, plTableVBox, plDescHeight)
+	resizeDescription(chViewFlex, chDesc, chVbox, chDescHeight)
+
+	showStatusIndicators()
+	resizemodal()
+
+	go App.Draw()
+}
+
+// resizeDescription hides the description in the flex in the compact
+// layout, or shows it with the given height in the normal layout.
+func resizeDescription(flex *tview.Flex, desc *tview.TextView, vbox *tview.Box, height int) {
+	if height == 0 {
+		return
+	}
+
+	vboxHeight := 1
+	if compactLayout {
+		height, vboxHeight = 0, 0
+	}
+
+	flex.ResizeItem(desc, height, 0)
+	flex.ResizeItem(vbox, vboxHeight, 0)
+}
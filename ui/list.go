@@ -31,6 +31,11 @@ var (
 
 const loadingText = "Search still in progress, please wait"
 
+const (
+	searchTabs = `[::b]Search[-:-:-] ["video"][darkcyan]Videos[""] ["playlist"][darkcyan]Playlists[""] ["channel"][darkcyan]Channels[""] ["all"][darkcyan]All[""]`
+	musicTabs  = `[::b]Music[-:-:-] ["song"][darkcyan]Songs[""] ["album"][darkcyan]Albums[""] ["artist"][darkcyan]Artists[""]`
+)
+
 // SetupList sets up a table to display search results.
 func SetupList() {
 	ResultsList = tview.NewTable()
@@ -43,9 +48,7 @@ func SetupList() {
 	resultPageMark.SetRegions(true)
 	resultPageMark.SetDynamicColors(true)
 	resultPageMark.SetBackgroundColor(tcell.ColorDefault)
-	resultPageMark.SetText(
-		`[::b]Search[-:-:-] ["video"][darkcyan]Videos[""] ["playlist"][darkcyan]Playlists[""] ["channel"][darkcyan]Channels[""]`,
-	)
+	resultPageMark.SetText(searchTabs)
 
 	box := tview.NewBox().
 		SetBackgroundColor(tcell.ColorDefault)
@@ -58,12 +61,12 @@ func SetupList() {
 
 	ResultsFlex.SetBackgroundColor(tcell.ColorDefault)
 
-	ResultsList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	ResultsList.SetInputCapture(contextCapture("results", func(event *tcell.EventKey) *tcell.EventKey {
 		captureListEvents(event)
 		capturePlayerEvent(event)
 
 		return event
-	})
+	}))
 
 	suggestionList = tview.NewTable()
 	suggestionList.SetSelectorWrap(true)
@@ -117,122 +120,181 @@ func SearchAndList(text string) {
 	}
 
 	App.QueueUpdateDraw(func() {
-		searchAndList(results)
-	})
+		if !searchAndList(results) {
+			InfoMessage("All fetched results are watched and hidden", false)
+			return
+		}
 
-	InfoMessage("Results fetched", false)
+		InfoMessage("Results fetched", false)
+	})
 }
 
-// searchAndList renders the search results list.
-func searchAndList(results []lib.SearchResult) {
-	var skipped int
+// searchAndList renders the search results list. If results are grouped
+// and the results are of mixed types, the list is rendered in sections.
+// It returns false if all the results are hidden since they are watched.
+func searchAndList(results []lib.SearchResult) bool {
+	var hidden int
 
 	pos := -1
 	rows := ResultsList.GetRowCount()
 	_, _, width, _ := VPage.GetRect()
 
-	for i, result := range results {
+	if rows == 0 {
+		listResults = nil
+	}
+
+	selected := len(listResults)
+	watched := watchedVideos()
+
+	for _, result := range results {
 		select {
 		case <-lib.SearchCtx().Done():
 			ResultsList.Clear()
-			return
+			return true
 
 		default:
 		}
-		var lentext string
-
 		if result.Type == "category" {
-			skipped++
 			continue
 		}
 
-		if pos < 0 {
-			pos = (rows + i) - skipped
-		}
-
 		if result.Title == "" {
 			result.Title = result.Author
 			result.Author = ""
 		}
 
-		if result.LiveNow {
-			lentext = "Live"
-		} else {
-			lentext = lib.FormatDuration(result.LengthSeconds)
+		listResults = append(listResults, result)
+		if isWatched(watched, result) {
+			hidden++
+			continue
 		}
 
-		actualRow := (rows + i) - skipped
+		if pos < 0 {
+			pos = rows
+		}
 
-		ResultsList.SetCell(actualRow, 0, tview.NewTableCell("[blue::b]"+tview.Escape(result.Title)).
-			SetExpansion(1).
-			SetReference(result).
-			SetMaxWidth((width / 4)).
-			SetSelectedStyle(mainStyle),
-		)
+		setResultRow(rows, result, width)
+		rows++
+	}
 
-		ResultsList.SetCell(actualRow, 1, tview.NewTableCell(" ").
-			SetSelectable(false).
-			SetAlign(tview.AlignRight),
-		)
+	if groupResults() {
+		showResultGroups(selected)
+	} else if pos >= 0 {
+		ResultsList.Select(pos, 0)
+		ResultsList.ScrollToEnd()
+	}
 
-		ResultsList.SetCell(actualRow, 2, tview.NewTableCell("[purple::b]"+result.Author).
-			SetSelectable(true).
-			SetMaxWidth((width / 4)).
-			SetAlign(tview.AlignLeft).
-			SetSelectedStyle(auxStyle),
-		)
+	ResultsList.SetSelectable(true, false)
 
-		ResultsList.SetCell(actualRow, 3, tview.NewTableCell(" ").
-			SetSelectable(false).
-			SetAlign(tview.AlignRight),
-		)
+	if bannerShown && len(results) > 0 {
+		bannerShown = false
+		VPage.SwitchToPage("search")
+	}
 
-		if result.Type == "playlist" || result.Type == "channel" {
-			ResultsList.SetCell(actualRow, 4, tview.NewTableCell("[pink]"+strconv.Itoa(result.VideoCount)+" videos").
-				SetSelectable(true).
-				SetAlign(tview.AlignRight).
-				SetSelectedStyle(auxStyle),
-			)
+	return pos >= 0 || hidden == 0
+}
 
-			if result.Type == "playlist" {
-				continue
-			}
-		} else {
-			ResultsList.SetCell(actualRow, 4, tview.NewTableCell("[pink]"+lentext).
-				SetSelectable(true).
-				SetAlign(tview.AlignRight).
-				SetSelectedStyle(auxStyle),
-			)
+// relistResults lists the search results again from listResults,
+// for example after watched videos are hidden or shown.
+func relistResults() {
+	if len(listResults) == 0 {
+		return
+	}
+
+	if groupResults() {
+		showResultGroups(-1)
+		return
+	}
+
+	row := 0
+	watched := watchedVideos()
+	_, _, width, _ := VPage.GetRect()
+
+	ResultsList.Clear()
+
+	for _, result := range listResults {
+		if isWatched(watched, result) {
+			continue
 		}
 
-		ResultsList.SetCell(actualRow, 5, tview.NewTableCell(" ").
-			SetSelectable(false).
-			SetAlign(tview.AlignRight),
+		setResultRow(row, result, width)
+		row++
+	}
+
+	if row == 0 {
+		InfoMessage("All results are watched and hidden", false)
+		return
+	}
+
+	ResultsList.Select(0, 0)
+}
+
+// setResultRow sets the cells of a row in ResultsList for a search result.
+func setResultRow(row int, result lib.SearchResult, width int) {
+	var authorBadge string
+	if result.Type != "channel" {
+		authorBadge = verifiedBadge(result)
+	}
+
+	ResultsList.SetCell(row, 0, tview.NewTableCell("[blue::b]"+tview.Escape(result.Title)+entryBadges(result)).
+		SetExpansion(1).
+		SetReference(result).
+		SetMaxWidth((width / 4)).
+		SetSelectedStyle(mainStyle),
+	)
+
+	ResultsList.SetCell(row, 1, tview.NewTableCell(" "+customColumn(result)).
+		SetSelectable(false).
+		SetAlign(tview.AlignRight),
+	)
+
+	ResultsList.SetCell(row, 2, tview.NewTableCell("[purple::b]"+result.Author+authorBadge).
+		SetSelectable(true).
+		SetMaxWidth((width / 4)).
+		SetAlign(tview.AlignLeft).
+		SetSelectedStyle(auxStyle),
+	)
+
+	ResultsList.SetCell(row, 3, tview.NewTableCell(" ").
+		SetSelectable(false).
+		SetAlign(tview.AlignRight),
+	)
+
+	if result.Type == "playlist" || result.Type == "channel" {
+		ResultsList.SetCell(row, 4, tview.NewTableCell("[pink]"+strconv.Itoa(result.VideoCount)+" videos").
+			SetSelectable(true).
+			SetAlign(tview.AlignRight).
+			SetSelectedStyle(auxStyle),
 		)
 
-		if result.Type == "channel" {
-			ResultsList.SetCell(actualRow, 6, tview.NewTableCell("[pink]"+lib.FormatNumber(result.SubCount)+" subs").
-				SetSelectable(true).
-				SetAlign(tview.AlignRight).
-				SetSelectedStyle(auxStyle),
-			)
-		} else {
-			ResultsList.SetCell(actualRow, 6, tview.NewTableCell("[pink]"+lib.FormatPublished(result.PublishedText)).
-				SetSelectable(true).
-				SetAlign(tview.AlignRight).
-				SetSelectedStyle(auxStyle),
-			)
+		if result.Type == "playlist" {
+			return
 		}
+	} else {
+		ResultsList.SetCell(row, 4, tview.NewTableCell("[pink]"+entryLength(result)).
+			SetSelectable(true).
+			SetAlign(tview.AlignRight).
+			SetSelectedStyle(auxStyle),
+		)
 	}
 
-	ResultsList.Select(pos, 0)
-	ResultsList.ScrollToEnd()
-
-	ResultsList.SetSelectable(true, false)
+	ResultsList.SetCell(row, 5, tview.NewTableCell(" ").
+		SetSelectable(false).
+		SetAlign(tview.AlignRight),
+	)
 
-	if bannerShown && len(results) > 0 {
-		bannerShown = false
-		VPage.SwitchToPage("search")
+	if result.Type == "channel" {
+		ResultsList.SetCell(row, 6, tview.NewTableCell("[pink]"+lib.FormatNumber(result.SubCount)+" subs").
+			SetSelectable(true).
+			SetAlign(tview.AlignRight).
+			SetSelectedStyle(auxStyle),
+		)
+	} else {
+		ResultsList.SetCell(row, 6, tview.NewTableCell("[pink]"+lib.FormatPublished(result.PublishedText, result.Published)).
+			SetSelectable(true).
+			SetAlign(tview.AlignRight).
+			SetSelectedStyle(auxStyle),
+		)
 	}
 }
 
@@ -337,7 +399,13 @@ func searchSuggestions(text string) {
 func captureListEvents(event *tcell.EventKey) {
 	switch event.Key() {
 	case tcell.KeyEnter:
-		loadMoreResults()
+		if isAudioPlayKey(event) {
+			break
+		}
+
+		if !toggleResultGroup() {
+			loadMoreResults()
+		}
 	}
 
 	switch event.Rune() {
@@ -361,6 +429,9 @@ func captureListEvents(event *tcell.EventKey) {
 
 	case ';':
 		showLinkPopup()
+
+	case 'H':
+		toggleHideWatched()
 	}
 }
 
@@ -434,14 +505,22 @@ func searchText(channel bool) {
 	}
 
 	sfunc := func(text string) {
+		live := !channel && isLiveQuery(text)
+
+		stopLiveSearch()
 		table := srchfocus()
 
+		if live {
+			lib.AddToHistory(text)
+			return
+		}
+
 		if text != "" {
 			lib.AddToHistory(text)
 			table.Clear()
 			table.SetSelectable(false, false)
-			resultPageMark.Highlight(stype)
-			lib.SearchCancel()
+			highlightSearchType()
+			lib.JobCancel("search")
 		} else {
 			return
 		}
@@ -458,6 +537,7 @@ func searchText(channel bool) {
 			go searchSuggestions(InputBox.GetText())
 
 		case tcell.KeyEscape:
+			stopLiveSearch()
 			srchfocus()
 			lib.HistoryReset()
 
@@ -496,6 +576,12 @@ func searchText(channel bool) {
 			if e.Modifiers() == tcell.ModAlt {
 				go searchParamPopup()
 			}
+
+		case 'm':
+			if e.Modifiers() == tcell.ModAlt && !channel {
+				InputBox.SetLabel(toggleMusic())
+				return nil
+			}
 		}
 
 		return e
@@ -516,10 +602,16 @@ func searchText(channel bool) {
 		label += " (" + stype + "):"
 	}
 
+	if !channel && lib.LiveSearch() {
+		SetInput(label, 0, sfunc, ifunc, liveSearch)
+		return
+	}
+
 	SetInput(label, 0, sfunc, ifunc)
 }
 
-// toggleSearch toggles the search type.
+// toggleSearch toggles the search type, within the
+// music search types if music search is enabled.
 func toggleSearch() string {
 	switch stype {
 	case "video":
@@ -528,7 +620,19 @@ func toggleSearch() string {
 	case "playlist":
 		stype = "channel"
 
+	case "song":
+		stype = "album"
+
+	case "album":
+		stype = "artist"
+
+	case "artist":
+		stype = "song"
+
 	case "channel":
+		stype = "all"
+
+	case "all":
 		fallthrough
 
 	default:
@@ -538,6 +642,44 @@ func toggleSearch() string {
 	return "[::b]Search (" + stype + "): "
 }
 
+// toggleMusic toggles between the search types and the music
+// search types, which search for songs, albums and artists.
+func toggleMusic() string {
+	switch stype {
+	case "video":
+		stype = "song"
+
+	case "playlist":
+		stype = "album"
+
+	case "channel":
+		stype = "artist"
+
+	case "song":
+		stype = "video"
+
+	case "album":
+		stype = "playlist"
+
+	case "artist":
+		stype = "channel"
+	}
+
+	return "[::b]Search (" + stype + "): "
+}
+
+// highlightSearchType shows the tabs for the search types or the
+// music search types, and highlights the current search type.
+func highlightSearchType() {
+	if lib.IsMusicSearch(stype) {
+		resultPageMark.SetText(musicTabs)
+	} else {
+		resultPageMark.SetText(searchTabs)
+	}
+
+	resultPageMark.Highlight(stype)
+}
+
 // loadMoreResults appends more search results to ResultsList
 func loadMoreResults() {
 	go SearchAndList("")
@@ -744,7 +886,7 @@ func parseSearchCmd() {
 	VPage.SwitchToPage("search")
 
 	stype = searchtype
-	resultPageMark.Highlight(stype)
+	highlightSearchType()
 
 	lib.AddToHistory(searchquery)
 	go SearchAndList(searchquery)
@@ -774,19 +916,12 @@ func getListReference() (lib.SearchResult, error) {
 
 	row, _ := table.GetSelection()
 
-	for col := 0; col <= 1; col++ {
-		cell := table.GetCell(row, col)
-		if cell == nil {
-			return lib.SearchResult{}, err
-		}
-
-		info, ok := cell.GetReference().(lib.SearchResult)
-		if ok {
-			return info, nil
-		}
+	info, ok := getCellReference(table, row)
+	if !ok {
+		return lib.SearchResult{}, err
 	}
 
-	return lib.SearchResult{}, err
+	return info, nil
 }
 
 // modifyListReference modifies a TableCell containing the specified reference.
@@ -804,12 +939,13 @@ func modifyListReference(title string, add bool, info ...lib.SearchResult) error
 			continue
 		}
 
-		ref := cell.GetReference()
-		if ref == nil {
+		// Group header rows have a string reference, and are skipped.
+		ref, ok := cell.GetReference().(lib.SearchResult)
+		if !ok {
 			continue
 		}
 
-		if info[0] == ref.(lib.SearchResult) {
+		if info[0] == ref {
 			if add {
 				cell.SetText(title)
 				cell.SetReference(info[1])
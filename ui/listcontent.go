@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+)
+
+// listCachedRows is the number of rows for which a listContent keeps cells.
+const listCachedRows = 256
+
+// listContent is the content of a table which lists videos. The cells of
+// a row are created from its entry only when the row is shown, and only
+// the cells of recently shown rows are kept, so that long playlists and
+// channels do not keep cells for every entry in memory.
+type listContent struct {
+	tview.TableContentReadOnly
+
+	entries []lib.SearchResult
+
+	cells map[int][]*tview.TableCell
+	order []int
+
+	// texts stores the text of the first column of rows whose
+	// cells were modified, for example when they were marked.
+	texts map[int]string
+}
+
+// newListContent returns a new listContent.
+func newListContent() *listContent {
+	return &listContent{
+		cells: make(map[int][]*tview.TableCell),
+		texts: make(map[int]string),
+	}
+}
+
+// Append adds an entry to the end of the list.
+func (l *listContent) Append(entry lib.SearchResult) {
+	l.entries = append(l.entries, entry)
+}
+
+// GetCell returns the cell at the given position, creating
+// the cells of the row if they are not kept.
+func (l *listContent) GetCell(row, column int) *tview.TableCell {
+	if row < 0 || row >= len(l.entries) || column < 0 || column > 1 {
+		return nil
+	}
+
+	cells, ok := l.cells[row]
+	if !ok {
+		cells = listCells(l.entries[row])
+		if text, ok := l.texts[row]; ok {
+			cells[0].SetText(text)
+			delete(l.texts, row)
+		}
+
+		l.cells[row] = cells
+		l.order = append(l.order, row)
+
+		l.evict()
+	}
+
+	return cells[column]
+}
+
+// Find returns the row of the video with the given ID, or -1 if it is not found.
+func (l *listContent) Find(id string) int {
+	for row, entry := range l.entries {
+		if entry.Type == "video" && entry.VideoID == id {
+			return row
+		}
+	}
+
+	return -1
+}
+
+// GetRowCount returns the number of rows.
+func (l *listContent) GetRowCount() int {
+	return len(l.entries)
+}
+
+// GetColumnCount returns the number of columns.
+func (l *listContent) GetColumnCount() int {
+	if len(l.entries) == 0 {
+		return 0
+	}
+
+	return 2
+}
+
+// RemoveRow removes the row at the given position.
+func (l *listContent) RemoveRow(row int) {
+	if row < 0 || row >= len(l.entries) {
+		return
+	}
+
+	l.flush()
+
+	texts := make(map[int]string, len(l.texts))
+	for r, text := range l.texts {
+		switch {
+		case r < row:
+			texts[r] = text
+
+		case r > row:
+			texts[r-1] = text
+		}
+	}
+
+	l.texts = texts
+	l.entries = append(l.entries[:row], l.entries[row+1:]...)
+}
+
+// Clear removes all rows.
+func (l *listContent) Clear() {
+	l.entries = nil
+	l.order = nil
+	l.cells = make(map[int][]*tview.TableCell)
+	l.texts = make(map[int]string)
+}
+
+// evict removes the cells of the least recently created rows,
+// until the cells of at most listCachedRows rows are kept.
+func (l *listContent) evict() {
+	for len(l.order) > listCachedRows {
+		l.release(l.order[0])
+		l.order = l.order[1:]
+	}
+}
+
+// flush removes the cells of all rows.
+func (l *listContent) flush() {
+	for _, row := range l.order {
+		l.release(row)
+	}
+
+	l.order = nil
+}
+
+// release removes the cells of a row, and stores the text
+// of its first column if it was modified.
+func (l *listContent) release(row int) {
+	cells, ok := l.cells[row]
+	if !ok {
+		return
+	}
+
+	delete(l.cells, row)
+
+	if text := cells[0].Text; text != listCells(l.entries[row])[0].Text {
+		l.texts[row] = text
+	}
+}
+
+// tableListContent returns the listContent of the table, or nil
+// if its cells are not created from a listContent.
+func tableListContent(table *tview.Table) *listContent {
+	switch table {
+	case plistTable:
+		return plistContent
+
+	case chVideoTable:
+		return chVideoList
+	}
+
+	return nil
+}
+
+// listCells returns the cells of a row for an entry.
+func listCells(entry lib.SearchResult) []*tview.TableCell {
+	_, _, width, _ := ResultsList.GetRect()
+
+	return []*tview.TableCell{
+		tview.NewTableCell("[blue::b]" + tview.Escape(entry.Title) + entryBadges(entry)).
+			SetExpansion(1).
+			SetReference(entry).
+			SetMaxWidth((width / 4)).
+			SetSelectedStyle(mainStyle),
+		tview.NewTableCell(customColumn(entry) + episodeLabel(entry) + "[pink]" + entryLength(entry)).
+			SetSelectable(true).
+			SetAlign(tview.AlignRight).
+			SetSelectedStyle(auxStyle),
+	}
+}
+
+// entryBadges returns colored tags for an entry, which mark verified
+// channels, and live streams, premieres, members-only and age-restricted
+// videos. Invidious marks members-only and paid videos as premium.
+func entryBadges(entry lib.SearchResult) string {
+	var badges string
+
+	switch entry.Type {
+	case "channel":
+		return verifiedBadge(entry)
+
+	case "video":
+
+	default:
+		return ""
+	}
+
+	switch {
+	case entry.LiveNow:
+		badges += " [red::b]LIVE[-:-:-]"
+
+	case entry.IsUpcoming:
+		badges += " [yellow::b]PREMIERE[-:-:-]"
+	}
+
+	if entry.Premium {
+		badges += " [purple::b]MEMBERS[-:-:-]"
+	}
+
+	if lib.IsAgeRestricted(entry.VideoID) {
+		badges += " [red::b]18+[-:-:-]"
+	}
+
+	return badges
+}
+
+// verifiedBadge returns a tag for channels, or entries
+// from channels, which are verified.
+func verifiedBadge(entry lib.SearchResult) string {
+	if !entry.AuthorVerified {
+		return ""
+	}
+
+	return " [green::b]✓[-:-:-]"
+}
+
+// episodeLabel returns a label with the playback state of entries
+// which are episodes of podcast channels.
+func episodeLabel(entry lib.SearchResult) string {
+	if entry.Type != "video" || !lib.IsPodcast(entry.AuthorID) {
+		return ""
+	}
+
+	state := lib.EpisodeState(entry.VideoID)
+
+	switch {
+	case state.Played:
+		return "[green]played[-] "
+
+	case state.Position > 0:
+		return "[yellow]" + lib.FormatDuration(state.Position) + " /[-] "
+	}
+
+	return ""
+}
+
+// customColumn returns the text which was set for the entry
+// by the on_list_columns hook.
+func customColumn(entry lib.SearchResult) string {
+	text := lib.EntryColumn(entry)
+	if text == "" {
+		return ""
+	}
+
+	return "[grey]" + tview.Escape(text) + "[-] "
+}
+
+// entryLength returns the length of an entry for display in lists,
+// or the time left until its start if it is an upcoming premiere.
+func entryLength(entry lib.SearchResult) string {
+	switch {
+	case lib.IsPremiere(entry):
+		return "Premiere in " + lib.PremiereCountdown(entry.PremiereTimestamp)
+
+	case entry.LiveNow:
+		return "Live"
+	}
+
+	return lib.FormatLength(entry.LengthSeconds)
+}
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	// liveChatInterval is the interval at which the live chat is
+	// polled again after an error.
+	liveChatInterval = 5 * time.Second
+
+	// liveChatLines is the number of messages kept in the live chat pane.
+	liveChatLines = 200
+
+	// The default and minimum widths of the live chat pane, and
+	// the step by which it is resized.
+	liveChatDefaultWidth = 40
+	liveChatMinWidth     = 20
+	liveChatWidthStep    = 5
+)
+
+var (
+	liveChatPane  *tview.Flex
+	liveChatTitle *tview.TextView
+	liveChatView  *tview.TextView
+
+	liveChatShown  bool
+	liveChatWidth  = liveChatDefaultWidth
+	liveChatCancel context.CancelFunc
+)
+
+// setupLiveChat sets up the live chat pane.
+func setupLiveChat() {
+	liveChatTitle = tview.NewTextView()
+	liveChatTitle.SetDynamicColors(true)
+	liveChatTitle.SetTextAlign(tview.AlignCenter)
+	liveChatTitle.SetBackgroundColor(tcell.ColorDefault)
+
+	liveChatView = tview.NewTextView()
+	liveChatView.SetWrap(true)
+	liveChatView.SetWordWrap(true)
+	liveChatView.SetDynamicColors(true)
+	liveChatView.SetBackgroundColor(tcell.ColorDefault)
+
+	liveChatPane = tview.NewFlex().
+		AddItem(liveChatTitle, 1, 0, false).
+		AddItem(liveChatView, 0, 10, false).
+		SetDirection(tview.FlexRow)
+	liveChatPane.SetBackgroundColor(tcell.ColorDefault)
+}
+
+// toggleLiveChat shows the live chat pane for the playing live
+// stream, or hides it if it is already shown. It must be called
+// from the UI goroutine.
+func toggleLiveChat() {
+	if liveChatShown {
+		hideLiveChat()
+		return
+	}
+
+	data := lib.PlayingData()
+	if data == nil || data.Get("length") != "Live" || data.Get("videoid") == "" {
+		InfoMessage("No live stream is playing", false)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	liveChatCancel = cancel
+	liveChatShown = true
+
+	liveChatView.Clear()
+	liveChatTitle.SetText("[::bu]Live chat")
+	ViewFlex.AddItem(liveChatPane, liveChatWidth, 0, false)
+
+	go pollLiveChat(ctx, data.Get("videoid"))
+}
+
+// hideLiveChat stops polling the live chat and hides the pane.
+// It must be called from the UI goroutine.
+func hideLiveChat() {
+	if !liveChatShown {
+		return
+	}
+
+	liveChatCancel()
+	liveChatShown = false
+
+	ViewFlex.RemoveItem(liveChatPane)
+}
+
+// resizeLiveChat changes the width of the live chat pane by the
+// given number of columns, up to half the width of the screen.
+// It must be called from the UI goroutine.
+func resizeLiveChat(step int) {
+	if !liveChatShown {
+		return
+	}
+
+	_, _, width, _ := UIFlex.GetRect()
+
+	liveChatWidth += step
+	if liveChatWidth > width/2 {
+		liveChatWidth = width / 2
+	}
+	if liveChatWidth < liveChatMinWidth {
+		liveChatWidth = liveChatMinWidth
+	}
+
+	ViewFlex.ResizeItem(liveChatPane, liveChatWidth, 0)
+}
+
+// pollLiveChat polls the live chat of the live stream with the given ID,
+// and shows the new messages in the pane, until the pane is hidden, the
+// live chat ends or the live stream stops playing.
+func pollLiveChat(ctx context.Context, id string) {
+	var lines []string
+
+	chat := lib.NewLiveChat(id)
+
+	t := time.NewTimer(0)
+	defer t.Stop()
+
+	closeChat := func() {
+		App.QueueUpdateDraw(func() {
+			if ctx.Err() == nil {
+				hideLiveChat()
+			}
+		})
+	}
+
+	setTitle := func(text string) {
+		App.QueueUpdateDraw(func() {
+			if ctx.Err() == nil {
+				liveChatTitle.SetText(text)
+			}
+		})
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-t.C:
+		}
+
+		if lib.GetMPV().PlayingVideoID() != id {
+			closeChat()
+			return
+		}
+
+		messages, interval, err := chat.Messages(ctx)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+
+		case errors.Is(err, lib.ErrLiveChatUnavailable), errors.Is(err, lib.ErrLiveChatEnded):
+			InfoMessage(err.Error(), false)
+			closeChat()
+			return
+
+		case err != nil:
+			interval = liveChatInterval
+			setTitle("[::bu]Live chat[-:-:-] [grey](reconnecting)")
+
+		case messages != nil:
+			for _, message := range messages {
+				lines = append(lines, liveChatLine(message))
+			}
+			if len(lines) > liveChatLines {
+				lines = lines[len(lines)-liveChatLines:]
+			}
+
+			text := strings.Join(lines, "\n")
+
+			App.QueueUpdateDraw(func() {
+				if ctx.Err() != nil {
+					return
+				}
+
+				liveChatTitle.SetText("[::bu]Live chat")
+				liveChatView.SetText(text)
+				liveChatView.ScrollToEnd()
+			})
+
+		default:
+			setTitle("[::bu]Live chat")
+		}
+
+		t.Reset(interval)
+	}
+}
+
+// liveChatLine renders a live chat message with its time and author,
+// and the amount which was paid for it if it is a paid message.
+func liveChatLine(message lib.LiveChatMessage) string {
+	line := "[grey]" + message.Time.Format("15:04") + "[-] " +
+		"[purple::b]" + tview.Escape(message.Author) + "[-:-:-] "
+
+	if message.Amount != "" {
+		line += "[yellow::b]" + tview.Escape(message.Amount) + "[-:-:-] "
+	}
+
+	return line + tview.Escape(message.Message)
+}
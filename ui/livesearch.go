@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// liveSearchDelay is the time to wait after the search text
+// is changed, before suggestions and results are fetched.
+const liveSearchDelay = 400 * time.Millisecond
+
+// liveSearchMinLength is the minimum length of the search
+// text, before suggestions and results are fetched.
+const liveSearchMinLength = 3
+
+var (
+	liveTimer *time.Timer
+	liveQuery string
+	liveLock  sync.Mutex
+)
+
+// liveSearch fetches suggestions and results for the search text, if it isn't
+// changed again within liveSearchDelay. Any search that is still in progress
+// is canceled, and the results are shown while the input is still focused.
+func liveSearch(text string) {
+	liveLock.Lock()
+	defer liveLock.Unlock()
+
+	if liveTimer != nil {
+		liveTimer.Stop()
+	}
+
+	text = strings.TrimSpace(text)
+	if len(text) < liveSearchMinLength {
+		return
+	}
+
+	liveTimer = time.AfterFunc(liveSearchDelay, func() {
+		liveLock.Lock()
+		liveQuery = text
+		liveLock.Unlock()
+
+		searchSuggestions(text)
+
+		lib.JobCancel("search")
+		searchLock.Acquire(context.Background(), 1)
+		searchLock.Release(1)
+
+		if !isLiveQuery(text) {
+			return
+		}
+
+		App.QueueUpdateDraw(func() {
+			ResultsList.Clear()
+			ResultsList.SetSelectable(false, false)
+			highlightSearchType()
+		})
+
+		SearchAndList(text)
+	})
+}
+
+// stopLiveSearch stops any pending live search.
+func stopLiveSearch() {
+	liveLock.Lock()
+	defer liveLock.Unlock()
+
+	if liveTimer != nil {
+		liveTimer.Stop()
+	}
+
+	liveQuery = ""
+}
+
+// isLiveQuery returns whether the results for text were fetched by live search.
+func isLiveQuery(text string) bool {
+	liveLock.Lock()
+	defer liveLock.Unlock()
+
+	return liveQuery != "" && liveQuery == strings.TrimSpace(text)
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+var (
+	lyricsCancel context.CancelFunc
+
+	lyricsPrevPage string
+	lyricsPrevItem tview.Primitive
+)
+
+// ShowLyrics fetches and shows the lyrics for the currently playing track.
+// If synced lyrics are available, the current line is highlighted as the
+// track plays.
+func ShowLyrics() {
+	data := lib.PlayingData()
+	if data == nil || !isPlaying() {
+		InfoMessage("Nothing is playing", false)
+		return
+	}
+
+	title, author := data.Get("title"), data.Get("author")
+
+	InfoMessage("Fetching lyrics for "+tview.Escape(title), true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	lyrics, err := lib.Lyrics(ctx, title, author)
+	cancel()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Fetched lyrics for "+tview.Escape(title), false)
+
+	var text strings.Builder
+
+	lines := lib.ParseSyncedLyrics(lyrics.SyncedLyrics)
+	if lines != nil {
+		for i, line := range lines {
+			text.WriteString(`["` + strconv.Itoa(i) + `"]` + tview.Escape(line.Text) + `[""]` + "\n")
+		}
+	} else {
+		text.WriteString(tview.Escape(lyrics.PlainLyrics))
+	}
+
+	artist, track := lib.ParseTrack(title, author)
+
+	App.QueueUpdateDraw(func() {
+		if lyricsCancel != nil {
+			lyricsCancel()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		lyricsCancel = cancel
+
+		lyricsTitle := tview.NewTextView()
+		lyricsTitle.SetDynamicColors(true)
+		lyricsTitle.SetText("[::bu]" + tview.Escape(artist+" - "+track))
+		lyricsTitle.SetTextAlign(tview.AlignCenter)
+		lyricsTitle.SetBackgroundColor(tcell.ColorDefault)
+
+		lyricsView := tview.NewTextView()
+		lyricsView.SetWrap(true)
+		lyricsView.SetRegions(true)
+		lyricsView.SetDynamicColors(true)
+		lyricsView.SetTextAlign(tview.AlignCenter)
+		lyricsView.SetText(text.String())
+		lyricsView.SetBackgroundColor(tcell.ColorDefault)
+		lyricsView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			captureSendPlayerEvent(event)
+
+			switch event.Key() {
+			case tcell.KeyEscape:
+				cancel()
+				VPage.SwitchToPage(lyricsPrevPage)
+				App.SetFocus(lyricsPrevItem)
+			}
+
+			return event
+		})
+
+		lyricsFlex := tview.NewFlex().
+			AddItem(lyricsTitle, 1, 0, false).
+			AddItem(lyricsView, 0, 10, false).
+			SetDirection(tview.FlexRow)
+
+		if pg, _ := VPage.GetFrontPage(); pg != "lyrics" {
+			lyricsPrevPage, lyricsPrevItem = VPage.GetFrontPage()
+		}
+
+		MPage.SwitchToPage("ui")
+		VPage.AddAndSwitchToPage("lyrics", lyricsFlex, true)
+
+		App.SetFocus(lyricsView)
+
+		if lines != nil {
+			go syncLyrics(ctx, lyricsView, lines, title)
+		}
+	})
+}
+
+// syncLyrics highlights the line of the lyrics which corresponds to
+// the current playback position, until the track changes.
+func syncLyrics(ctx context.Context, view *tview.TextView, lines []lib.LyricLine, title string) {
+	prev := -1
+
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-t.C:
+		}
+
+		if lib.PlayingData().Get("title") != title {
+			return
+		}
+
+		pos := float64(lib.GetMPV().TimePosition())
+
+		line := sort.Search(len(lines), func(i int) bool {
+			return lines[i].Time > pos
+		}) - 1
+		if line < 0 || line == prev {
+			continue
+		}
+
+		prev = line
+
+		App.QueueUpdateDraw(func() {
+			view.Highlight(strconv.Itoa(line)).ScrollToHighlight()
+		})
+	}
+}
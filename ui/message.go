@@ -3,8 +3,10 @@ package ui
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 )
@@ -22,6 +24,11 @@ var (
 	//MessageBox is an area to display messages.
 	MessageBox *tview.TextView
 
+	statusFlex      *tview.Flex
+	statusIndicator *tview.TextView
+	indicators      map[string]string
+	indicatorOrder  []string
+
 	sctx    context.Context
 	scancel context.CancelFunc
 	msgchan chan message
@@ -31,13 +38,29 @@ var (
 func SetupStatus() {
 	Status = tview.NewPages()
 
+	statusIndicator = tview.NewTextView()
+	statusIndicator.SetDynamicColors(true)
+	statusIndicator.SetTextAlign(tview.AlignRight)
+	statusIndicator.SetBackgroundColor(tcell.ColorDefault)
+
+	statusFlex = tview.NewFlex().
+		AddItem(MessageBox, 0, 1, false).
+		AddItem(statusIndicator, 0, 0, false).
+		SetDirection(tview.FlexColumn)
+	statusFlex.SetBackgroundColor(tcell.ColorDefault)
+
+	indicators = make(map[string]string)
+
 	Status.AddPage("input", InputBox, true, true)
-	Status.AddPage("messages", MessageBox, true, true)
+	Status.AddPage("messages", statusFlex, true, true)
 
 	msgchan = make(chan message, 10)
 	sctx, scancel = context.WithCancel(context.Background())
 
 	go startStatus()
+	go monitorNetwork()
+	go monitorJobs()
+	go monitorPremieres()
 }
 
 // StopStatus stops the message event loop.
@@ -70,6 +93,9 @@ func ErrorMessage(err error) {
 		return
 	}
 
+	addToErrorLog(err.Error())
+	lib.LogError("error message", "error", err)
+
 	select {
 	case msgchan <- message{"[red::b]" + err.Error(), false}:
 		return
@@ -78,6 +104,51 @@ func ErrorMessage(err error) {
 	}
 }
 
+// setStatusIndicator sets or, if text is empty, removes a persistent
+// indicator on the right side of the status bar. It must be called
+// from the UI goroutine.
+func setStatusIndicator(name, text string) {
+	if _, ok := indicators[name]; !ok && text != "" {
+		indicatorOrder = append(indicatorOrder, name)
+	}
+
+	if text == "" {
+		delete(indicators, name)
+
+		for i, n := range indicatorOrder {
+			if n == name {
+				indicatorOrder = append(indicatorOrder[:i], indicatorOrder[i+1:]...)
+				break
+			}
+		}
+	} else {
+		indicators[name] = text
+	}
+
+	showStatusIndicators()
+}
+
+// showStatusIndicators shows the indicators in the status bar. The
+// indicators are hidden in the compact layout.
+func showStatusIndicators() {
+	var texts []string
+	for _, n := range indicatorOrder {
+		texts = append(texts, indicators[n])
+	}
+
+	indicatorText := strings.Join(texts, " | ")
+	width := tview.TaggedStringWidth(indicatorText)
+	if width > 0 {
+		width++
+	}
+	if compactLayout {
+		width = 0
+	}
+
+	statusIndicator.SetText(indicatorText)
+	statusFlex.ResizeItem(statusIndicator, width, 0)
+}
+
 // startStatus starts the message event loop
 func startStatus() {
 	var text string
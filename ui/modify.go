@@ -13,18 +13,23 @@ var (
 )
 
 // Modify retrieves the reference from the table in focus, determines
-// its type and runs the appropriate modification handler.
+// its type and runs the appropriate modification handler. If entries
+// are marked, all marked entries of the same type are modified.
 func Modify(add bool) {
 	var err error
 	var info lib.SearchResult
+	var entries []lib.SearchResult
 
 	App.QueueUpdateDraw(func() {
-		info, err = getListReference()
+		entries, err = getSelectedEntries()
 	})
 	if err != nil {
 		return
 	}
 
+	info = entries[0]
+	entries = filterEntries(entries, info.Type)
+
 	modifyMapLock.Lock()
 	if modifyMap == nil {
 		modifyMap = make(map[string]*semaphore.Weighted)
@@ -48,12 +53,29 @@ func Modify(add bool) {
 
 	switch info.Type {
 	case "video":
-		modifyPlaylistVideo(info, add)
+		modifyPlaylistVideo(entries, add)
 
 	case "playlist":
-		modifyPlaylist(info, add)
+		for _, entry := range entries {
+			modifyPlaylist(entry, add)
+		}
 
 	case "channel":
-		modifyChannelSubscription(info, add)
+		for _, entry := range entries {
+			modifyChannelSubscription(entry, add)
+		}
 	}
 }
+
+// filterEntries returns the entries which match the provided type.
+func filterEntries(entries []lib.SearchResult, mtype string) []lib.SearchResult {
+	var filtered []lib.SearchResult
+
+	for _, entry := range entries {
+		if entry.Type == mtype {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
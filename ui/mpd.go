@@ -0,0 +1,691 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// mpdVersion is the MPD protocol version reported to clients.
+const mpdVersion = "0.21.0"
+
+// mpdError stores an error which is sent to an MPD client.
+type mpdError struct {
+	code int
+	msg  string
+}
+
+// mpdState stores the player state which is compared to
+// detect changes for clients waiting in the idle command.
+type mpdState struct {
+	playlist uint32
+	pos      int
+	paused   bool
+	stopped  bool
+	volume   int
+	loop     string
+	shuffle  bool
+}
+
+var (
+	mpdListener net.Listener
+	mpdStarted  time.Time
+)
+
+// Error returns the error message.
+func (e mpdError) Error() string {
+	return e.msg
+}
+
+// startMPD starts a server which understands a subset of the MPD
+// protocol, so that MPD clients can control the player. The server
+// has no password support, so it only listens on a loopback address.
+func startMPD() error {
+	addr := lib.MPDAddress()
+	if addr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("Cannot start the MPD server on %s", addr)
+	}
+
+	mpdListener = listener
+	mpdStarted = time.Now()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handleMPDClient(conn)
+		}
+	}()
+
+	return nil
+}
+
+// stopMPD stops the MPD server.
+func stopMPD() {
+	if mpdListener != nil {
+		mpdListener.Close()
+	}
+}
+
+// handleMPDClient reads commands and command lists from
+// the client, runs them and writes the responses.
+func handleMPDClient(conn net.Conn) {
+	var list []string
+	var inList, listOK bool
+
+	defer conn.Close()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	w := bufio.NewWriter(conn)
+	fmt.Fprintf(w, "OK MPD %s\n", mpdVersion)
+	w.Flush()
+
+	for line := range lines {
+		switch {
+		case line == "command_list_begin", line == "command_list_ok_begin":
+			inList, listOK, list = true, line == "command_list_ok_begin", nil
+			continue
+
+		case line == "command_list_end":
+			failed := false
+			inList = false
+
+			for i, command := range list {
+				if err := runMPDCommand(w, command, lines); err != nil {
+					writeMPDError(w, i, command, err)
+					failed = true
+					break
+				}
+
+				if listOK {
+					w.WriteString("list_OK\n")
+				}
+			}
+
+			if !failed {
+				w.WriteString("OK\n")
+			}
+
+		case inList:
+			list = append(list, line)
+			continue
+
+		case line == "close":
+			return
+
+		default:
+			if err := runMPDCommand(w, line, lines); err != nil {
+				writeMPDError(w, 0, line, err)
+			} else {
+				w.WriteString("OK\n")
+			}
+		}
+
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
+
+// runMPDCommand runs a single MPD command.
+//
+//gocyclo:ignore
+func runMPDCommand(w io.Writer, line string, lines chan string) error {
+	args := parseMPDArgs(line)
+	if len(args) == 0 {
+		return mpdError{5, "No command given"}
+	}
+
+	command, args := args[0], args[1:]
+	mpv := lib.GetMPV()
+
+	switch command {
+	case "ping", "clearerror", "noidle", "consume", "crossfade",
+		"tagtypes", "decoders", "urlhandlers", "listplaylists",
+		"lsinfo", "list", "listall", "channels", "readmessages", "notcommands":
+
+	case "commands":
+		for _, name := range []string{
+			"add", "addid", "clear", "currentsong", "delete", "deleteid",
+			"getvol", "idle", "move", "next", "noidle", "outputs", "pause",
+			"ping", "play", "playid", "playlistinfo", "plchanges", "previous",
+			"random", "repeat", "seek", "seekcur", "seekid", "setvol", "single",
+			"stats", "status", "stop", "volume",
+		} {
+			fmt.Fprintf(w, "command: %s\n", name)
+		}
+
+	case "idle":
+		return mpdIdle(w, lines)
+
+	case "status":
+		writeMPDStatus(w)
+
+	case "stats":
+		fmt.Fprintf(w, "uptime: %d\nplaytime: %d\nartists: 0\nalbums: 0\nsongs: %d\ndb_playtime: 0\n",
+			int64(time.Since(mpdStarted).Seconds()), mpv.TimePosition(), mpv.PlaylistCount())
+
+	case "outputs":
+		fmt.Fprint(w, "outputid: 0\noutputname: mpv\nplugin: mpv\noutputenabled: 1\n")
+
+	case "replay_gain_status":
+		fmt.Fprint(w, "replay_gain_mode: off\n")
+
+	case "getvol":
+		fmt.Fprintf(w, "volume: %d\n", mpv.Volume())
+
+	case "currentsong":
+		if !isPlaying() || mpv.IsIdle() {
+			break
+		}
+
+		pos := mpv.PlaylistPos()
+		ids := mpdEntryIDs()
+		if list := updatePlaylist(); pos >= 0 && pos < len(list) && pos < len(ids) {
+			writeMPDSong(w, pos, ids[pos], list[pos])
+		}
+
+	case "playlistinfo", "playlistid", "plchanges":
+		ids := mpdEntryIDs()
+		list := updatePlaylist()
+		for pos, data := range list {
+			if pos >= len(ids) {
+				break
+			}
+
+			if len(args) > 0 && command != "plchanges" {
+				if n, err := mpdPos(args[0], command == "playlistid"); err != nil || n != pos {
+					continue
+				}
+			}
+
+			writeMPDSong(w, pos, ids[pos], data)
+		}
+
+	case "play", "playid":
+		if len(args) > 0 {
+			pos, err := mpdPos(args[0], command == "playid")
+			if err != nil {
+				return err
+			}
+
+			mpv.SetPlaylistPos(pos)
+		}
+
+		mpv.Play()
+
+	case "pause":
+		paused := !mpv.IsPaused()
+		if len(args) > 0 {
+			paused = args[0] == "1"
+		}
+
+		if paused != mpv.IsPaused() {
+			mpv.CyclePaused()
+		}
+
+	case "stop":
+		mpv.Set("pause", "yes")
+		mpv.Call("seek", 0, "absolute")
+
+	case "next":
+		mpv.Next()
+
+	case "previous":
+		mpv.Prev()
+
+	case "seek", "seekid":
+		if len(args) < 2 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		pos, err := mpdPos(args[0], command == "seekid")
+		if err != nil {
+			return err
+		}
+
+		secs, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return mpdError{2, "Invalid time"}
+		}
+
+		if pos == mpv.PlaylistPos() {
+			mpv.Call("seek", secs, "absolute")
+			break
+		}
+
+		// The entry is seeked once it is loaded, since
+		// a seek before it is loaded would be dropped.
+		videoID := mpdVideoID(pos)
+		if videoID == "" {
+			return mpdError{50, "Only videos can be seeked before they are played"}
+		}
+
+		lib.SeekOnLoad(videoID, int64(secs))
+		mpv.SetPlaylistPos(pos)
+
+	case "seekcur":
+		if len(args) < 1 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		secs, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return mpdError{2, "Invalid time"}
+		}
+
+		if strings.HasPrefix(args[0], "+") || strings.HasPrefix(args[0], "-") {
+			mpv.Call("seek", secs, "relative")
+		} else {
+			mpv.Call("seek", secs, "absolute")
+		}
+
+	case "setvol", "volume":
+		if len(args) < 1 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		vol, err := strconv.Atoi(args[0])
+		if err != nil {
+			return mpdError{2, "Invalid volume"}
+		}
+
+		if command == "volume" {
+			vol += mpv.Volume()
+		}
+
+		mpv.SetVolume(vol)
+
+	case "repeat", "single":
+		if len(args) < 1 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		mode := "no"
+		if args[0] == "1" {
+			mode = "inf"
+		}
+
+		if command == "single" {
+			mpv.Set("loop-file", mode)
+		} else {
+			mpv.Set("loop-playlist", mode)
+		}
+
+	case "random":
+		if len(args) < 1 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		if (args[0] == "1") != mpv.IsShuffle() {
+			mpv.CycleShuffle()
+		}
+
+	case "clear":
+		if isPlaying() {
+			controlStop(nil)
+		}
+
+	case "delete", "deleteid":
+		if len(args) < 1 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		pos, err := mpdPos(args[0], command == "deleteid")
+		if err != nil {
+			return err
+		}
+
+		mpv.PlaylistDelete(pos)
+
+	case "move":
+		if len(args) < 2 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		from, err := mpdPos(args[0], false)
+		if err != nil {
+			return err
+		}
+
+		to, err := mpdPos(args[1], false)
+		if err != nil {
+			return err
+		}
+
+		// mpv moves the entry to the position before the target.
+		if to > from {
+			to++
+		}
+
+		mpv.PlaylistMove(from, to)
+
+	case "add":
+		if len(args) < 1 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		if _, err := controlQueue(true, false)(args[:1]); err != nil {
+			return mpdError{50, err.Error()}
+		}
+
+	case "addid":
+		if len(args) < 1 {
+			return mpdError{2, "Missing argument"}
+		}
+
+		id, err := mpdAddID(args[0])
+		if err != nil {
+			return mpdError{50, err.Error()}
+		}
+
+		fmt.Fprintf(w, "Id: %d\n", id)
+
+	default:
+		return mpdError{5, "unknown command \"" + command + "\""}
+	}
+
+	sendPlayerEvent()
+
+	return nil
+}
+
+// mpdIdle waits until the player state changes, or the client
+// cancels waiting with the noidle command.
+func mpdIdle(w io.Writer, lines chan string) error {
+	prev := getMPDState()
+
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return io.EOF
+			}
+
+			if line == "noidle" {
+				return nil
+			}
+
+		case <-t.C:
+			var changed []string
+
+			curr := getMPDState()
+
+			if curr.playlist != prev.playlist {
+				changed = append(changed, "playlist")
+			}
+			if curr.pos != prev.pos || curr.paused != prev.paused || curr.stopped != prev.stopped {
+				changed = append(changed, "player")
+			}
+			if curr.volume != prev.volume {
+				changed = append(changed, "mixer")
+			}
+			if curr.loop != prev.loop || curr.shuffle != prev.shuffle {
+				changed = append(changed, "options")
+			}
+
+			if changed == nil {
+				continue
+			}
+
+			for _, subsystem := range changed {
+				fmt.Fprintf(w, "changed: %s\n", subsystem)
+			}
+
+			return nil
+		}
+	}
+}
+
+// getMPDState returns the current player state.
+func getMPDState() mpdState {
+	mpv := lib.GetMPV()
+
+	return mpdState{
+		playlist: mpdPlaylistVersion(),
+		pos:      mpv.PlaylistPos(),
+		paused:   mpv.IsPaused(),
+		stopped:  !isPlaying() || mpv.IsIdle(),
+		volume:   mpv.Volume(),
+		loop:     mpv.LoopType(),
+		shuffle:  mpv.IsShuffle(),
+	}
+}
+
+// writeMPDStatus writes the player status.
+func writeMPDStatus(w io.Writer) {
+	mpv := lib.GetMPV()
+	loop := mpv.LoopType()
+
+	state := "play"
+	switch {
+	case !isPlaying() || mpv.IsIdle():
+		state = "stop"
+
+	case mpv.IsPaused():
+		state = "pause"
+	}
+
+	fmt.Fprintf(w, "volume: %d\n", mpv.Volume())
+	fmt.Fprintf(w, "repeat: %s\n", mpdBool(loop == "loop-playlist"))
+	fmt.Fprintf(w, "random: %s\n", mpdBool(mpv.IsShuffle()))
+	fmt.Fprintf(w, "single: %s\n", mpdBool(loop == "loop-file"))
+	fmt.Fprint(w, "consume: 0\n")
+	fmt.Fprintf(w, "playlist: %d\n", mpdPlaylistVersion())
+	fmt.Fprintf(w, "playlistlength: %d\n", mpv.PlaylistCount())
+	fmt.Fprintf(w, "state: %s\n", state)
+
+	if state == "stop" {
+		return
+	}
+
+	pos := mpv.PlaylistPos()
+	elapsed, duration := mpv.TimePosition(), mpv.Duration()
+
+	id, _ := mpv.PlaylistEntryID(pos)
+
+	fmt.Fprintf(w, "song: %d\nsongid: %d\n", pos, id)
+	fmt.Fprintf(w, "time: %d:%d\nelapsed: %d\nduration: %d\n", elapsed, duration, elapsed, duration)
+}
+
+// writeMPDSong writes the information for a queue entry,
+// with the ID of its playlist entry in mpv as its song ID.
+func writeMPDSong(w io.Writer, pos, id int, data PlaylistData) {
+	file := data.Filename
+	if data.VideoID != "" {
+		file = "https://www.youtube.com/watch?v=" + data.VideoID
+	}
+
+	fmt.Fprintf(w, "file: %s\n", file)
+	fmt.Fprintf(w, "Title: %s\n", data.Title)
+	fmt.Fprintf(w, "Artist: %s\n", data.Author)
+
+	if secs, err := lib.ParseDuration(data.Duration); err == nil {
+		fmt.Fprintf(w, "Time: %d\nduration: %d\n", secs, secs)
+	}
+
+	fmt.Fprintf(w, "Pos: %d\nId: %d\n", pos, id)
+}
+
+// mpdAddID loads the video with the given URL or ID into the queue, and
+// returns the ID of its playlist entry. Unlike the add command, the video
+// is loaded before the response is sent, so that the ID is valid.
+func mpdAddID(urlOrID string) (int, error) {
+	info, err := urlMediaInfo(urlOrID)
+	if err != nil {
+		return 0, err
+	}
+
+	if info.Type != "video" {
+		return 0, fmt.Errorf("Only videos can be added with addid")
+	}
+
+	if err := lib.MPVWait(); err != nil {
+		return 0, err
+	}
+
+	lib.JobReset("video")
+
+	title, id, err := lib.QueueVideo(info.VideoID, false)
+	if err != nil {
+		return 0, err
+	}
+
+	info.Title = title
+	go addToPlayHistory(info)
+
+	return id, nil
+}
+
+// mpdEntryIDs returns the IDs of the playlist entries in mpv, which
+// are used as song IDs, since they do not change when the queue changes.
+func mpdEntryIDs() []int {
+	entries, err := lib.GetMPV().PlaylistEntries()
+	if err != nil {
+		return nil
+	}
+
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+
+	return ids
+}
+
+// mpdVideoID returns the video ID of the queue entry at pos, if it is a video.
+func mpdVideoID(pos int) string {
+	entries, err := lib.GetMPV().PlaylistEntries()
+	if err != nil || pos < 0 || pos >= len(entries) {
+		return ""
+	}
+
+	return lib.GetDataFromURL(entries[pos].Filename).Get("videoid")
+}
+
+// writeMPDError writes an error response for a command.
+func writeMPDError(w io.Writer, listNum int, line string, err error) {
+	code := 5
+	if e, ok := err.(mpdError); ok {
+		code = e.code
+	}
+
+	command := ""
+	if args := parseMPDArgs(line); len(args) > 0 {
+		command = args[0]
+	}
+
+	fmt.Fprintf(w, "ACK [%d@%d] {%s} %s\n", code, listNum, command, err.Error())
+}
+
+// mpdPlaylistVersion returns a number which changes when the queue changes.
+func mpdPlaylistVersion() uint32 {
+	h := fnv.New32a()
+
+	for _, data := range updatePlaylist() {
+		h.Write([]byte(data.Filename))
+	}
+
+	return h.Sum32()
+}
+
+// mpdPos parses a queue position, or a song ID if id is set,
+// and returns the position.
+func mpdPos(arg string, id bool) (int, error) {
+	pos, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, mpdError{2, "Invalid position " + arg}
+	}
+
+	if id {
+		for i, entryID := range mpdEntryIDs() {
+			if entryID == pos {
+				return i, nil
+			}
+		}
+
+		return 0, mpdError{50, "No such song"}
+	}
+
+	if pos < 0 || pos >= lib.GetMPV().PlaylistCount() {
+		return 0, mpdError{50, "No such song"}
+	}
+
+	return pos, nil
+}
+
+// mpdBool returns the MPD representation of a boolean.
+func mpdBool(b bool) string {
+	if b {
+		return "1"
+	}
+
+	return "0"
+}
+
+// parseMPDArgs splits a command line into arguments,
+// handling double-quoted arguments with escapes.
+func parseMPDArgs(line string) []string {
+	var args []string
+	var arg strings.Builder
+	var quoted, escaped, inArg bool
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+
+		case r == '\\' && quoted:
+			escaped = true
+
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+
+		case (r == ' ' || r == '\t') && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	markText    = "[red::b]*[-:-:-] "
+	playingText = "[green::b]>[-:-:-] "
+)
+
+// toggleMark marks or unmarks the selected entry in the focused list,
+// and moves the selection to the next entry.
+func toggleMark() {
+	table := getListTable()
+	if table == nil {
+		return
+	}
+
+	row, _ := table.GetSelection()
+	if _, ok := getCellReference(table, row); !ok {
+		return
+	}
+
+	cell := table.GetCell(row, 0)
+	if strings.HasPrefix(cell.Text, markText) {
+		cell.SetText(strings.TrimPrefix(cell.Text, markText))
+	} else {
+		cell.SetText(markText + cell.Text)
+	}
+
+	table.InputHandler()(
+		tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone),
+		nil,
+	)
+}
+
+// clearMarks unmarks all entries in the list.
+func clearMarks(table *tview.Table) {
+	if table == nil {
+		return
+	}
+
+	for row := 0; row < table.GetRowCount(); row++ {
+		cell := table.GetCell(row, 0)
+		if cell == nil {
+			continue
+		}
+
+		cell.SetText(strings.TrimPrefix(cell.Text, markText))
+	}
+}
+
+// markedEntries returns the references of all marked entries
+// in the list, in the order in which they are listed.
+func markedEntries(table *tview.Table) []lib.SearchResult {
+	var entries []lib.SearchResult
+
+	if table == nil {
+		return nil
+	}
+
+	for row := 0; row < table.GetRowCount(); row++ {
+		cell := table.GetCell(row, 0)
+		if cell == nil || !strings.HasPrefix(cell.Text, markText) {
+			continue
+		}
+
+		if info, ok := getCellReference(table, row); ok {
+			entries = append(entries, info)
+		}
+	}
+
+	return entries
+}
+
+// getSelectedEntries returns the marked entries in the focused list
+// and clears the marks. If no entries are marked, the selected entry is returned.
+func getSelectedEntries() ([]lib.SearchResult, error) {
+	table := getListTable()
+
+	entries := markedEntries(table)
+	if entries != nil {
+		clearMarks(table)
+		return entries, nil
+	}
+
+	info, err := getListReference()
+	if err != nil {
+		return nil, err
+	}
+
+	return []lib.SearchResult{info}, nil
+}
+
+// markWatched adds the selected entries to the play history.
+func markWatched() {
+	entries, err := getSelectedEntries()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	go func() {
+		for _, info := range entries {
+			addToPlayHistory(info)
+			lib.MarkEpisodePlayed(info.VideoID, info.AuthorID)
+		}
+
+		InfoMessage("Marked "+strconv.Itoa(len(entries))+" entries as watched", false)
+	}()
+}
+
+// getCellReference gets the reference stored in the given row of a table.
+func getCellReference(table *tview.Table, row int) (lib.SearchResult, bool) {
+	for col := 0; col <= 1; col++ {
+		cell := table.GetCell(row, col)
+		if cell == nil {
+			return lib.SearchResult{}, false
+		}
+
+		if info, ok := cell.GetReference().(lib.SearchResult); ok {
+			return info, true
+		}
+	}
+
+	return lib.SearchResult{}, false
+}
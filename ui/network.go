@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// monitorNetwork shows an indicator in the status bar when the instance
+// is unreachable, and periodically rechecks the connection until it is restored.
+// It also shows which instance a video was loaded from, if the current instance
+// could not serve it.
+func monitorNetwork() {
+	t := time.NewTicker(10 * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-sctx.Done():
+			return
+
+		case online := <-lib.NetworkStatus:
+			App.QueueUpdateDraw(func() {
+				if online {
+					setStatusIndicator("network", "")
+				} else {
+					setStatusIndicator("network", "[red::b]OFFLINE[-:-:-]")
+				}
+			})
+
+			if online {
+				InfoMessage("Connection restored", false)
+			} else {
+				ErrorMessage(fmt.Errorf("Instance is unreachable, retrying"))
+			}
+
+		case msg := <-lib.FallbackStatus:
+			InfoMessage(msg, false)
+
+		case <-t.C:
+			if client := lib.GetClient(); client != nil && !lib.IsOnline() {
+				client.CheckConnection()
+			}
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -22,6 +24,7 @@ var (
 	Player *tview.Flex
 
 	playerTitle     *tview.TextView
+	playerAuthor    *tview.TextView
 	playerDesc      *tview.TextView
 	playerChan      chan bool
 	playing         bool
@@ -32,6 +35,10 @@ var (
 	playerWidth     int
 	playerStates    []string
 	playHistory     []lib.SearchResult
+	playingID       string
+	liveStats       string
+	liveStatsID     string
+	liveStatsLock   sync.Mutex
 
 	addRateLimit *semaphore.Weighted
 )
@@ -46,13 +53,24 @@ func SetupPlayer() {
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 
+	playerAuthor = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+
+	authorHeight := 0
+	if lib.ExpandedPlayer() {
+		authorHeight = 1
+	}
+
 	Player = tview.NewFlex().
 		AddItem(playerTitle, 1, 0, false).
+		AddItem(playerAuthor, authorHeight, 0, false).
 		AddItem(playerDesc, 1, 0, false).
 		SetDirection(tview.FlexRow)
 
 	Player.SetBackgroundColor(tcell.ColorDefault)
 	playerTitle.SetBackgroundColor(tcell.ColorDefault)
+	playerAuthor.SetBackgroundColor(tcell.ColorDefault)
 	playerDesc.SetBackgroundColor(tcell.ColorDefault)
 
 	playerChan = make(chan bool, 10)
@@ -76,7 +94,7 @@ func AddPlayer() {
 	setPlaying(true)
 
 	App.QueueUpdateDraw(func() {
-		UIFlex.AddItem(Player, 2, 0, false)
+		UIFlex.AddItem(Player, playerHeight(), 0, false)
 		resizemodal()
 	})
 }
@@ -95,7 +113,7 @@ func RemovePlayer() {
 		resizemodal()
 	})
 
-	lib.VideoCancel()
+	lib.JobCancel("video")
 	lib.GetMPV().Stop()
 	lib.GetMPV().PlaylistClear()
 }
@@ -129,9 +147,24 @@ func StartPlayer() {
 
 // startPlayer is the player update loop.
 func startPlayer(ctx context.Context, cancel context.CancelFunc) {
+	var liveID string
+	var liveUpdated time.Time
+	var prevTitle, prevState string
+
+	var titleText string
+	var titleOffset int
+	var scroll <-chan time.Time
+
 	t := time.NewTicker(1 * time.Second)
 	defer t.Stop()
 
+	if speed := lib.TitleScrollSpeed(); speed > 0 {
+		st := time.NewTicker(time.Second / time.Duration(speed))
+		defer st.Stop()
+
+		scroll = st.C
+	}
+
 	update := func() {
 		var err error
 		var width int
@@ -148,22 +181,81 @@ func startPlayer(ctx context.Context, cancel context.CancelFunc) {
 			return
 		}
 
+		lib.TrackWatchTime()
+		lib.TrackResume()
+		lib.TrackRecovery()
+
+		id := lib.GetMPV().PlayingVideoID()
+
 		playStateLock.Lock()
 		playerStates = states
+		playingID = id
 		playStateLock.Unlock()
 
+		if lib.ScreenReaderMode() {
+			state := strings.Split(progressText, ",")[0]
+
+			switch {
+			case title != prevTitle:
+				InfoMessage("Now "+state+": "+tview.Escape(title), false)
+
+			case state != prevState:
+				InfoMessage("Player "+state, false)
+			}
+
+			prevTitle, prevState = title, state
+		}
+
+		data := lib.PlayingData()
+		author := data.Get("author")
+
+		var queueText string
+		if lib.ShowQueueTime() {
+			queueText = "[grey::b]Queue: " + queueSummary(updatePlaylist()) + "[-:-:-]"
+		}
+
+		if data.Get("length") == "Live" {
+			if id != liveID || time.Since(liveUpdated) >= time.Minute {
+				liveID, liveUpdated = id, time.Now()
+				go updateLiveStats(id)
+			}
+
+			title += " " + getLiveStats(id)
+		}
+
 		App.QueueUpdateDraw(func() {
+			if title != titleText {
+				titleText, titleOffset = title, 0
+			}
+
 			playerDesc.SetText(progressText)
-			playerTitle.SetText("[::b]" + tview.Escape(title))
+			playerTitle.SetText("[::b]" + tview.Escape(scrollTitle(titleText, titleOffset, scroll != nil)))
+			playerAuthor.SetText("[purple::b]" + tview.Escape(author))
+
+			if queueText != "" {
+				setStatusIndicator("queue", queueText)
+			}
+
+			markPlaying(getListTable(), id)
 		})
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
+			playStateLock.Lock()
+			playingID = ""
+			playStateLock.Unlock()
+
+			App.QueueUpdateDraw(func() {
+				markPlaying(getListTable(), "")
+				setStatusIndicator("queue", "")
+			})
+
 			RemovePlayer()
 			playerDesc.SetText("")
 			playerTitle.SetText("")
+			playerAuthor.SetText("")
 			return
 
 		case <-playerEvent:
@@ -173,11 +265,34 @@ func startPlayer(ctx context.Context, cancel context.CancelFunc) {
 
 		case <-t.C:
 			update()
+
+		case <-scroll:
+			App.QueueUpdateDraw(func() {
+				titleOffset++
+				playerTitle.SetText("[::b]" + tview.Escape(scrollTitle(titleText, titleOffset, true)))
+			})
 		}
 
 	}
 }
 
+// scrollTitle returns the part of the title that is visible in the player,
+// starting from offset. If the title fits in the player or scrolling is
+// disabled, the entire title is returned. Wide characters, like CJK
+// characters and emoji, take up two columns.
+func scrollTitle(title string, offset int, scroll bool) string {
+	_, _, width, _ := playerTitle.GetRect()
+
+	if !scroll || width <= 0 || runewidth.StringWidth(title) <= width {
+		return title
+	}
+
+	runes := append([]rune(title), []rune("   ")...)
+	offset %= len(runes)
+
+	return runewidth.Truncate(string(append(runes[offset:], runes[:offset]...)), width, "")
+}
+
 // StopPlayer finalizes the player before exit.
 func StopPlayer(closeInstances bool) {
 	SetPlayer(false)
@@ -199,74 +314,167 @@ func SetPlayer(play bool) {
 	}
 }
 
-// PlaySelected plays the current selection.
+// PlaySelected plays the current selection. If entries are marked
+// in the list, all of them are loaded in order.
 func PlaySelected(audio, current bool, mediaInfo ...lib.SearchResult) {
 	var err error
-	var media string
-	var info lib.SearchResult
 
-	if mediaInfo != nil {
-		info = mediaInfo[0]
-	} else {
-		info, err = getListReference()
+	entries := mediaInfo
+	if entries == nil {
+		entries, err = getSelectedEntries()
 		if err != nil {
 			return
 		}
 	}
 
-	if audio {
+	playEntries(entries, audio, current, false)
+}
+
+// PlayList loads the entries in the focused list into the player.
+// If fromSelected is set, the queue is replaced with the selected
+// entry and all entries after it, otherwise all entries are appended
+// to the queue.
+func PlayList(audio, fromSelected bool) {
+	var row int
+
+	table := getListTable()
+	if table == nil {
+		return
+	}
+
+	if fromSelected {
+		row, _ = table.GetSelection()
+	}
+
+	var entries []lib.SearchResult
+
+	for ; row < table.GetRowCount(); row++ {
+		info, ok := getCellReference(table, row)
+		if !ok || info.Type == "channel" {
+			continue
+		}
+
+		entries = append(entries, info)
+	}
+	if entries == nil {
+		InfoMessage("No entries to play", false)
+		return
+	}
+
+	clearMarks(table)
+
+	playEntries(entries, audio, fromSelected, fromSelected)
+}
+
+// playEntries loads the provided entries into the player in order.
+// If replace is set, the queue is cleared before the entries are loaded.
+func playEntries(entries []lib.SearchResult, audio, current, replace bool) {
+	var media string
+
+	if audio || lib.ForceAudio() {
 		media = "audio"
 	} else {
 		media = "video"
 	}
 
-	if info.Type == "channel" {
-		ErrorMessage(fmt.Errorf("Cannot play %s for channel type", media))
-		return
-	}
+	if len(entries) == 1 {
+		info := entries[0]
+		if info.Type == "channel" {
+			ErrorMessage(fmt.Errorf("Cannot play %s for channel type", media))
+			return
+		}
 
-	InfoMessage("Loading "+media+" for "+info.Type+" "+info.Title, true)
+		InfoMessage("Loading "+media+" for "+info.Type+" "+info.Title, true)
+	} else {
+		InfoMessage("Loading "+media+" for "+strconv.Itoa(len(entries))+" entries", true)
+	}
 
 	go func() {
-		var title string
-
 		err := addRateLimit.Acquire(context.Background(), 1)
 		if err != nil {
 			return
 		}
 		defer addRateLimit.Release(1)
 
-		lib.VideoNewCtx()
+		if err := lib.MPVWait(); err != nil {
+			ErrorMessage(err)
+			return
+		}
 
-		switch info.Type {
-		case "playlist":
-			title, err = lib.LoadPlaylist(info.PlaylistID, audio)
+		lib.JobReset("video")
 
-		case "video":
-			title, err = lib.LoadVideo(info.VideoID, audio)
+		var added, prev int
 
-		default:
-			return
+		if replace {
+			addQueueClearUndo()
+			lib.GetMPV().PlaylistClear()
+
+			prev = lib.GetMPV().PlaylistCount()
 		}
-		if err != nil {
-			if err.Error() != "Rate-limit exceeded" {
+
+		for i, info := range entries {
+			if lib.IsPremiere(info) {
+				schedulePremiere(info, audio)
+				continue
+			}
+
+			if err := loadEntry(info, audio, current && i == 0); err != nil {
+				if err.Error() == "Rate-limit exceeded" {
+					return
+				}
+
 				ErrorMessage(err)
+				continue
 			}
 
-			return
-		}
+			if replace && added == 0 && prev > 0 {
+				lib.GetMPV().SetPlaylistPos(prev)
+				lib.GetMPV().PlaylistDelete(0)
+				lib.GetMPV().Play()
+			}
 
-		info.Title = title
-		go addToPlayHistory(info)
+			added++
+		}
 
-		InfoMessage("Added "+info.Title, false)
+		switch {
+		case len(entries) == 1 && added == 1:
+			InfoMessage("Added "+entries[0].Title, false)
 
-		if current && info.Type == "video" {
-			lib.GetMPV().PlaylistPlayLatest()
+		case len(entries) > 1:
+			InfoMessage("Added "+strconv.Itoa(added)+" of "+strconv.Itoa(len(entries))+" entries", false)
 		}
 	}()
 }
 
+// loadEntry loads a single video or playlist into the player.
+func loadEntry(info lib.SearchResult, audio, current bool) error {
+	var err error
+	var title string
+
+	switch info.Type {
+	case "playlist":
+		title, err = lib.LoadPlaylist(info.PlaylistID, audio)
+
+	case "video":
+		title, err = lib.LoadVideo(info.VideoID, audio)
+
+	default:
+		return fmt.Errorf("Cannot play %s %s", info.Type, info.Title)
+	}
+	if err != nil {
+		return err
+	}
+
+	info.Title = title
+	go addToPlayHistory(info)
+
+	if current && info.Type == "video" {
+		lib.GetMPV().PlaylistPlayLatest()
+	}
+
+	return nil
+}
+
 // parsePlayParams parses the url or ID and media type from the
 // command-line options.
 func parsePlayParams() {
@@ -294,12 +502,22 @@ func playInputURL(audio bool) {
 
 // playFromURL plays the media from a video/playlist URL or ID.
 func playFromURL(text string, audio bool) {
-	id, mtype, err := lib.GetVPIDFromURL(text)
+	info, err := urlMediaInfo(text)
 	if err != nil {
 		ErrorMessage(err)
 		return
 	}
 
+	PlaySelected(audio, false, info)
+}
+
+// urlMediaInfo returns the media information for a video/playlist URL or ID.
+func urlMediaInfo(text string) (lib.SearchResult, error) {
+	id, mtype, err := lib.GetVPIDFromURL(text)
+	if err != nil {
+		return lib.SearchResult{}, err
+	}
+
 	info := lib.SearchResult{
 		Title: text,
 		Type:  mtype,
@@ -311,7 +529,7 @@ func playFromURL(text string, audio bool) {
 		info.PlaylistID = id
 	}
 
-	PlaySelected(audio, false, info)
+	return info, nil
 }
 
 // isPlaying returns the currently playing status.
@@ -330,12 +548,16 @@ func setPlaying(status bool) {
 	playing = status
 }
 
-// loadPlayerState sets player volume, loop, mute and shuffle
-// settings to its last known state.
+// loadPlayerState sets player volume, speed, loop, mute and
+// shuffle settings to its last known state.
 func loadPlayerState() {
 	var states []string
 
-	state, err := lib.ConfigPath("state")
+	if lib.MPVWait() != nil {
+		return
+	}
+
+	state, err := lib.DataPath("state")
 	if err != nil {
 		return
 	}
@@ -369,12 +591,20 @@ func loadPlayerState() {
 			continue
 		}
 
+		if strings.HasPrefix(s, "speed ") {
+			if speed, err := strconv.ParseFloat(strings.Split(s, " ")[1], 64); err == nil {
+				lib.GetMPV().SetSpeed(speed)
+			}
+
+			continue
+		}
+
 		lib.GetMPV().Call("cycle", s)
 	}
 }
 
-// savePlayerState saves the player volume, loop, mute and
-// shuffle settings.
+// savePlayerState saves the player volume, speed, loop, mute
+// and shuffle settings.
 func savePlayerState() {
 	playStateLock.Lock()
 	defer playStateLock.Unlock()
@@ -383,7 +613,7 @@ func savePlayerState() {
 		return
 	}
 
-	statefile, err := lib.ConfigPath("state")
+	statefile, err := lib.DataPath("state")
 	if err != nil {
 		return
 	}
@@ -403,7 +633,7 @@ func loadPlayHistory() {
 
 	var hist []lib.SearchResult
 
-	playhistory, err := lib.ConfigPath("playhistory.json")
+	playhistory, err := lib.DataPath("playhistory.json")
 	if err != nil {
 		return
 	}
@@ -418,7 +648,103 @@ func loadPlayHistory() {
 		return
 	}
 
-	playHistory = hist
+	playHistory = dedupePlayHistory(hist)
+}
+
+// dedupePlayHistory removes repeated entries from the history, keeping
+// the most recent one. Entries were repeated if their details changed
+// between plays, for example when they were played from different lists.
+func dedupePlayHistory(hist []lib.SearchResult) []lib.SearchResult {
+	var deduped []lib.SearchResult
+
+	seen := make(map[string]struct{}, len(hist))
+	for _, info := range hist {
+		key := historyKey(info)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		deduped = append(deduped, info)
+	}
+
+	return deduped
+}
+
+// historyKey returns the key which identifies an entry in the history.
+func historyKey(info lib.SearchResult) string {
+	switch info.Type {
+	case "playlist":
+		return "playlist:" + info.PlaylistID
+
+	case "channel":
+		return "channel:" + info.AuthorID
+	}
+
+	return "video:" + info.VideoID
+}
+
+// matchHistory returns whether all words of the text
+// appear in the title or the author of the entry.
+func matchHistory(info lib.SearchResult, text string) bool {
+	content := strings.ToLower(info.Title + " " + info.Author)
+
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if !strings.Contains(content, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// purgePlayHistory removes the entries which were last played within the
+// dates, and the playbacks within the dates from the watch log. The text
+// contains a start date and an optional end date, as YYYY-MM-DD, and the
+// end date is included in the range.
+func purgePlayHistory(text string) error {
+	var kept []lib.SearchResult
+
+	dates := strings.Fields(text)
+	if len(dates) == 0 || len(dates) > 2 {
+		return fmt.Errorf("Enter a start date and an optional end date")
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", dates[0], time.Local)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid date", dates[0])
+	}
+
+	to := from
+	if len(dates) == 2 {
+		to, err = time.ParseInLocation("2006-01-02", dates[1], time.Local)
+		if err != nil {
+			return fmt.Errorf("%s is not a valid date", dates[1])
+		}
+	}
+	to = to.AddDate(0, 0, 1)
+
+	counts := lib.WatchCounts()
+
+	playHistoryLock.Lock()
+	for _, info := range playHistory {
+		played := time.Unix(counts[info.VideoID].LastPlayed, 0)
+		if info.Type == "video" && !played.Before(from) && played.Before(to) {
+			continue
+		}
+
+		kept = append(kept, info)
+	}
+
+	removed := len(playHistory) - len(kept)
+	playHistory = kept
+	playHistoryLock.Unlock()
+
+	purged := lib.PurgeWatchLog(from, to)
+
+	InfoMessage(fmt.Sprintf("Removed %d history entries and %d plays", removed, purged), false)
+
+	return nil
 }
 
 // addToPlayHistory adds a loaded media item into the history.
@@ -428,7 +754,8 @@ func addToPlayHistory(info lib.SearchResult) {
 
 	// Taken from:
 	// https://github.com/golang/go/wiki/SliceTricks#move-to-front-or-prepend-if-not-present-in-place-if-possible
-	if len(playHistory) != 0 && playHistory[0] == info {
+	if len(playHistory) != 0 && historyKey(playHistory[0]) == historyKey(info) {
+		playHistory[0] = info
 		return
 	}
 
@@ -440,7 +767,7 @@ func addToPlayHistory(info lib.SearchResult) {
 			playHistory[0] = info
 			prevInfo = phInfo
 
-		case phInfo == info:
+		case historyKey(phInfo) == historyKey(info):
 			playHistory[i] = prevInfo
 			return
 
@@ -466,6 +793,8 @@ func showPlayHistory() {
 		return
 	}
 
+	counts := lib.WatchCounts()
+
 	App.QueueUpdateDraw(func() {
 		var histTable *tview.Table
 
@@ -484,12 +813,11 @@ func showPlayHistory() {
 		})
 		histInput.SetChangedFunc(func(text string) {
 			var row int
-			text = strings.ToLower(text)
 
 			histTable.Clear()
 
 			for _, ph := range playHistory {
-				if text != "" && strings.Index(strings.ToLower(ph.Title), text) < 0 {
+				if !matchHistory(ph, text) {
 					continue
 				}
 
@@ -515,6 +843,19 @@ func showPlayHistory() {
 					SetSelectedStyle(auxStyle),
 				)
 
+				var plays string
+				if count := counts[ph.VideoID]; ph.Type == "video" && count.Plays > 1 {
+					plays = "[pink]" + strconv.Itoa(count.Plays) + " plays"
+				}
+
+				histTable.SetCell(row, 5, tview.NewTableCell("").
+					SetSelectable(false),
+				)
+
+				histTable.SetCell(row, 6, tview.NewTableCell(plays).
+					SetSelectedStyle(auxStyle),
+				)
+
 				row++
 			}
 
@@ -546,6 +887,30 @@ func showPlayHistory() {
 			case '/':
 				App.SetFocus(histInput)
 
+			case 'X':
+				SetInput("Purge history (YYYY-MM-DD [YYYY-MM-DD]):", 0, nil, func(e *tcell.EventKey) *tcell.EventKey {
+					switch e.Key() {
+					case tcell.KeyEnter:
+						if err := purgePlayHistory(InputBox.GetText()); err != nil {
+							ErrorMessage(err)
+							return nil
+						}
+
+						counts = lib.WatchCounts()
+						histInput.SetText(histInput.GetText())
+
+						fallthrough
+
+					case tcell.KeyEscape:
+						App.SetFocus(histTable)
+						Status.SwitchToPage("messages")
+					}
+
+					return e
+				})
+
+				return nil
+
 			case 'i':
 				exit = true
 				ViewPlaylist(true, event.Modifiers() == tcell.ModAlt)
@@ -598,7 +963,7 @@ func savePlayHistory() {
 	playHistoryLock.Lock()
 	defer playHistoryLock.Unlock()
 
-	phfile, err := lib.ConfigPath("playhistory.json")
+	phfile, err := lib.DataPath("playhistory.json")
 	if err != nil {
 		return
 	}
@@ -615,16 +980,18 @@ func savePlayHistory() {
 }
 
 // monitorErrors monitors for errors related to loading media
-// from MPV.
+// from MPV, and for videos which are resumed from a saved position.
 func monitorErrors() {
 	for {
 		select {
-		case msg, ok := <-lib.MPVErrors:
+		case perr, ok := <-lib.MPVErrors:
 			if !ok {
 				return
 			}
 
-			ErrorMessage(fmt.Errorf("Unable to play %s", msg))
+			App.QueueUpdateDraw(func() {
+				showPlaybackError(perr)
+			})
 
 		case _, ok := <-lib.MPVFileLoaded:
 			if !ok {
@@ -632,6 +999,15 @@ func monitorErrors() {
 			}
 
 			AddPlayer()
+
+		case position, ok := <-lib.MPVResumed:
+			if !ok {
+				return
+			}
+
+			App.QueueUpdateDraw(func() {
+				askStartOver(position)
+			})
 		}
 	}
 }
@@ -643,21 +1019,80 @@ func capturePlayerEvent(event *tcell.EventKey) {
 
 	switch event.Key() {
 	case tcell.KeyCtrlO:
-		ShowFileBrowser("Open playlist:", plOpenReplace, plFbExit)
+		ShowFileBrowser("playlist", "Open playlist:", plOpenReplace, plFbExit)
 
 	case tcell.KeyCtrlH:
-		go showPlayHistory()
+		if !lockedFeature("History") {
+			go showPlayHistory()
+		}
+
+	case tcell.KeyCtrlV:
+		showQualityPrompt()
+
+	case tcell.KeyCtrlY:
+		go copyShareLink()
+
+	case tcell.KeyCtrlK:
+		showEqualizer()
+
+	case tcell.KeyEnter:
+		if isAudioPlayKey(event) {
+			playSelected('a')
+		}
 	}
 
 	switch event.Rune() {
 	case 'a', 'A', 'v', 'V':
+		if event.Modifiers() == tcell.ModAlt {
+			r := event.Rune()
+			PlayList(r == 'a' || r == 'A', r == 'A' || r == 'V')
+			break
+		}
+
 		playSelected(event.Rune())
 
+	case 't':
+		toggleMark()
+
+	case 'T':
+		clearMarks(getListTable())
+
+	case 'W':
+		markWatched()
+
+	case 'w':
+		addWatchLater()
+
+	case 'P':
+		jumpToPlaying()
+
+	case 'L':
+		go ShowLyrics()
+
+	case 'K':
+		go ShowCastDevices()
+
+	case 'D':
+		go showAudioDevices()
+
+	case 'J':
+		toggleLiveChat()
+
+	case '(', ')':
+		step := liveChatWidthStep
+		if event.Rune() == '(' {
+			step = -step
+		}
+
+		resizeLiveChat(step)
+
 	case 'p':
 		playlistPopup()
 
 	case 'Y':
-		ShowDownloadView()
+		if !lockedFeature("Downloads") {
+			ShowDownloadView()
+		}
 	}
 }
 
@@ -668,6 +1103,12 @@ func capturePlayerEvent(event *tcell.EventKey) {
 func captureSendPlayerEvent(event *tcell.EventKey) {
 	var nokey, norune bool
 
+	event = remapKey("player", event)
+
+	if captureCastEvent(event) {
+		return
+	}
+
 	switch event.Key() {
 	case tcell.KeyRight:
 		lib.GetMPV().SeekForward()
@@ -681,6 +1122,7 @@ func captureSendPlayerEvent(event *tcell.EventKey) {
 
 	switch event.Rune() {
 	case 'S':
+		addQueueClearUndo()
 		SetPlayer(false)
 		sendPlaylistExit()
 
@@ -699,6 +1141,21 @@ func captureSendPlayerEvent(event *tcell.EventKey) {
 	case '-':
 		lib.GetMPV().VolumeDecrease()
 
+	case '[':
+		lib.GetMPV().AdjustSpeed(-0.1)
+
+	case ']':
+		lib.GetMPV().AdjustSpeed(0.1)
+
+	case '{':
+		lib.GetMPV().CycleSpeed()
+
+	case ',', '.':
+		go switchChapter(event.Rune() == ',')
+
+	case '#':
+		go showChapters()
+
 	case '<':
 		lib.GetMPV().Prev()
 
@@ -709,11 +1166,24 @@ func captureSendPlayerEvent(event *tcell.EventKey) {
 		lib.GetMPV().CyclePaused()
 
 	case 'y':
-		go ShowDownloadOptions()
+		if !lockedFeature("Downloads") {
+			go ShowDownloadOptions()
+		}
 
 	case 'o':
 		go ViewInstances()
 
+	case 'O':
+		if event.Modifiers()&tcell.ModAlt != 0 {
+			go togglePlayingMedia()
+			break
+		}
+
+		toggleForceAudio()
+
+	case 'Q':
+		go showPlayingQuality()
+
 	case 'b', 'B':
 		playInputURL(event.Rune() == 'b')
 
@@ -751,6 +1221,172 @@ func playSelected(r rune) {
 	}
 }
 
+// playingMarker stores the row of a list which is marked as playing,
+// along with the number of rows and the first entry of the list, so
+// that the list is searched again only if it was changed.
+type playingMarker struct {
+	id    string
+	first string
+	row   int
+	count int
+}
+
+// playingMarks stores the row which is marked as playing in each
+// list. It is only accessed from the UI goroutine.
+var playingMarks = make(map[*tview.Table]playingMarker)
+
+// playingMarksMax is the number of lists for which the marked row is
+// stored, after which the stored rows are discarded, since popups
+// create a new table every time they are shown.
+const playingMarksMax = 16
+
+// markPlaying marks the entry in the list which corresponds to the
+// currently playing video. The list is only searched if the playing
+// video or the list has changed, and only the previously marked row
+// and the new row are modified. It must be called from the UI goroutine.
+func markPlaying(table *tview.Table, id string) {
+	if table == nil {
+		return
+	}
+
+	mark := playingMarker{id: id, row: -1, count: table.GetRowCount()}
+	if info, ok := getCellReference(table, 0); ok {
+		mark.first = info.VideoID + info.PlaylistID + info.AuthorID
+	}
+
+	prev, marked := playingMarks[table]
+	if marked && prev.id == id && prev.count == mark.count && prev.first == mark.first {
+		if prev.row < 0 {
+			return
+		}
+
+		if info, ok := getCellReference(table, prev.row); ok && info.VideoID == id {
+			return
+		}
+	}
+
+	if marked && prev.row >= 0 && prev.row < mark.count {
+		setPlayingText(table, prev.row, false)
+	}
+
+	if id != "" {
+		mark.row = findVideoRow(table, id)
+		if mark.row >= 0 {
+			setPlayingText(table, mark.row, true)
+		}
+	}
+
+	if _, ok := playingMarks[table]; !ok && len(playingMarks) >= playingMarksMax {
+		playingMarks = make(map[*tview.Table]playingMarker)
+	}
+
+	playingMarks[table] = mark
+}
+
+// setPlayingText adds or removes the playing marker of the row.
+func setPlayingText(table *tview.Table, row int, playing bool) {
+	cell := table.GetCell(row, 0)
+	if cell == nil {
+		return
+	}
+
+	text := strings.Replace(cell.Text, playingText, "", 1)
+
+	if playing {
+		if strings.HasPrefix(text, markText) {
+			text = markText + playingText + strings.TrimPrefix(text, markText)
+		} else {
+			text = playingText + text
+		}
+	}
+
+	if text != cell.Text {
+		cell.SetText(text)
+	}
+}
+
+// findVideoRow returns the row of the list which contains the video with
+// the given ID, or -1 if it is not found. The entries of lists whose
+// cells are created when shown are searched without creating their cells.
+func findVideoRow(table *tview.Table, id string) int {
+	if content := tableListContent(table); content != nil {
+		return content.Find(id)
+	}
+
+	for row := 0; row < table.GetRowCount(); row++ {
+		if info, ok := getCellReference(table, row); ok && info.Type == "video" && info.VideoID == id {
+			return row
+		}
+	}
+
+	return -1
+}
+
+// jumpToPlaying selects the entry in the list which corresponds
+// to the currently playing video.
+func jumpToPlaying() {
+	playStateLock.Lock()
+	id := playingID
+	playStateLock.Unlock()
+
+	table := getListTable()
+	if table == nil || id == "" {
+		InfoMessage("Nothing is playing", false)
+		return
+	}
+
+	if row := findVideoRow(table, id); row >= 0 {
+		table.Select(row, 0)
+		return
+	}
+
+	InfoMessage("Playing video is not in this list", false)
+}
+
+// updateLiveStats fetches the viewer count and
+// uptime of the live stream with the given ID.
+func updateLiveStats(id string) {
+	video, err := lib.GetClient().LiveStats(id)
+	if err != nil || !video.LiveNow {
+		return
+	}
+
+	stats := "(" + lib.FormatNumber(int(video.ViewCount)) + " watching"
+	if video.Published > 0 {
+		stats += ", live for " + lib.FormatDuration(time.Now().Unix()-video.Published)
+	}
+	stats += ")"
+
+	liveStatsLock.Lock()
+	liveStats, liveStatsID = stats, id
+	liveStatsLock.Unlock()
+}
+
+// getLiveStats returns the stats of the live stream with the given ID.
+func getLiveStats(id string) string {
+	liveStatsLock.Lock()
+	defer liveStatsLock.Unlock()
+
+	if id != liveStatsID {
+		return ""
+	}
+
+	return liveStats
+}
+
+// playerHeight returns the height of the player.
+func playerHeight() int {
+	if compactLayout {
+		return 1
+	}
+
+	if lib.ExpandedPlayer() {
+		return 3
+	}
+
+	return 2
+}
+
 func resizePlayer(width int) {
 	if width == playerWidth {
 		return
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showPlaybackError shows a popup with the error for a playlist entry
+// which could not be played, from which the entry can be retried or
+// removed from the queue. If another popup is open, the error is shown
+// in the status bar instead.
+func showPlaybackError(perr lib.PlaybackError) {
+	if pg, _ := MPage.GetFrontPage(); pg != "ui" && pg != "playbackerror" {
+		ErrorMessage(playbackError(perr))
+		return
+	}
+
+	addToErrorLog(playbackError(perr).Error())
+
+	errorText := "[::u]Title[-:-:-]\n[::b]" + tview.Escape(perr.Title) +
+		"\n\n[::u]Error[-:-:-]\n[red::b]" + tview.Escape(perr.Error) + "[-:-:-]" +
+		"\n\n[::b]r[-:-:-] Retry, [::b]f[-:-:-] Retry with different format, " +
+		"[::b]d[-:-:-] Remove from queue, [::b]Esc[-:-:-] Dismiss"
+
+	errorTitle := tview.NewTextView()
+	errorTitle.SetDynamicColors(true)
+	errorTitle.SetTextAlign(tview.AlignCenter)
+	errorTitle.SetText("[white::bu]Playback error")
+	errorTitle.SetBackgroundColor(tcell.ColorDefault)
+
+	errorPopup := tview.NewTextView()
+	errorPopup.SetWrap(true)
+	errorPopup.SetText(errorText)
+	errorPopup.SetDynamicColors(true)
+	errorPopup.SetBackgroundColor(tcell.ColorDefault)
+	errorPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		captureSendPlayerEvent(event)
+
+		switch event.Key() {
+		case tcell.KeyEnter, tcell.KeyEscape:
+			exitPlaybackError()
+		}
+
+		switch event.Rune() {
+		case 'r', 'f':
+			go requeueEntry(perr, event.Rune() == 'f')
+			exitPlaybackError()
+
+		case 'd':
+			go removeEntry(perr)
+			exitPlaybackError()
+		}
+
+		return event
+	})
+
+	errorFlex := tview.NewFlex().
+		AddItem(errorTitle, 1, 0, false).
+		AddItem(errorPopup, 10, 10, false).
+		SetDirection(tview.FlexRow)
+
+	MPage.AddAndSwitchToPage(
+		"playbackerror",
+		statusmodal(errorFlex, errorPopup),
+		true,
+	).ShowPage("ui")
+
+	App.SetFocus(errorPopup)
+}
+
+// requeueEntry resolves the failed entry again, and replaces it in the queue.
+// If otherFormat is set, the entry is loaded with the next-best format.
+func requeueEntry(perr lib.PlaybackError, otherFormat bool) {
+	InfoMessage("Retrying "+perr.Title, true)
+
+	if err := lib.RetryEntry(perr.ID, otherFormat); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Requeued "+perr.Title, false)
+}
+
+// removeEntry removes the failed entry from the queue.
+func removeEntry(perr lib.PlaybackError) {
+	if err := lib.RemoveEntry(perr.ID); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Removed "+perr.Title+" from the queue", false)
+}
+
+// playbackError returns the error message for a failed entry.
+func playbackError(perr lib.PlaybackError) error {
+	return fmt.Errorf("Unable to play %s: %s", perr.Title, perr.Error)
+}
+
+// exitPlaybackError closes the playback error popup.
+func exitPlaybackError() {
+	exitFocus()
+	popupStatus(false)
+	MPage.RemovePage("playbackerror")
+}
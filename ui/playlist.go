@@ -2,7 +2,6 @@ package ui
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +11,7 @@ import (
 	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -31,9 +31,11 @@ var (
 	// Playlist shows the playlist popup
 	Playlist   *tview.Flex
 	plistPopup *tview.Table
+	plistTitle *tview.TextView
 
 	plViewFlex   *tview.Flex
 	plistTable   *tview.Table
+	plistContent *listContent
 	plTableTitle *tview.TextView
 	plTableDesc  *tview.TextView
 	plTableVBox  *tview.Box
@@ -66,7 +68,10 @@ func SetupPlaylist() {
 
 // setupViewPlaylist sets up the playlist view page.
 func setupViewPlaylist() {
+	plistContent = newListContent()
+
 	plistTable = tview.NewTable()
+	plistTable.SetContent(plistContent)
 	plistTable.SetSelectorWrap(true)
 	plistTable.SetBackgroundColor(tcell.ColorDefault)
 
@@ -85,12 +90,14 @@ func setupViewPlaylist() {
 	plViewFlex = tview.NewFlex().
 		SetDirection(tview.FlexRow)
 
-	plistTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	plistTable.SetInputCapture(contextCapture("playlist", func(event *tcell.EventKey) *tcell.EventKey {
 		capturePlayerEvent(event)
 
 		switch event.Key() {
 		case tcell.KeyEnter:
-			loadMorePlistResults()
+			if !isAudioPlayKey(event) {
+				loadMorePlistResults()
+			}
 
 		case tcell.KeyEscape:
 			VPage.SwitchToPage(plPrevPage)
@@ -113,12 +120,12 @@ func setupViewPlaylist() {
 		}
 
 		return event
-	})
+	}))
 }
 
 // setupPlaylistPopup sets up the playlist popup.
 func setupPlaylistPopup() {
-	plistTitle := tview.NewTextView()
+	plistTitle = tview.NewTextView()
 	plistTitle.SetDynamicColors(true)
 	plistTitle.SetTextColor(tcell.ColorBlue)
 	plistTitle.SetText("[white::bu]Queue")
@@ -134,7 +141,7 @@ func setupPlaylistPopup() {
 		AddItem(plistPopup, 10, 10, false).
 		SetDirection(tview.FlexRow)
 
-	plistPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	plistPopup.SetInputCapture(contextCapture("playlist", func(event *tcell.EventKey) *tcell.EventKey {
 		captureSendPlayerEvent(event)
 
 		switch event.Key() {
@@ -149,28 +156,45 @@ func setupPlaylistPopup() {
 
 		case tcell.KeyCtrlS:
 			plExit()
-			ShowFileBrowser("Save as:", plSaveAs, plFbExit)
+			ShowFileBrowser("playlist", "Save as:", plSaveAs, plFbExit)
 
 		case tcell.KeyCtrlA:
 			plExit()
-			ShowFileBrowser("Append from:", plOpenAppend, plFbExit)
+			ShowFileBrowser("playlist", "Append from:", plOpenAppend, plFbExit)
 		}
 
 		switch event.Rune() {
 		case 'd':
-			plDelete()
+			deleteQueueEntries()
 			resizemodal()
 
+		case 't':
+			toggleQueueMark()
+
+		case 'T':
+			clearQueueMarks()
+
+		case 'U', 'D':
+			moveQueueEntriesTo(event.Rune() == 'U')
+
 		case 'M':
 			plMove()
 			resizemodal()
 
 		case 'S':
 			plExit()
+
+		case '/':
+			filterQueue()
+			return nil
+
+		case '+':
+			addQueueToPlaylist()
+			return nil
 		}
 
 		return event
-	})
+	}))
 
 	plistPopup.SetSelectionChangedFunc(func(row, col int) {
 		selector := ">"
@@ -220,7 +244,7 @@ func playlistPopup() {
 //
 //gocyclo:ignore
 func startPlaylist() {
-	var playlistData []map[string]interface{}
+	var playlistData []lib.PlaylistEntry
 
 	exitPlaylist := func() {
 		App.QueueUpdateDraw(func() {
@@ -229,7 +253,7 @@ func startPlaylist() {
 		})
 	}
 
-	update := func(plEventData []map[string]interface{}) {
+	update := func(plEventData []lib.PlaylistEntry) {
 		if len(plEventData) == 0 && plistPopup.HasFocus() {
 			exitPlaylist()
 			return
@@ -238,19 +262,39 @@ func startPlaylist() {
 		plistPopup.Clear()
 		playlistData = plEventData
 
-		if !plistPopup.HasFocus() {
+		if !plistPopup.HasFocus() && !queueFiltering {
 			return
 		}
 
+		var list []PlaylistData
+		for _, entry := range plEventData {
+			list = append(list, getPlaylistData(entry))
+		}
+
+		title := "[white::bu]Queue[-:-:-] [grey::b](" + queueSummary(list) + ")"
+
 		App.QueueUpdateDraw(func() {
+			if marked := markedQueuePositions(plEventData); marked != nil {
+				title += " [red::b](" + strconv.Itoa(len(marked)) + " marked)"
+			}
+
 			_, _, w, _ := plistPopup.GetRect()
 			pos, _ := plistPopup.GetSelection()
 			plistPopup.SetSelectable(false, false)
 
-			for i, pldata := range plEventData {
+			queueRows = queueRows[:0]
+
+			for i, entry := range plEventData {
 				var marker string
 
-				data := getPlaylistData(i, pldata)
+				data := getPlaylistData(entry)
+				if queueFilter != "" && (data == (PlaylistData{}) || !queueMatch(data.Title, data.Author)) {
+					continue
+				}
+
+				row := len(queueRows)
+				queueRows = append(queueRows, i)
+
 				if data == (PlaylistData{}) {
 					continue
 				}
@@ -259,6 +303,11 @@ func startPlaylist() {
 					marker = " [white::b](playing)"
 				}
 
+				color := "[blue::b]"
+				if isQueueMarked(entry.ID) {
+					color = markText + color
+				}
+
 				info := lib.SearchResult{
 					Title:   data.Title,
 					Type:    "video",
@@ -266,7 +315,7 @@ func startPlaylist() {
 					VideoID: data.VideoID,
 				}
 
-				plistPopup.SetCell(i, 1, tview.NewTableCell("[blue::b]"+tview.Escape(data.Title)+marker).
+				plistPopup.SetCell(row, 1, tview.NewTableCell(color+tview.Escape(data.Title)+marker).
 					SetExpansion(1).
 					SetMaxWidth(w/7).
 					SetReference(info).
@@ -274,36 +323,46 @@ func startPlaylist() {
 					SetSelectedStyle(auxStyle),
 				)
 
-				plistPopup.SetCell(i, 2, tview.NewTableCell(" ").
+				plistPopup.SetCell(row, 2, tview.NewTableCell(" ").
 					SetSelectable(false),
 				)
 
-				plistPopup.SetCell(i, 3, tview.NewTableCell("[purple::b]"+tview.Escape(data.Author)).
+				plistPopup.SetCell(row, 3, tview.NewTableCell("[purple::b]"+tview.Escape(data.Author)).
 					SetMaxWidth(w/5).
 					SetSelectable(true).
 					SetSelectedStyle(auxStyle),
 				)
 
-				plistPopup.SetCell(i, 4, tview.NewTableCell(" ").
+				plistPopup.SetCell(row, 4, tview.NewTableCell(" ").
 					SetSelectable(false),
 				)
 
-				plistPopup.SetCell(i, 5, tview.NewTableCell("[pink::b]"+tview.Escape(data.Type)).
+				plistPopup.SetCell(row, 5, tview.NewTableCell("[pink::b]"+tview.Escape(data.Type)).
 					SetMaxWidth(w/5).
 					SetSelectable(true).
 					SetSelectedStyle(auxStyle),
 				)
 
-				plistPopup.SetCell(i, 6, tview.NewTableCell(" ").
+				plistPopup.SetCell(row, 6, tview.NewTableCell(" ").
 					SetSelectable(false),
 				)
 
-				plistPopup.SetCell(i, 7, tview.NewTableCell("[pink::b]"+data.Duration).
+				duration := "[pink::b]" + data.Duration
+				if data.Duration == "Live" {
+					duration = "[red::b]LIVE"
+				}
+
+				plistPopup.SetCell(row, 7, tview.NewTableCell(duration).
 					SetSelectable(true).
 					SetSelectedStyle(auxStyle),
 				)
 			}
 
+			if queueFilter != "" {
+				title += " [yellow::b](" + strconv.Itoa(len(queueRows)) + " matching '" + tview.Escape(queueFilter) + "')"
+			}
+			plistTitle.SetText(title)
+
 			plistPopup.SetSelectable(true, false)
 
 			plistPopup.Select(pos, 0)
@@ -326,6 +385,50 @@ func startPlaylist() {
 	}
 }
 
+// queueSummary returns the number of entries in the queue,
+// and the total remaining duration of the unplayed entries.
+func queueSummary(list []PlaylistData) string {
+	var live bool
+	var remaining int64
+
+	playing := -1
+	for i, data := range list {
+		if data.Playing {
+			playing = i
+			break
+		}
+	}
+
+	for i, data := range list {
+		if i < playing {
+			continue
+		}
+
+		if i == playing {
+			if duration := lib.GetMPV().Duration(); duration > 0 {
+				remaining += duration - lib.GetMPV().TimePosition()
+			}
+
+			continue
+		}
+
+		duration, err := lib.ParseDuration(data.Duration)
+		if err != nil {
+			live = live || data.Duration == "Live"
+			continue
+		}
+
+		remaining += duration
+	}
+
+	summary := strconv.Itoa(len(list)) + " entries, " + lib.FormatDuration(remaining) + " remaining"
+	if live {
+		summary += " + live"
+	}
+
+	return summary
+}
+
 // loadMorePlistResults appends more playlist results to the playlist
 // view table.
 func loadMorePlistResults() {
@@ -370,124 +473,54 @@ func ViewPlaylist(newlist, noload bool) {
 }
 
 // viewPlaylist loads the playlist URL and shows the playlist contents.
+// Entries are added to the list as they are decoded.
 func viewPlaylist(info lib.SearchResult, newlist bool) {
-	var err error
-	var cancel bool
+	var shown bool
+	var added int
+
+	pos := -1
 
 	InfoMessage("Loading playlist entries", true)
 	defer InfoMessage("Loaded playlist entries", false)
 
-	result, err := lib.GetClient().Playlist(info.PlaylistID, plPrevPage == "dashboard")
-	if err != nil {
-		cancel = true
-	}
-
-	App.QueueUpdateDraw(func() {
-		if cancel {
-			ResultsList.SetSelectable(true, false)
-			return
+	show := func(result lib.PlaylistResult) {
+		if !shown && newlist {
+			showPlaylistView(result)
 		}
 
-		var skipped int
-
-		pos := -1
-
-		_, _, width, _ := ResultsList.GetRect()
-
-		if newlist {
-			plViewFlex.Clear()
-			plistTable.Clear()
-			plistIdMap = make(map[string]struct{})
-
-			desc := strings.ReplaceAll(result.Description, "\n", " ")
-			desclen := len(desc)
-
-			header := tview.NewTextView()
-			header.SetRegions(true)
-			header.SetDynamicColors(true)
-			header.SetBackgroundColor(tcell.ColorDefault)
-			header.SetText(
-				`[::b]Playlist[-:-:-] ["video"][darkcyan]Videos[""]`,
-			)
-			header.Highlight("video")
-
-			plViewFlex.AddItem(header, 1, 0, false)
-			plViewFlex.AddItem(plTableTitle, 1, 0, false)
-
-			if desclen > 0 {
-				s := 2
-				if desclen >= width {
-					s++
-				} else {
-					s--
-				}
-
-				plViewFlex.AddItem(plTableVBox, 1, 0, false)
-				plViewFlex.AddItem(plTableDesc, s, 0, false)
-				plViewFlex.AddItem(plTableVBox, 1, 0, false)
-			}
-
-			plViewFlex.AddItem(plistTable, 0, 10, true)
-
-			plTableDesc.SetText(desc)
-			plTableTitle.SetText("[::bu]" + result.Title)
-
-			VPage.AddAndSwitchToPage("playlistview", plViewFlex, true)
-		}
-
-		rows := plistTable.GetRowCount()
+		shown = true
 		plistTable.SetSelectable(false, false)
+	}
 
-		for i, v := range result.Videos {
-			select {
-			case <-lib.PlaylistCtx().Done():
-				return
-
-			default:
-			}
-
-			if pos < 0 {
-				pos = (rows + i) - skipped
-			}
+	result, err := lib.GetClient().PlaylistStream(
+		info.PlaylistID, plPrevPage == "dashboard",
+		func(result lib.PlaylistResult, videos []lib.PlaylistVideo) {
+			App.QueueUpdateDraw(func() {
+				show(result)
 
-			if v.LengthSeconds == 0 {
-				skipped++
-				continue
-			}
+				row, count := addPlaylistEntries(info, result, videos)
+				if pos < 0 && count > 0 {
+					pos = row
+				}
 
-			_, ok := plistIdMap[v.VideoID]
-			if ok {
-				skipped++
-				continue
-			}
+				added += count
+			})
+		},
+	)
 
-			sref := lib.SearchResult{
-				Type:       "video",
-				Title:      v.Title,
-				VideoID:    v.VideoID,
-				AuthorID:   v.AuthorID,
-				IndexID:    v.IndexID,
-				PlaylistID: info.PlaylistID,
-				Author:     result.Author,
+	App.QueueUpdateDraw(func() {
+		if err != nil {
+			if shown {
+				plistTable.SetSelectable(true, false)
 			}
 
-			plistTable.SetCell((rows+i)-skipped, 0, tview.NewTableCell("[blue::b]"+tview.Escape(v.Title)).
-				SetExpansion(1).
-				SetReference(sref).
-				SetMaxWidth((width / 4)).
-				SetSelectedStyle(mainStyle),
-			)
-
-			plistTable.SetCell((rows+i)-skipped, 1, tview.NewTableCell("[pink]"+lib.FormatDuration(v.LengthSeconds)).
-				SetSelectable(true).
-				SetAlign(tview.AlignRight).
-				SetSelectedStyle(auxStyle),
-			)
-
-			plistIdMap[v.VideoID] = struct{}{}
+			ResultsList.SetSelectable(true, false)
+			return
 		}
 
-		if skipped == len(result.Videos) {
+		show(result)
+
+		if added == 0 {
 			InfoMessage("No more results", false)
 			plistTable.SetSelectable(true, false)
 			return
@@ -515,24 +548,105 @@ func viewPlaylist(info lib.SearchResult, newlist bool) {
 	})
 }
 
-// getPlaylistData returns playlist data.
-func getPlaylistData(row int, pldata map[string]interface{}) PlaylistData {
-	var id int
-	var data PlaylistData
-	var filename string
-	var playing bool
+// showPlaylistView clears the playlist view, and shows the playlist's
+// title and description.
+func showPlaylistView(result lib.PlaylistResult) {
+	_, _, width, _ := ResultsList.GetRect()
 
-	if i, ok := pldata["id"].(float64); ok {
-		id = int(i)
-	}
-	if f, ok := pldata["filename"].(string); ok {
-		filename = f
+	plViewFlex.Clear()
+	plistTable.Clear()
+	plistIdMap = make(map[string]struct{})
+
+	desc := strings.ReplaceAll(result.Description, "\n", " ")
+	desclen := runewidth.StringWidth(desc)
+
+	header := tview.NewTextView()
+	header.SetRegions(true)
+	header.SetDynamicColors(true)
+	header.SetBackgroundColor(tcell.ColorDefault)
+	header.SetText(
+		`[::b]Playlist[-:-:-] ["video"][darkcyan]Videos[""]`,
+	)
+	header.Highlight("video")
+
+	plViewFlex.AddItem(header, 1, 0, false)
+	plViewFlex.AddItem(plTableTitle, 1, 0, false)
+
+	plDescHeight = 0
+
+	if desclen > 0 {
+		s := 2
+		if desclen >= width {
+			s++
+		} else {
+			s--
+		}
+
+		plDescHeight = s
+
+		plViewFlex.AddItem(plTableVBox, 1, 0, false)
+		plViewFlex.AddItem(plTableDesc, s, 0, false)
+		plViewFlex.AddItem(plTableVBox, 1, 0, false)
+		resizeDescription(plViewFlex, plTableDesc, plTableVBox, s)
 	}
-	if p, ok := pldata["current"].(bool); ok {
-		playing = p
+
+	plViewFlex.AddItem(plistTable, 0, 10, true)
+
+	plTableDesc.SetText(desc)
+	plTableTitle.SetText("[::bu]" + result.Title)
+
+	VPage.AddAndSwitchToPage("playlistview", plViewFlex, true)
+}
+
+// addPlaylistEntries appends the playlist's videos to the playlist view,
+// skipping unavailable and duplicate videos. It returns the row of the
+// first added entry, and the number of added entries.
+func addPlaylistEntries(info lib.SearchResult, result lib.PlaylistResult, videos []lib.PlaylistVideo) (int, int) {
+	var added int
+
+	rows := plistTable.GetRowCount()
+
+	for _, v := range videos {
+		select {
+		case <-lib.PlaylistCtx().Done():
+			return rows, added
+
+		default:
+		}
+
+		if v.LengthSeconds == 0 {
+			continue
+		}
+
+		if _, ok := plistIdMap[v.VideoID]; ok {
+			continue
+		}
+
+		sref := lib.SearchResult{
+			Type:          "video",
+			Title:         v.Title,
+			VideoID:       v.VideoID,
+			AuthorID:      v.AuthorID,
+			IndexID:       v.IndexID,
+			PlaylistID:    info.PlaylistID,
+			Author:        result.Author,
+			LengthSeconds: v.LengthSeconds,
+		}
+
+		plistContent.Append(sref)
+
+		plistIdMap[v.VideoID] = struct{}{}
+		added++
 	}
 
-	urlData := lib.GetDataFromURL(filename)
+	return rows, added
+}
+
+// getPlaylistData returns playlist data from a playlist entry.
+func getPlaylistData(entry lib.PlaylistEntry) PlaylistData {
+	var data PlaylistData
+
+	urlData := lib.GetDataFromURL(entry.Filename)
 	if urlData == nil {
 		return (PlaylistData{})
 	}
@@ -549,7 +663,12 @@ func getPlaylistData(row int, pldata map[string]interface{}) PlaylistData {
 		}
 
 		if udata == "title" && urlData.Get(udata) == "" {
-			urlData.Set(udata, lib.GetMPV().PlaylistTitle(row))
+			title := entry.Title
+			if title == "" {
+				title = entry.Filename
+			}
+
+			urlData.Set(udata, title)
 			continue
 		}
 
@@ -558,9 +677,9 @@ func getPlaylistData(row int, pldata map[string]interface{}) PlaylistData {
 		}
 	}
 
-	data.ID = id
-	data.Playing = playing
-	data.Filename = filename
+	data.ID = entry.ID
+	data.Playing = entry.Current
+	data.Filename = entry.Filename
 	data.VideoID = urlData.Get("id")
 	data.Title = urlData.Get("title")
 	data.Author = urlData.Get("author")
@@ -572,29 +691,15 @@ func getPlaylistData(row int, pldata map[string]interface{}) PlaylistData {
 
 // updatePlaylist returns updated playlist data from mpv.
 func updatePlaylist() []PlaylistData {
-	var data []PlaylistData
-
-	liststr := lib.GetMPV().PlaylistData()
-	if liststr == "" {
-		ErrorMessage(fmt.Errorf("Could not fetch playlist"))
-		return []PlaylistData{}
-	}
-
-	err := json.Unmarshal([]byte(liststr), &data)
+	entries, err := lib.GetMPV().PlaylistEntries()
 	if err != nil {
-		ErrorMessage(fmt.Errorf("Error while parsing playlist data"))
-		return []PlaylistData{}
-	}
-	if len(data) == 0 {
+		ErrorMessage(err)
 		return []PlaylistData{}
 	}
 
-	for i := range data {
-		data[i] = getPlaylistData(i, map[string]interface{}{
-			"id":       data[i].ID,
-			"playing":  data[i].Playing,
-			"filename": data[i].Filename,
-		})
+	data := make([]PlaylistData, len(entries))
+	for i, entry := range entries {
+		data[i] = getPlaylistData(entry)
 	}
 
 	return data
@@ -628,7 +733,7 @@ func createPlaylistForm() {
 
 			InfoMessage("Creating playlist "+title, true)
 
-			if err := lib.GetClient().CreatePlaylist(title, privacy); err != nil {
+			if _, err := lib.GetClient().CreatePlaylist(title, privacy); err != nil {
 				ErrorMessage(err)
 				return
 			}
@@ -757,6 +862,11 @@ func modifyPlaylist(info lib.SearchResult, add bool) {
 	if !add && pg == "dashboard" {
 		InfoMessage("Removing playlist "+info.Title, true)
 
+		if err := addPlaylistRemoveUndo(info); err != nil {
+			ErrorMessage(err)
+			return
+		}
+
 		if err := lib.GetClient().RemovePlaylist(info.PlaylistID); err != nil {
 			ErrorMessage(err)
 			return
@@ -772,28 +882,32 @@ func modifyPlaylist(info lib.SearchResult, add bool) {
 	}
 }
 
-// modifyPlaylistVideo modifies the availability of the video in a playlist.
-func modifyPlaylistVideo(info lib.SearchResult, add bool) {
+// modifyPlaylistVideo modifies the availability of the videos in a playlist.
+func modifyPlaylistVideo(videos []lib.SearchResult, add bool) {
 	if !lib.IsAuthInstance() {
 		InfoMessage("Cannot add video to playlist", false)
 		return
 	}
 
 	if !add {
-		InfoMessage("Removing video from "+info.Title, true)
-
-		if err := lib.GetClient().RemovePlaylistVideo(info.PlaylistID, info.IndexID); err != nil {
-			ErrorMessage(err)
-			return
-		}
+		for _, info := range videos {
+			InfoMessage("Removing video from "+info.Title, true)
 
-		App.QueueUpdateDraw(func() {
-			if err := modifyListReference("", false, info); err != nil {
+			if err := lib.GetClient().RemovePlaylistVideo(info.PlaylistID, info.IndexID); err != nil {
 				ErrorMessage(err)
+				return
 			}
-		})
 
-		InfoMessage("Removed video from "+info.Title, false)
+			addPlaylistVideoUndo(info)
+
+			App.QueueUpdateDraw(func() {
+				if err := modifyListReference("", false, info); err != nil {
+					ErrorMessage(err)
+				}
+			})
+
+			InfoMessage("Removed video from "+info.Title, false)
+		}
 
 		return
 	}
@@ -835,15 +949,17 @@ func modifyPlaylistVideo(info lib.SearchResult, add bool) {
 			}
 
 			go func() {
-				InfoMessage("Adding "+info.Title+" to "+playlist.Title, true)
+				for _, info := range videos {
+					InfoMessage("Adding "+info.Title+" to "+playlist.Title, true)
 
-				err := lib.GetClient().AddPlaylistVideo(playlist.PlaylistID, info.VideoID)
-				if err != nil {
-					ErrorMessage(err)
-					return
-				}
+					err := lib.GetClient().AddPlaylistVideo(playlist.PlaylistID, info.VideoID)
+					if err != nil {
+						ErrorMessage(err)
+						return
+					}
 
-				InfoMessage("Added "+info.Title+" to "+playlist.Title, true)
+					InfoMessage("Added "+info.Title+" to "+playlist.Title, true)
+				}
 			}()
 		}
 
@@ -888,13 +1004,25 @@ func modifyPlaylistVideo(info lib.SearchResult, add bool) {
 // plEnter either plays a file or, if a playlist entry has begun
 // to move, selects the new position of the moving entry.
 func plEnter() {
-	row, _ := plistPopup.GetSelection()
+	row := selectedQueueRow()
+	pos := queuePosition(row)
+
+	if moving && len(queueMarks) > 0 {
+		moving = false
+
+		if pos > prevrow {
+			pos++
+		}
+		moveQueueEntries(pos)
+
+		return
+	}
 
 	if moving {
-		if row > prevrow {
-			lib.GetMPV().PlaylistMove(prevrow, row+1)
+		if pos > prevrow {
+			lib.GetMPV().PlaylistMove(prevrow, pos+1)
 		} else {
-			lib.GetMPV().PlaylistMove(prevrow, row)
+			lib.GetMPV().PlaylistMove(prevrow, pos)
 		}
 
 		moving = false
@@ -903,7 +1031,7 @@ func plEnter() {
 		return
 	}
 
-	lib.GetMPV().SetPlaylistPos(row)
+	lib.GetMPV().SetPlaylistPos(pos)
 
 	lib.GetMPV().Play()
 
@@ -912,6 +1040,7 @@ func plEnter() {
 
 // plExit exits the playlist popup.
 func plExit() {
+	queueFilter = ""
 	exitFocus()
 	popupStatus(false)
 	ResultsList.SetSelectable(true, false)
@@ -920,11 +1049,12 @@ func plExit() {
 // plDelete deletes an entry from the playlist
 func plDelete() {
 	rows := plistPopup.GetRowCount()
-	row, _ := plistPopup.GetSelection()
+	row := selectedQueueRow()
+	pos := queuePosition(row)
 
 	switch {
 	case row >= rows-1:
-		if lib.GetMPV().LoopType() != "R-P" {
+		if lib.GetMPV().LoopType() != "R-P" && pos >= lib.GetMPV().PlaylistCount()-1 {
 			lib.GetMPV().Prev()
 		}
 		plistPopup.Select(row-1, 0)
@@ -933,19 +1063,22 @@ func plDelete() {
 		plistPopup.Select(row, 0)
 	}
 
-	lib.GetMPV().PlaylistDelete(row)
+	addQueueEntryUndo(pos)
+	lib.GetMPV().PlaylistDelete(pos)
 
-	pos := lib.GetMPV().PlaylistPos()
-	if pos == row {
+	if lib.GetMPV().PlaylistPos() == pos {
 		sendPlayerEvent()
 	}
 }
 
 // plMove begins to move the position of a playlist entry.
+// The queue position of the entry is stored in prevrow.
 func plMove() {
-	prevrow, _ = plistPopup.GetSelection()
+	row := selectedQueueRow()
+
+	prevrow = queuePosition(row)
 	moving = true
-	plistPopup.Select(prevrow, 0)
+	plistPopup.Select(row, 0)
 }
 
 // plOpenReplace opens a playlist file, and replaces the current playlist.
@@ -954,6 +1087,7 @@ func plOpenReplace(openpath string) {
 
 	err := lib.GetMPV().LoadPlaylist(openpath, true)
 	if err != nil {
+		ErrorMessage(err)
 		return
 	}
 
@@ -1060,7 +1194,7 @@ func plSaveAs(savepath string) {
 		}
 	}
 
-	entries, err := plGetEntries(savepath, list, appendfile)
+	entries, _, err := plGetEntries(savepath, list, appendfile)
 	if err != nil {
 		ErrorMessage(err)
 		return
@@ -1088,8 +1222,9 @@ func plSaveAs(savepath string) {
 // plGetEntries generates playlist entries with a m3u8 header if entries are being
 // overwritten to a playlist file. If appendfile is set, it reads the playlist
 // file, filters out the duplicates from the playlist entry list, and appends entries
-// to the already existing playlist entries from the playlist file.
-func plGetEntries(savepath string, list []PlaylistData, appendfile bool) (string, error) {
+// to the already existing playlist entries from the playlist file. It also
+// returns the number of entries which are not duplicates.
+func plGetEntries(savepath string, list []PlaylistData, appendfile bool) (string, int, error) {
 	var skipped int
 	var entries string
 	var fileEntries map[string]struct{}
@@ -1099,7 +1234,7 @@ func plGetEntries(savepath string, list []PlaylistData, appendfile bool) (string
 
 		plfile, err := os.Open(savepath)
 		if err != nil {
-			return "", fmt.Errorf("Unable to open playlist")
+			return "", 0, fmt.Errorf("Unable to open playlist")
 		}
 
 		scanner := bufio.NewScanner(plfile)
@@ -1139,10 +1274,10 @@ func plGetEntries(savepath string, list []PlaylistData, appendfile bool) (string
 	}
 
 	if skipped == len(list) {
-		return "", fmt.Errorf("No new items in playlist to append")
+		return "", 0, fmt.Errorf("No new items in playlist to append")
 	}
 
-	return entries, nil
+	return entries, len(list) - skipped, nil
 }
 
 // plFbExit exits the filebrowser.
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 )
@@ -72,7 +73,7 @@ func resizemodal() {
 	playing := isPlaying()
 	if popup.playing != playing {
 		if playing {
-			pad += 2
+			pad += playerHeight()
 		}
 
 		popup.modal.RemoveItemIndex(popup.modal.GetItemCount() - 1)
@@ -100,7 +101,7 @@ func statusmodal(v, t tview.Primitive) tview.Primitive {
 	}
 	playing := isPlaying()
 	if playing {
-		pad += 2
+		pad += playerHeight()
 	}
 
 	vbox := getVbox()
@@ -133,6 +134,8 @@ func statusmodal(v, t tview.Primitive) tview.Primitive {
 	return stflex
 }
 
+// getVbox returns a box with a horizontal line drawn across it.
+// In screen-reader mode, the line is not drawn.
 func getVbox() *tview.Box {
 	return tview.NewBox().
 		SetBackgroundColor(tcell.ColorDefault).
@@ -141,7 +144,7 @@ func getVbox() *tview.Box {
 			x, y, width, height int) (int, int, int, int) {
 
 			centerY := y + height/2
-			for cx := x; cx < x+width; cx++ {
+			for cx := x; cx < x+width && !lib.ScreenReaderMode(); cx++ {
 				screen.SetContent(
 					cx,
 					centerY,
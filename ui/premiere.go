@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// schedulePremiere schedules an upcoming premiere to be queued once it starts.
+func schedulePremiere(info lib.SearchResult, audio bool) {
+	if !lib.SchedulePremiere(info, audio) {
+		InfoMessage(info.Title+" is already scheduled", false)
+		return
+	}
+
+	InfoMessage(
+		info.Title+" premieres in "+lib.PremiereCountdown(info.PremiereTimestamp)+
+			", it will be queued when it starts", false,
+	)
+}
+
+// monitorPremieres shows a countdown to the next scheduled premiere
+// in the status bar, and shows whether premieres were queued once
+// they have started.
+func monitorPremieres() {
+	var shown bool
+
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-sctx.Done():
+			return
+
+		case p := <-lib.PremiereStatus:
+			if p.Err != nil {
+				ErrorMessage(fmt.Errorf("Could not queue %s: %s", p.Title, p.Err))
+				continue
+			}
+
+			InfoMessage("Premiere started, queued "+p.Title, false)
+
+		case <-t.C:
+		}
+
+		var text string
+
+		premieres := lib.Premieres()
+		if len(premieres) == 0 && !shown {
+			continue
+		}
+
+		if shown = len(premieres) > 0; shown {
+			text = "[yellow::b]Premiere " + lib.PremiereCountdown(premieres[0].Start) + "[-:-:-]"
+		}
+
+		App.QueueUpdateDraw(func() {
+			setStatusIndicator("premiere", text)
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showQualityPrompt shows the formats of the selected video, so that
+// the video is queued with the selected format instead of the default
+// quality settings.
+func showQualityPrompt() {
+	info, err := getListReference()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if info.Type != "video" {
+		ErrorMessage(fmt.Errorf("Formats can only be selected for videos"))
+		return
+	}
+
+	vpg, vtable := VPage.GetFrontPage()
+
+	go loadQualityPrompt(info, vpg, vtable)
+}
+
+// loadQualityPrompt fetches the formats of the video
+// and shows them in a popup above the given page.
+func loadQualityPrompt(info lib.SearchResult, vpg string, vtable tview.Primitive) {
+	InfoMessage("Getting formats for "+info.Title, true)
+
+	lib.JobReset("video")
+
+	video, err := lib.GetClient().Video(info.VideoID)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if video.LiveNow {
+		ErrorMessage(fmt.Errorf("Cannot select a format for a live video"))
+		return
+	}
+
+	App.QueueUpdateDraw(func() {
+		qualityPopup := tview.NewTable()
+		qualityPopup.SetBorder(true)
+		qualityPopup.SetSelectorWrap(true)
+		qualityPopup.SetSelectable(true, false)
+		qualityPopup.SetTitle(" [::b]Select format ")
+		qualityPopup.SetBackgroundColor(tcell.ColorDefault)
+		qualityPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			exit := func() {
+				VPage.RemovePage("qualityoption")
+				App.SetFocus(vtable)
+			}
+
+			switch event.Key() {
+			case tcell.KeyEnter:
+				row, _ := qualityPopup.GetSelection()
+				if format, ok := qualityPopup.GetCell(row, 0).GetReference().(lib.FormatData); ok {
+					go playFormat(info, format)
+				}
+
+				fallthrough
+
+			case tcell.KeyEscape:
+				exit()
+			}
+
+			return event
+		})
+
+		length := setQualityOptions(qualityPopup, video, "")
+
+		wrapOptions := tview.NewFlex().
+			AddItem(nil, 0, 20, false).
+			AddItem(qualityPopup, 0, 40, false).
+			AddItem(nil, 0, 20, false).
+			SetDirection(tview.FlexRow)
+		wrapOptions.SetBackgroundColor(tcell.ColorDefault)
+
+		qualityFlex := tview.NewFlex().
+			AddItem(nil, 0, 10, false).
+			AddItem(wrapOptions, length, 0, false).
+			AddItem(nil, 0, 10, false).
+			SetDirection(tview.FlexColumn)
+		qualityFlex.SetBackgroundColor(tcell.ColorDefault)
+
+		VPage.AddAndSwitchToPage("qualityoption", qualityFlex, true).ShowPage(vpg)
+
+		App.SetFocus(qualityPopup)
+	})
+
+	InfoMessage("Formats loaded", false)
+}
+
+// setQualityOptions lists the formats of the video in the table, and
+// returns the width of the longest option. The format with the given
+// itag is marked and selected.
+func setQualityOptions(table *tview.Table, video lib.VideoResult, itag string) int {
+	var length int
+
+	for i, formats := range [][]lib.FormatData{
+		video.FormatStreams,
+		video.AdaptiveFormats,
+	} {
+		for _, format := range formats {
+			bitrate := "-"
+			if format.Bitrate > 0 {
+				bitrate = strconv.FormatInt(format.Bitrate/1000, 10) + " kbps"
+			}
+
+			option, ok := formatOption(format, i == 0, bitrate)
+			if !ok {
+				continue
+			}
+
+			row := table.GetRowCount()
+
+			if format.Itag == itag {
+				option = "* " + option
+				table.Select(row, 0)
+			}
+
+			if optionLength := tview.TaggedStringWidth(option) + 6; optionLength > length {
+				length = optionLength
+			}
+
+			table.SetCell(row, 0, tview.NewTableCell(option).
+				SetExpansion(1).
+				SetReference(format).
+				SetSelectedStyle(auxStyle),
+			)
+		}
+	}
+
+	return length
+}
+
+// showPlayingQuality shows the formats of the playing video, so that the
+// video can be switched to another format while it is playing.
+func showPlayingQuality() {
+	InfoMessage("Getting formats for the playing video", true)
+
+	lib.JobReset("video")
+
+	video, itag, err := lib.PlayingFormats()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	App.QueueUpdateDraw(func() {
+		qualityTitle := tview.NewTextView()
+		qualityTitle.SetDynamicColors(true)
+		qualityTitle.SetTextAlign(tview.AlignCenter)
+		qualityTitle.SetText("[white::bu]Switch format")
+		qualityTitle.SetBackgroundColor(tcell.ColorDefault)
+
+		qualityPopup := tview.NewTable()
+		qualityPopup.SetBorders(false)
+		qualityPopup.SetSelectorWrap(true)
+		qualityPopup.SetSelectable(true, false)
+		qualityPopup.SetBackgroundColor(tcell.ColorDefault)
+		qualityPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			captureSendPlayerEvent(event)
+
+			switch event.Key() {
+			case tcell.KeyEscape:
+				exitFocus()
+
+			case tcell.KeyEnter:
+				row, _ := qualityPopup.GetSelection()
+				exitFocus()
+
+				if format, ok := qualityPopup.GetCell(row, 0).GetReference().(lib.FormatData); ok && format.Itag != itag {
+					go switchFormat(video, format)
+				}
+			}
+
+			return event
+		})
+
+		setQualityOptions(qualityPopup, video, itag)
+
+		qualityFlex := tview.NewFlex().
+			AddItem(qualityTitle, 1, 0, false).
+			AddItem(qualityPopup, 10, 10, false).
+			SetDirection(tview.FlexRow)
+
+		MPage.AddAndSwitchToPage(
+			"switchquality",
+			statusmodal(qualityFlex, qualityPopup),
+			true,
+		).ShowPage("ui")
+
+		App.SetFocus(qualityPopup)
+	})
+
+	InfoMessage("Formats loaded", false)
+}
+
+// switchFormat reloads the playing video with the given format.
+func switchFormat(video lib.VideoResult, format lib.FormatData) {
+	InfoMessage("Switching format of "+video.Title, true)
+
+	if err := lib.SwitchFormat(video, format); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Switched format of "+video.Title, false)
+}
+
+// formatOption returns the description of a format, with its type, codec,
+// and resolution and frame rate, or sample rate and channels. The details,
+// for example the bitrate or size, are shown after the type. If muxed is
+// set, the format contains both video and audio. It returns false for
+// formats which cannot be played or downloaded.
+func formatOption(format lib.FormatData, muxed bool, details string) (string, bool) {
+	var minfo string
+	var optionInfo []string
+
+	mtype := strings.Split(strings.Split(format.Type, ";")[0], "/")[0]
+	if (mtype == "audio" && (format.Container == "" || format.Encoding == "")) ||
+		(mtype == "video" && (format.Resolution == "" || format.FPS == 0)) ||
+		(mtype != "audio" && mtype != "video") {
+		return "", false
+	}
+
+	if muxed {
+		minfo = " + audio"
+	} else {
+		minfo = " only"
+	}
+
+	optionInfo = []string{
+		"[red::b]" + mtype + minfo + "[-:-:-]",
+		"[blue::b]" + details + "[-:-:-]",
+		"[purple::b]" + format.Container + "/" + formatCodec(format) + "[-:-:-]",
+	}
+	if mtype != "audio" {
+		optionInfo = append(optionInfo, []string{
+			"[green::b]" + format.Resolution + "[-:-:-]",
+			"[yellow::b]" + strconv.Itoa(format.FPS) + "fps[-:-:-]",
+		}...)
+	} else {
+		optionInfo = append(optionInfo, []string{
+			"[lightpink::b]" + strconv.Itoa(format.AudioSampleRate) + "Hz[-:-:-]",
+			"[grey::b]" + strconv.Itoa(format.AudioChannels) + "ch[-:-:-]",
+		}...)
+	}
+
+	return strings.Join(optionInfo, ", "), true
+}
+
+// formatCodec returns the codec of a format, for example "avc1" from
+// a type of 'video/mp4; codecs="avc1.4d401f"'.
+func formatCodec(format lib.FormatData) string {
+	pos := strings.Index(format.Type, "codecs=")
+	if pos < 0 {
+		return format.Encoding
+	}
+
+	codec := strings.Trim(format.Type[pos+len("codecs="):], `"`)
+	if dot := strings.Index(codec, "."); dot >= 0 {
+		codec = codec[:dot]
+	}
+
+	return codec
+}
+
+// playFormat queues the video with the given format.
+func playFormat(info lib.SearchResult, format lib.FormatData) {
+	InfoMessage("Loading "+info.Title, true)
+
+	if err := addRateLimit.Acquire(context.Background(), 1); err != nil {
+		return
+	}
+	defer addRateLimit.Release(1)
+
+	if err := lib.MPVWait(); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	lib.JobReset("video")
+
+	title, err := lib.LoadVideoFormat(info.VideoID, format)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	info.Title = title
+	go addToPlayHistory(info)
+
+	InfoMessage("Added "+title, false)
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var (
+	// queueFilter is the lowercased text which is searched for in the
+	// titles and authors of the queue entries.
+	queueFilter string
+
+	// queueFiltering is set while the queue filter is being entered,
+	// so that the queue is redrawn even though it does not have focus.
+	queueFiltering bool
+
+	// queueRows stores the queue position of each row which is shown in
+	// the queue. Only the entries which match the queue filter are shown.
+	queueRows []int
+)
+
+// filterQueue asks for text to search for in the queue. As the text is
+// entered, only the entries which match it are shown in the queue, and
+// the filter is kept until the queue is closed.
+func filterQueue() {
+	start := queuePosition(selectedQueueRow())
+
+	exit := func() {
+		queueFiltering = false
+
+		App.SetFocus(plistPopup)
+		Status.SwitchToPage("messages")
+
+		sendPlaylistEvent()
+	}
+
+	queueFiltering = true
+
+	SetInput("Filter queue:", 0, nil, func(e *tcell.EventKey) *tcell.EventKey {
+		switch e.Key() {
+		case tcell.KeyEnter:
+			exit()
+
+		case tcell.KeyEscape:
+			queueFilter = ""
+			exit()
+
+			plistPopup.Select(start, 0)
+		}
+
+		return e
+	}, func(text string) {
+		queueFilter = strings.ToLower(text)
+		plistPopup.Select(0, 0)
+
+		sendPlaylistEvent()
+	})
+}
+
+// queueMatch returns whether the title or author
+// of a queue entry contains the queue filter.
+func queueMatch(title, author string) bool {
+	return strings.Contains(strings.ToLower(title+" "+author), queueFilter)
+}
+
+// selectedQueueRow returns the selected row in the queue.
+func selectedQueueRow() int {
+	row, _ := plistPopup.GetSelection()
+
+	return row
+}
+
+// queuePosition returns the queue position of the entry
+// which is shown at the given row of the queue.
+func queuePosition(row int) int {
+	if row < 0 || row >= len(queueRows) {
+		return row
+	}
+
+	return queueRows[row]
+}
+
+// queueRow returns the row at which the entry at the given queue
+// position is shown, or the row of the next shown entry if it is
+// hidden by the queue filter.
+func queueRow(pos int) int {
+	for row, p := range queueRows {
+		if p >= pos {
+			return row
+		}
+	}
+
+	if len(queueRows) > 0 && queueFilter != "" {
+		return len(queueRows) - 1
+	}
+
+	return pos
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// queueMarks stores the mpv playlist IDs of the marked queue entries.
+// The IDs do not change when entries are moved, so entries stay marked
+// while the queue is reordered.
+var queueMarks = make(map[int]struct{})
+
+// toggleQueueMark marks or unmarks the selected queue entry,
+// and moves the selection to the next entry.
+func toggleQueueMark() {
+	entries := queueEntries()
+	row := selectedQueueRow()
+
+	pos := queuePosition(row)
+	if pos < 0 || pos >= len(entries) {
+		return
+	}
+
+	id := entries[pos].ID
+	if _, ok := queueMarks[id]; ok {
+		delete(queueMarks, id)
+	} else {
+		queueMarks[id] = struct{}{}
+	}
+
+	if row+1 < plistPopup.GetRowCount() {
+		plistPopup.Select(row+1, 0)
+	}
+
+	sendPlaylistEvent()
+}
+
+// clearQueueMarks unmarks all queue entries.
+func clearQueueMarks() {
+	queueMarks = make(map[int]struct{})
+	sendPlaylistEvent()
+}
+
+// isQueueMarked returns whether the queue entry with the given ID is marked.
+func isQueueMarked(id int) bool {
+	_, ok := queueMarks[id]
+	return ok
+}
+
+// markedQueuePositions returns the sorted positions of the marked entries,
+// and removes the marks of entries which are no longer in the queue.
+func markedQueuePositions(entries []lib.PlaylistEntry) []int {
+	var positions []int
+
+	marks := make(map[int]struct{}, len(queueMarks))
+	for pos, entry := range entries {
+		if isQueueMarked(entry.ID) {
+			marks[entry.ID] = struct{}{}
+			positions = append(positions, pos)
+		}
+	}
+
+	queueMarks = marks
+
+	return positions
+}
+
+// deleteQueueEntries deletes the marked queue entries at once,
+// or the selected entry if no entries are marked.
+func deleteQueueEntries() {
+	entries := queueEntries()
+
+	positions := markedQueuePositions(entries)
+	if positions == nil {
+		plDelete()
+		return
+	}
+
+	var playingDeleted bool
+
+	playing := lib.GetMPV().PlaylistPos()
+	addQueueEntriesUndo(entries, positions)
+
+	for i := len(positions) - 1; i >= 0; i-- {
+		lib.GetMPV().PlaylistDelete(positions[i])
+		playingDeleted = playingDeleted || positions[i] == playing
+	}
+
+	queueMarks = make(map[int]struct{})
+
+	row := queueRow(positions[0])
+	if remaining := plistPopup.GetRowCount() - len(positions); row >= remaining {
+		row = remaining - 1
+	}
+	plistPopup.Select(row, 0)
+
+	if playingDeleted {
+		sendPlayerEvent()
+	}
+
+	InfoMessage("Deleted "+strconv.Itoa(len(positions))+" entries", false)
+}
+
+// moveQueueEntries moves the marked queue entries as a block, or the
+// selected entry if no entries are marked, so that the entries are
+// placed before the entry at dest, or at the end of the queue if dest
+// is the length of the queue. The entries keep their order in the block.
+func moveQueueEntries(dest int) {
+	entries := queueEntries()
+	if len(entries) == 0 {
+		return
+	}
+
+	positions := markedQueuePositions(entries)
+	if positions == nil {
+		pos := queuePosition(selectedQueueRow())
+		if pos < 0 || pos >= len(entries) {
+			return
+		}
+
+		positions = []int{pos}
+	}
+
+	moved := make(map[int]struct{}, len(positions))
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	for _, pos := range positions {
+		moved[pos] = struct{}{}
+	}
+
+	anchor := -1
+	for i := dest; i < len(entries); i++ {
+		if _, ok := moved[i]; !ok {
+			anchor = ids[i]
+			break
+		}
+	}
+
+	for _, pos := range positions {
+		id := entries[pos].ID
+
+		from, to := indexOfID(ids, id), len(ids)
+		if anchor >= 0 {
+			to = indexOfID(ids, anchor)
+		}
+		if from+1 == to {
+			continue
+		}
+
+		lib.GetMPV().PlaylistMove(from, to)
+
+		ids = append(ids[:from], ids[from+1:]...)
+		if to > from {
+			to--
+		}
+		ids = append(ids[:to], append([]int{id}, ids[to:]...)...)
+	}
+
+	plistPopup.Select(queueRow(indexOfID(ids, entries[positions[0]].ID)), 0)
+}
+
+// moveQueueEntriesTo moves the marked or selected queue
+// entries to the top or the bottom of the queue.
+func moveQueueEntriesTo(top bool) {
+	dest := 0
+	if !top {
+		dest = lib.GetMPV().PlaylistCount()
+	}
+
+	moveQueueEntries(dest)
+}
+
+// queueEntries returns the entries in the queue.
+func queueEntries() []lib.PlaylistEntry {
+	entries, err := lib.GetMPV().PlaylistEntries()
+	if err != nil {
+		ErrorMessage(err)
+		return nil
+	}
+
+	return entries
+}
+
+// indexOfID returns the position of the entry with the given ID.
+func indexOfID(ids []int, id int) int {
+	for i, entryID := range ids {
+		if entryID == id {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// addQueueToPlaylist adds the marked queue entries, or the selected
+// entry if no entries are marked, to an account playlist or to a local
+// playlist file. A local playlist file is chosen directly if there is
+// no account, otherwise it is listed along with the account playlists.
+func addQueueToPlaylist() {
+	entries := queueEntries()
+
+	positions := markedQueuePositions(entries)
+	if positions == nil {
+		pos := queuePosition(selectedQueueRow())
+		if pos < 0 || pos >= len(entries) {
+			return
+		}
+
+		positions = []int{pos}
+	}
+
+	list := make([]PlaylistData, 0, len(positions))
+	for _, pos := range positions {
+		list = append(list, queuePlaylistData(entries[pos]))
+	}
+
+	queueMarks = make(map[int]struct{})
+	plExit()
+
+	if !lib.IsAuthInstance() {
+		showQueuePlaylistFile(list)
+		return
+	}
+
+	go selectQueuePlaylist(list)
+}
+
+// selectQueuePlaylist shows a popup to select the playlist
+// which the queue entries should be added to.
+func selectQueuePlaylist(list []PlaylistData) {
+	InfoMessage("Retrieving playlists", true)
+
+	playlists, err := lib.GetClient().AuthPlaylists()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Retrieved playlists", false)
+
+	App.QueueUpdateDraw(func() {
+		selectTitle := tview.NewTextView()
+		selectTitle.SetDynamicColors(true)
+		selectTitle.SetTextAlign(tview.AlignCenter)
+		selectTitle.SetText("[white::bu]Add " + strconv.Itoa(len(list)) + " entries to playlist")
+		selectTitle.SetBackgroundColor(tcell.ColorDefault)
+
+		selectPopup := tview.NewTable()
+		selectPopup.SetBorders(false)
+		selectPopup.SetSelectorWrap(true)
+		selectPopup.SetSelectable(true, false)
+		selectPopup.SetBackgroundColor(tcell.ColorDefault)
+		selectPopup.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			captureSendPlayerEvent(event)
+
+			switch event.Key() {
+			case tcell.KeyEscape:
+				exitFocus()
+
+			case tcell.KeyEnter:
+				row, _ := selectPopup.GetSelection()
+				exitFocus()
+
+				playlist, ok := selectPopup.GetCell(row, 0).GetReference().(lib.SearchResult)
+				if !ok {
+					showQueuePlaylistFile(list)
+					break
+				}
+
+				go addQueueToAccountPlaylist(playlist, list)
+			}
+
+			return event
+		})
+
+		selectPopup.SetCell(0, 0, tview.NewTableCell("[white::b]Local playlist file").
+			SetExpansion(1).
+			SetSelectedStyle(mainStyle),
+		)
+
+		selectPopup.SetCell(0, 1, tview.NewTableCell("").
+			SetSelectable(true),
+		)
+
+		for i, p := range playlists {
+			ref := lib.SearchResult{
+				Type:       "playlist",
+				Title:      p.Title,
+				PlaylistID: p.PlaylistID,
+				Author:     p.Author,
+			}
+
+			selectPopup.SetCell(i+1, 0, tview.NewTableCell("[blue::b]"+tview.Escape(p.Title)).
+				SetExpansion(1).
+				SetReference(ref).
+				SetSelectedStyle(mainStyle),
+			)
+
+			selectPopup.SetCell(i+1, 1, tview.NewTableCell("[pink]"+strconv.Itoa(p.VideoCount)+" videos").
+				SetSelectable(true).
+				SetAlign(tview.AlignRight).
+				SetSelectedStyle(auxStyle),
+			)
+		}
+
+		selectFlex := tview.NewFlex().
+			AddItem(selectTitle, 1, 0, false).
+			AddItem(selectPopup, 10, 10, false).
+			SetDirection(tview.FlexRow)
+
+		MPage.AddAndSwitchToPage(
+			"selectplaylist",
+			statusmodal(selectFlex, selectPopup),
+			true,
+		).ShowPage("ui")
+
+		App.SetFocus(selectPopup)
+	})
+}
+
+// showQueuePlaylistFile shows the file browser to select the
+// local playlist file which the queue entries should be added to.
+func showQueuePlaylistFile(list []PlaylistData) {
+	ShowFileBrowser("playlist", "Add to:", func(savepath string) {
+		addQueueToPlaylistFile(savepath, list)
+	}, plFbExit)
+}
+
+// addQueueToAccountPlaylist adds the queue entries to the account playlist.
+// Entries which are not Youtube videos, like local files, are skipped.
+func addQueueToAccountPlaylist(playlist lib.SearchResult, list []PlaylistData) {
+	var added int
+
+	for _, data := range list {
+		if data.VideoID == "" || data.VideoID == "-" {
+			continue
+		}
+
+		InfoMessage("Adding "+data.Title+" to "+playlist.Title, true)
+
+		if err := lib.GetClient().AddPlaylistVideo(playlist.PlaylistID, data.VideoID); err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		added++
+	}
+
+	if added == 0 {
+		ErrorMessage(fmt.Errorf("Only videos can be added to %s", playlist.Title))
+		return
+	}
+
+	InfoMessage("Added "+strconv.Itoa(added)+" entries to "+playlist.Title, false)
+}
+
+// addQueueToPlaylistFile adds the queue entries to the local playlist
+// file, which is created if it does not exist. Entries which are
+// already in the playlist file are skipped.
+func addQueueToPlaylistFile(savepath string, list []PlaylistData) {
+	var appendfile bool
+
+	if !plistSaveLock.TryAcquire(1) {
+		InfoMessage("Playlist save in progress", false)
+		return
+	}
+	defer plistSaveLock.Release(1)
+
+	if filepath.Ext(savepath) != ".m3u8" {
+		savepath += ".m3u8"
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if _, err := os.Stat(savepath); err == nil {
+		appendfile = true
+		flags |= os.O_APPEND
+	}
+
+	entries, added, err := plGetEntries(savepath, list, appendfile)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	file, err := os.OpenFile(savepath, flags, 0664)
+	if err != nil {
+		ErrorMessage(fmt.Errorf("Unable to open playlist"))
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(entries); err != nil {
+		ErrorMessage(fmt.Errorf("Unable to save playlist"))
+		return
+	}
+
+	InfoMessage("Added "+strconv.Itoa(added)+" entries to "+filepath.Base(savepath), false)
+}
+
+// queuePlaylistData returns the playlist data of a queue entry. If the title
+// cannot be determined from the entry, the title which the entry was loaded
+// with is looked up from the playlist monitor.
+func queuePlaylistData(entry lib.PlaylistEntry) PlaylistData {
+	data := getPlaylistData(entry)
+	if data.Filename == "" {
+		data = PlaylistData{
+			ID:       entry.ID,
+			Filename: entry.Filename,
+			Playing:  entry.Current,
+			Title:    entry.Title,
+		}
+	}
+
+	if data.Title == "" || data.Title == entry.Filename {
+		data.Title = entry.Filename
+
+		if title, ok := lib.MonitorTitle(entry.ID); ok && title != "" {
+			data.Title = title
+		}
+	}
+
+	return data
+}
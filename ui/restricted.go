@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/gdamore/tcell/v2"
+)
+
+// toggleRestrictedLock activates locked mode, or asks
+// for the PIN to deactivate it if it is active.
+func toggleRestrictedLock() {
+	if !lib.RestrictedLocked() {
+		if err := lib.LockRestricted(); err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		setLockedIndicator()
+		InfoMessage("Locked mode activated", false)
+
+		return
+	}
+
+	p := App.GetFocus()
+
+	SetInput("PIN:", 0, nil, func(e *tcell.EventKey) *tcell.EventKey {
+		switch e.Key() {
+		case tcell.KeyEnter:
+			if !lib.UnlockRestricted(InputBox.GetText()) {
+				ErrorMessage(fmt.Errorf("Incorrect PIN"))
+			} else {
+				setLockedIndicator()
+				InfoMessage("Locked mode deactivated", false)
+			}
+
+			InputBox.SetText("")
+
+			fallthrough
+
+		case tcell.KeyEscape:
+			App.SetFocus(p)
+			Status.SwitchToPage("messages")
+		}
+
+		return e
+	})
+	InputBox.SetMaskCharacter('*')
+}
+
+// setLockedIndicator shows an indicator in the
+// status bar if locked mode is active.
+func setLockedIndicator() {
+	var text string
+
+	if lib.RestrictedLocked() {
+		text = "[red::b]LOCKED[-:-:-]"
+	}
+
+	setStatusIndicator("locked", text)
+}
+
+// lockedFeature returns whether the feature is hidden because
+// locked mode is active, and shows a message if it is.
+func lockedFeature(name string) bool {
+	if !lib.RestrictedLocked() {
+		return false
+	}
+
+	InfoMessage(name+" is not available in locked mode", false)
+
+	return true
+}
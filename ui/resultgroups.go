@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+)
+
+// resultGroup is a section of the results list, which
+// lists the search results of a single type.
+type resultGroup struct {
+	rtype string
+	name  string
+}
+
+var (
+	// resultGroups lists the sections of the results
+	// list, in the order in which they are shown.
+	resultGroups = []resultGroup{
+		{"channel", "Channels"},
+		{"playlist", "Playlists"},
+		{"video", "Videos"},
+	}
+
+	listResults     []lib.SearchResult
+	collapsedGroups = make(map[string]bool)
+)
+
+// groupResults returns whether the results list should be
+// shown in sections, which is only done if grouping is enabled
+// and the results are of more than one type.
+func groupResults() bool {
+	if !lib.GroupResults() || len(listResults) == 0 {
+		return false
+	}
+
+	for _, result := range listResults[1:] {
+		if result.Type != listResults[0].Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// showResultGroups renders the results list in sections, with a header
+// for each section, and selects the result at the given position in
+// listResults, or the header of its section if the section is collapsed.
+func showResultGroups(selected int) {
+	pos, row := -1, 0
+	watched := watchedVideos()
+	_, _, width, _ := VPage.GetRect()
+
+	ResultsList.Clear()
+
+	for _, group := range resultGroups {
+		var entries []int
+
+		for i, result := range listResults {
+			if result.Type == group.rtype && !isWatched(watched, result) {
+				entries = append(entries, i)
+			}
+		}
+		if entries == nil {
+			continue
+		}
+
+		collapsed := collapsedGroups[group.rtype]
+
+		marker := "▼"
+		if collapsed {
+			marker = "▶"
+		}
+
+		ResultsList.SetCell(row, 0, tview.NewTableCell("[::b]"+marker+" "+group.name+" ("+strconv.Itoa(len(entries))+")").
+			SetReference(group.rtype).
+			SetSelectedStyle(auxStyle),
+		)
+
+		header := row
+		row++
+
+		for _, i := range entries {
+			switch {
+			case i == selected && collapsed:
+				pos = header
+
+			case i == selected:
+				pos = row
+			}
+
+			if !collapsed {
+				setResultRow(row, listResults[i], width)
+				row++
+			}
+		}
+	}
+
+	if pos < 0 {
+		pos = 0
+	}
+
+	ResultsList.Select(pos, 0)
+}
+
+// toggleResultGroup collapses or expands the section of the results list
+// whose header is selected. It returns false if a header is not selected.
+func toggleResultGroup() bool {
+	if !groupResults() {
+		return false
+	}
+
+	row, _ := ResultsList.GetSelection()
+
+	cell := ResultsList.GetCell(row, 0)
+	if cell == nil {
+		return false
+	}
+
+	rtype, ok := cell.GetReference().(string)
+	if !ok {
+		return false
+	}
+
+	collapsedGroups[rtype] = !collapsedGroups[rtype]
+
+	showResultGroups(-1)
+
+	for i := 0; i < ResultsList.GetRowCount(); i++ {
+		if cell := ResultsList.GetCell(i, 0); cell != nil && cell.GetReference() == rtype {
+			ResultsList.Select(i, 0)
+			break
+		}
+	}
+
+	return true
+}
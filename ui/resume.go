@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// askStartOver shows the position which the playing video was resumed at,
+// and asks whether to start over from the beginning instead. If an input
+// is being typed, only a message is shown.
+func askStartOver(position int64) {
+	text := "Resuming at " + lib.FormatDuration(position)
+
+	p := App.GetFocus()
+	if _, ok := p.(*tview.InputField); ok {
+		InfoMessage(text, false)
+		return
+	}
+
+	SetInput(text+". Start over? (y/n)", 1, nil, func(e *tcell.EventKey) *tcell.EventKey {
+		switch e.Key() {
+		case tcell.KeyEnter:
+			if InputBox.GetText() == "y" {
+				lib.GetMPV().Set("time-pos", 0)
+			}
+
+			fallthrough
+
+		case tcell.KeyEscape:
+			App.SetFocus(p)
+			Status.SwitchToPage("messages")
+		}
+
+		return e
+	})
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/gdamore/tcell/v2"
+)
+
+// sessionState stores the queue and playback position, which are saved
+// periodically and restored on the next launch if invidtui exited uncleanly.
+type sessionState struct {
+	Queue    []string `json:"queue"`
+	Position int      `json:"position"`
+	Time     int64    `json:"time"`
+}
+
+// sessionInterval is the interval at which the session is saved.
+const sessionInterval = 30 * time.Second
+
+var sessionStop chan struct{}
+
+// startSession offers to restore the previous session, if it was not closed
+// cleanly, and starts saving the current session periodically.
+func startSession() {
+	mode := lib.RestoreSession()
+	if mode == "never" {
+		return
+	}
+
+	if session, ok := loadSession(); ok {
+		switch {
+		case mode == "always":
+			go restoreSession(session)
+
+		default:
+			go App.QueueUpdateDraw(func() {
+				askRestoreSession(session)
+			})
+		}
+	}
+
+	sessionStop = make(chan struct{})
+	go checkpointSession(sessionStop)
+}
+
+// stopSession stops saving the session and removes the saved session,
+// so that it is not offered for restoring on the next launch.
+func stopSession() {
+	if sessionStop == nil {
+		return
+	}
+
+	close(sessionStop)
+
+	if sessionFile, err := lib.DataPath("session.json"); err == nil {
+		os.Remove(sessionFile)
+	}
+}
+
+// askRestoreSession asks whether to restore the previous session.
+func askRestoreSession(session sessionState) {
+	SetInput("Restore previous session? (y/n)", 1, nil, func(e *tcell.EventKey) *tcell.EventKey {
+		switch e.Key() {
+		case tcell.KeyEnter:
+			if InputBox.GetText() == "y" {
+				go restoreSession(session)
+			}
+
+			fallthrough
+
+		case tcell.KeyEscape:
+			_, item := VPage.GetFrontPage()
+			App.SetFocus(item)
+			Status.SwitchToPage("messages")
+		}
+
+		return e
+	})
+}
+
+// restoreSession loads the queue of the previous session, and seeks to
+// the last playback position. Playback is paused after restoring.
+func restoreSession(session sessionState) {
+	InfoMessage("Restoring previous session", true)
+
+	if err := lib.MPVWait(); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	lib.GetMPV().Set("pause", "yes")
+
+	for _, filename := range session.Queue {
+		lib.GetMPV().PlaylistInsert(filename, -1)
+	}
+
+	if lib.GetMPV().PlaylistCount() == 0 {
+		InfoMessage("Could not restore previous session", false)
+		return
+	}
+
+	AddPlayer()
+
+	if session.Position >= 0 && session.Position < lib.GetMPV().PlaylistCount() {
+		lib.GetMPV().SetPlaylistPos(session.Position)
+
+		for i := 0; i < 20 && session.Time > 0; i++ {
+			time.Sleep(500 * time.Millisecond)
+
+			if lib.GetMPV().Duration() > 0 {
+				lib.GetMPV().Call("seek", session.Time, "absolute")
+				break
+			}
+		}
+	}
+
+	InfoMessage("Previous session restored", false)
+}
+
+// loadSession loads the saved session.
+func loadSession() (sessionState, bool) {
+	var session sessionState
+
+	sessionFile, err := lib.DataPath("session.json")
+	if err != nil {
+		return session, false
+	}
+
+	data, err := ioutil.ReadFile(sessionFile)
+	if err != nil || len(data) == 0 {
+		return session, false
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, false
+	}
+
+	return session, len(session.Queue) > 0
+}
+
+// checkpointSession saves the session periodically until stop is closed.
+func checkpointSession(stop chan struct{}) {
+	ticker := time.NewTicker(sessionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case <-ticker.C:
+		}
+
+		saveSession()
+	}
+}
+
+// saveSession saves the queue and the playback position.
+func saveSession() {
+	session := sessionState{Position: -1}
+
+	entries, _ := lib.GetMPV().PlaylistEntries()
+	for _, entry := range entries {
+		session.Queue = append(session.Queue, entry.Filename)
+	}
+
+	if len(session.Queue) > 0 {
+		session.Position = lib.GetMPV().PlaylistPos()
+		session.Time = lib.GetMPV().TimePosition()
+	}
+
+	sessionFile, err := lib.DataPath("session.json")
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(session, "", " ")
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(sessionFile, data, 0664)
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// statsTopMax is the maximum number of channels, videos
+// and days that are shown in the statistics view.
+const statsTopMax = 10
+
+var (
+	statsPrevPage string
+	statsPrevItem tview.Primitive
+)
+
+// ShowStats shows the total playback time, the most played channels
+// and videos, and the playback time of the most recent days and weeks.
+func ShowStats() {
+	stats := lib.GetWatchStats()
+	if stats.Plays == 0 {
+		InfoMessage("No playback statistics yet", false)
+		return
+	}
+
+	if pg, _ := VPage.GetFrontPage(); pg == "stats" {
+		return
+	}
+
+	MPage.SwitchToPage("ui")
+
+	statsPrevPage, statsPrevItem = VPage.GetFrontPage()
+
+	title := tview.NewTextView()
+	title.SetDynamicColors(true)
+	title.SetText("[::bu]Statistics")
+	title.SetTextAlign(tview.AlignLeft)
+	title.SetBackgroundColor(tcell.ColorDefault)
+
+	statsView := tview.NewTextView()
+	statsView.SetWrap(true)
+	statsView.SetDynamicColors(true)
+	statsView.SetText(statsText(stats))
+	statsView.SetBackgroundColor(tcell.ColorDefault)
+	statsView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			VPage.SwitchToPage(statsPrevPage)
+			App.SetFocus(statsPrevItem)
+		}
+
+		return event
+	})
+
+	statsFlex := tview.NewFlex().
+		AddItem(title, 1, 0, false).
+		AddItem(statsView, 0, 10, false).
+		SetDirection(tview.FlexRow)
+
+	VPage.AddAndSwitchToPage("stats", statsFlex, true)
+
+	App.SetFocus(statsView)
+}
+
+// statsText returns the text for the statistics view.
+func statsText(stats lib.WatchStats) string {
+	var text strings.Builder
+
+	text.WriteString(fmt.Sprintf(
+		"[::b]Total:[-:-:-] %d plays, %s\n",
+		stats.Plays, lib.FormatDuration(stats.TotalSeconds),
+	))
+	text.WriteString(fmt.Sprintf(
+		"[::b]Watched:[-:-:-] %s, [::b]Listened:[-:-:-] %s\n",
+		lib.FormatDuration(stats.WatchedSeconds), lib.FormatDuration(stats.ListenedSeconds),
+	))
+
+	for _, section := range []struct {
+		title    string
+		stats    []lib.WatchStat
+		activity bool
+	}{
+		{"Top channels", stats.Channels, false},
+		{"Most played videos", stats.Videos, false},
+		{"Recent days", recentStats(stats.Days), true},
+		{"Recent weeks", recentStats(stats.Weeks), true},
+	} {
+		var longest int64
+
+		text.WriteString("\n[::bu]" + section.title + "[-:-:-]\n")
+
+		for _, stat := range section.stats {
+			if stat.Seconds > longest {
+				longest = stat.Seconds
+			}
+		}
+
+		for i, stat := range section.stats {
+			if i == statsTopMax {
+				break
+			}
+
+			var bar string
+			if section.activity {
+				bar = "[green]" + activityBar(stat.Seconds, longest) + "[-] "
+			}
+
+			text.WriteString(fmt.Sprintf(
+				"%s[blue::b]%s[-:-:-] [grey]%d plays, %s[-]\n",
+				bar, tview.Escape(stat.Name), stat.Plays, lib.FormatDuration(stat.Seconds),
+			))
+		}
+	}
+
+	return text.String()
+}
+
+// recentStats returns the last statsTopMax entries of stats which are sorted by date.
+func recentStats(stats []lib.WatchStat) []lib.WatchStat {
+	if len(stats) > statsTopMax {
+		return stats[len(stats)-statsTopMax:]
+	}
+
+	return stats
+}
+
+// activityBar returns a bar whose length is proportional to the
+// playback time, relative to the longest playback time shown.
+func activityBar(seconds, longest int64) string {
+	const width = 20
+
+	length := 0
+	if longest > 0 {
+		length = int(seconds * width / longest)
+	}
+	if length == 0 && seconds > 0 {
+		length = 1
+	}
+
+	return strings.Repeat("█", length) + strings.Repeat(" ", width-length)
+}
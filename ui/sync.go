@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// syncThreshold is the difference in seconds between the host's and
+// the guest's playback position, after which the guest seeks to the
+// host's position.
+const syncThreshold = 3
+
+// startSync starts the sync session, if one was requested.
+func startSync() error {
+	if err := lib.StartSync(); err != nil {
+		return err
+	}
+
+	switch lib.SyncMode() {
+	case "host":
+		go updateSyncIndicator()
+
+	case "guest":
+		go followSyncHost()
+	}
+
+	return nil
+}
+
+// followSyncHost loads, seeks and pauses the player according
+// to the playback state received from the host.
+func followSyncHost() {
+	setSyncIndicator("[green::b]Sync: guest[-:-:-]")
+
+	for state := range lib.SyncStates() {
+		if state.VideoID == "" {
+			continue
+		}
+
+		if lib.GetMPV().PlayingVideoID() != state.VideoID {
+			lib.JobReset("video")
+
+			info := lib.SearchResult{
+				Type:    "video",
+				Title:   state.VideoID,
+				VideoID: state.VideoID,
+			}
+
+			if err := loadEntry(info, state.Audio, true); err != nil {
+				ErrorMessage(err)
+			}
+
+			continue
+		}
+
+		if lib.GetMPV().IsIdle() {
+			continue
+		}
+
+		if pos := lib.GetMPV().TimePosition(); pos-state.Position > syncThreshold ||
+			state.Position-pos > syncThreshold {
+			lib.GetMPV().Call("seek", state.Position, "absolute")
+		}
+
+		if lib.GetMPV().IsPaused() != state.Paused {
+			lib.GetMPV().CyclePaused()
+		}
+
+		sendPlayerEvent()
+	}
+
+	setSyncIndicator("")
+	ErrorMessage(fmt.Errorf("Disconnected from the sync session"))
+}
+
+// updateSyncIndicator shows the number of connected guests in the status bar.
+func updateSyncIndicator() {
+	t := time.NewTicker(5 * time.Second)
+	defer t.Stop()
+
+	for {
+		setSyncIndicator(fmt.Sprintf("[green::b]Sync: %d guest(s)[-:-:-]", lib.SyncGuests()))
+
+		<-t.C
+	}
+}
+
+// setSyncIndicator sets the sync status indicator.
+func setSyncIndicator(text string) {
+	App.QueueUpdateDraw(func() {
+		setStatusIndicator("sync", text)
+	})
+}
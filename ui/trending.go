@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+var (
+	trendingTables   map[string]*tview.Table
+	trendingResults  map[string][]lib.SearchResult
+	trendingPages    *tview.Pages
+	trendingPageMark *tview.TextView
+
+	trendingPrevPage string
+	trendingPrevItem tview.Primitive
+)
+
+const (
+	trendingMark = `[::bu]Trending[-:-:-]`
+	trendingTabs = ` ["all"][darkcyan]All[""] ["music"][darkcyan]Music[""] ["gaming"][darkcyan]Gaming[""] ["movies"][darkcyan]Movies[""]`
+)
+
+// ShowTrending shows the trending videos, with a tab for each category.
+func ShowTrending() {
+	trendingTables = make(map[string]*tview.Table)
+	trendingResults = make(map[string][]lib.SearchResult)
+	trendingPages = tview.NewPages()
+	trendingPages.SetBackgroundColor(tcell.ColorDefault)
+
+	for _, category := range lib.TrendingCategories {
+		region := trendingRegion(category)
+
+		table := tview.NewTable()
+		table.SetSelectorWrap(true)
+		table.SetBackgroundColor(tcell.ColorDefault)
+		table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			trendingTableEvents(event)
+			capturePlayerEvent(event)
+
+			switch event.Rune() {
+			case '+':
+				go Modify(true)
+
+			case ';':
+				showLinkPopup()
+
+			case 'C':
+				ShowComments()
+			}
+
+			return event
+		})
+
+		trendingTables[region] = table
+		trendingPages.AddPage(region, table, true, false)
+	}
+
+	trendingPageMark = tview.NewTextView()
+	trendingPageMark.SetWrap(false)
+	trendingPageMark.SetRegions(true)
+	trendingPageMark.SetDynamicColors(true)
+	trendingPageMark.SetText(trendingMark + trendingTabs)
+	trendingPageMark.SetBackgroundColor(tcell.ColorDefault)
+	trendingPageMark.SetHighlightedFunc(func(added, removed, remaining []string) {
+		if added == nil || added[0] == "" {
+			return
+		}
+
+		table := trendingTables[added[0]]
+
+		App.SetFocus(table)
+		trendingPages.SwitchToPage(added[0])
+
+		if table.GetRowCount() == 0 {
+			go loadTrending(added[0], false, false)
+		}
+	})
+
+	box := tview.NewBox().
+		SetBackgroundColor(tcell.ColorDefault)
+
+	trendingFlex := tview.NewFlex().
+		AddItem(trendingPageMark, 1, 0, false).
+		AddItem(box, 1, 0, false).
+		AddItem(trendingPages, 0, 10, true).
+		SetDirection(tview.FlexRow)
+	trendingFlex.SetBackgroundColor(tcell.ColorDefault)
+
+	App.QueueUpdateDraw(func() {
+		MPage.SwitchToPage("ui")
+		trendingPrevPage, trendingPrevItem = VPage.GetFrontPage()
+		VPage.AddAndSwitchToPage("trending", trendingFlex, true)
+
+		trendingPageMark.Highlight("all")
+	})
+}
+
+// loadTrending loads the trending videos of the category shown in the
+// given tab. If getmore is set, the next page of the category is loaded,
+// and if refresh is set, the category is fetched again.
+func loadTrending(region string, getmore, refresh bool) {
+	InfoMessage("Loading trending videos", true)
+
+	videos, err := lib.GetClient().Trending(trendingCategory(region), getmore, refresh)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if getmore && len(videos) == 0 {
+		InfoMessage("No more trending videos", false)
+		return
+	}
+
+	showTrending(region, videos, getmore)
+
+	InfoMessage("Trending videos loaded", false)
+}
+
+// showTrending shows the trending videos in the table of the given tab.
+func showTrending(region string, videos []lib.SearchResult, getmore bool) {
+	App.QueueUpdateDraw(func() {
+		table := trendingTables[region]
+
+		if !getmore {
+			table.Clear()
+			table.SetSelectable(false, false)
+
+			trendingResults[region] = nil
+		}
+
+		trendingResults[region] = append(trendingResults[region], videos...)
+
+		rows := table.GetRowCount()
+		setTrendingRows(table, rows, videos)
+
+		table.SetSelectable(true, false)
+
+		if getmore && rows > 0 && rows < table.GetRowCount() {
+			table.Select(rows, 0)
+		} else if !getmore {
+			table.Select(0, 0)
+		}
+	})
+}
+
+// relistTrending lists the trending videos of each tab again,
+// for example after watched videos are hidden or shown.
+func relistTrending() {
+	for region, table := range trendingTables {
+		if len(trendingResults[region]) == 0 {
+			continue
+		}
+
+		table.Clear()
+		setTrendingRows(table, 0, trendingResults[region])
+		table.Select(0, 0)
+	}
+}
+
+// setTrendingRows sets the rows of the trending videos in the
+// table from the given row, skipping watched videos if they are hidden.
+func setTrendingRows(table *tview.Table, row int, videos []lib.SearchResult) {
+	watched := watchedVideos()
+	_, _, width, _ := VPage.GetRect()
+
+	for _, video := range videos {
+		if isWatched(watched, video) {
+			continue
+		}
+
+		table.SetCell(row, 0, tview.NewTableCell("[blue::b]"+tview.Escape(video.Title)+entryBadges(video)).
+			SetExpansion(1).
+			SetReference(video).
+			SetMaxWidth((width / 4)).
+			SetSelectedStyle(mainStyle),
+		)
+
+		table.SetCell(row, 1, tview.NewTableCell("[purple::b]"+tview.Escape(video.Author)).
+			SetMaxWidth((width / 4)).
+			SetSelectedStyle(auxStyle),
+		)
+
+		table.SetCell(row, 2, tview.NewTableCell("[pink]"+entryLength(video)).
+			SetAlign(tview.AlignRight).
+			SetSelectedStyle(auxStyle),
+		)
+
+		row++
+	}
+}
+
+// trendingTableEvents handles the events of the trending tables.
+func trendingTableEvents(event *tcell.EventKey) {
+	region := trendingPageMark.GetHighlights()[0]
+
+	switch event.Key() {
+	case tcell.KeyTab:
+		switchTrendingTabs(false)
+
+	case tcell.KeyBacktab:
+		switchTrendingTabs(true)
+
+	case tcell.KeyEnter:
+		if !isAudioPlayKey(event) {
+			go loadTrending(region, true, false)
+		}
+
+	case tcell.KeyEscape:
+		App.SetFocus(trendingPrevItem)
+		VPage.SwitchToPage(trendingPrevPage)
+	}
+
+	switch event.Rune() {
+	case 'R':
+		go loadTrending(region, false, true)
+
+	case 'H':
+		toggleHideWatched()
+	}
+}
+
+// switchTrendingTabs switches to the next or previous trending tab.
+func switchTrendingTabs(reverse bool) {
+	categories := lib.TrendingCategories
+	region := trendingPageMark.GetHighlights()[0]
+
+	for i, category := range categories {
+		if trendingRegion(category) != region {
+			continue
+		}
+
+		if reverse {
+			i += len(categories) - 1
+		} else {
+			i++
+		}
+
+		trendingPageMark.Highlight(trendingRegion(categories[i%len(categories)]))
+
+		return
+	}
+}
+
+// trendingRegion returns the tab region of the trending category.
+func trendingRegion(category string) string {
+	if category == "" {
+		return "all"
+	}
+
+	return category
+}
+
+// trendingCategory returns the trending category of the tab region.
+func trendingCategory(region string) string {
+	if region == "all" {
+		return ""
+	}
+
+	return region
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/darkhz/invidtui/lib"
 	"github.com/darkhz/tview"
@@ -21,6 +22,10 @@ var (
 	// like the playlist view for example.
 	VPage *tview.Pages
 
+	// ViewFlex holds VPage, and the live chat pane
+	// to its side when it is shown.
+	ViewFlex *tview.Flex
+
 	// MPage holds the entire UI Flexbox. This is needed to
 	// align and display popups properly.
 	MPage *tview.Pages
@@ -30,7 +35,12 @@ var (
 
 	appSuspend  bool
 	bannerShown bool
+
+	// detectClose is closed when the application stops, and
+	// closeOnce ensures that it is closed only once, since
+	// StopUI can be called from multiple places.
 	detectClose chan struct{}
+	closeOnce   sync.Once
 )
 
 const banner = `
@@ -43,20 +53,36 @@ const banner = `
 // SetupUI sets up the UI and starts the application.
 func SetupUI() error {
 	setupPrimitives()
+	prefs := loadLayout()
 
-	mainStyle = tcell.Style{}.
-		Foreground(tcell.ColorBlue).
-		Background(tcell.ColorWhite).
-		Attributes(tcell.AttrBold)
-
-	auxStyle = tcell.Style{}.
-		Attributes(tcell.AttrBold)
+	setupStyles()
 
 	MPage = tview.NewPages()
 	MPage.AddPage("ui", UIFlex, true, true)
 
 	App = tview.NewApplication()
+	if lib.NoColorMode() {
+		screen, err := tcell.NewScreen()
+		if err != nil {
+			return err
+		}
+
+		App.SetScreen(&monoScreen{screen})
+	}
+
 	App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Keys are not remapped while text is being entered,
+		// so that remapped keys can still be typed.
+		if _, ok := App.GetFocus().(*tview.InputField); !ok {
+			event = remapKey("global", event)
+		}
+
+		if event.Modifiers() == tcell.ModAlt && event.Key() == tcell.KeyRune {
+			if _, ok := App.GetFocus().(*tview.InputField); !ok && runKeyHook(event.Rune()) {
+				return nil
+			}
+		}
+
 		switch event.Key() {
 		case tcell.KeyCtrlC:
 			return nil
@@ -69,13 +95,55 @@ func SetupUI() error {
 		case tcell.KeyCtrlZ:
 			appSuspend = true
 
+		case tcell.KeyCtrlU:
+			if _, ok := App.GetFocus().(*tview.InputField); !ok {
+				go Undo()
+				return nil
+			}
+
+		case tcell.KeyCtrlP:
+			if _, ok := App.GetFocus().(*tview.InputField); !ok {
+				go ShowFinder()
+				return nil
+			}
+
+		case tcell.KeyCtrlT:
+			if _, ok := App.GetFocus().(*tview.InputField); !ok {
+				if !lockedFeature("Statistics") {
+					ShowStats()
+				}
+
+				return nil
+			}
+
+		case tcell.KeyCtrlL:
+			if _, ok := App.GetFocus().(*tview.InputField); !ok {
+				toggleRestrictedLock()
+				return nil
+			}
+
+		case tcell.KeyCtrlE:
+			if _, ok := App.GetFocus().(*tview.InputField); !ok {
+				ShowErrorLog()
+				return nil
+			}
+
+		case tcell.KeyCtrlW:
+			if _, ok := App.GetFocus().(*tview.InputField); !ok {
+				go ShowWatchLater()
+				return nil
+			}
+
+		case tcell.KeyCtrlR:
+			if pg, _ := VPage.GetFrontPage(); pg != "trending" {
+				if _, ok := App.GetFocus().(*tview.InputField); !ok {
+					go ShowTrending()
+					return nil
+				}
+			}
+
 		case tcell.KeyCtrlX:
-			lib.VideoCancel()
-			lib.SearchCancel()
-			lib.PlaylistCancel()
-			lib.ClientSendCancel()
-			closeCommentView()
-			InfoMessage("Loading canceled", false)
+			cancelJobs()
 		}
 
 		switch event.Rune() {
@@ -93,6 +161,7 @@ func SetupUI() error {
 		width, height := t.Size()
 
 		suspendUI(t)
+		resizeLayout(width, height)
 		resizePlayer(width)
 		resizeListEntries(width)
 		resizePopup(width, height)
@@ -102,9 +171,37 @@ func SetupUI() error {
 	msg += "Press / to search."
 	InfoMessage(msg, true)
 
+	if err := setupKeybinds(); err != nil {
+		ErrorMessage(err)
+	}
+
+	if err := setupControl(); err != nil {
+		ErrorMessage(err)
+	}
+
+	if err := lib.StartEndpoint(); err != nil {
+		ErrorMessage(err)
+	}
+
+	if err := startSync(); err != nil {
+		ErrorMessage(err)
+	}
+
+	if err := startMPD(); err != nil {
+		ErrorMessage(err)
+	}
+
 	detectClose = make(chan struct{})
 	go detectMPVClose()
 
+	watchConfig()
+	watchSuspend()
+	startClipboardWatcher()
+	startSession()
+	setAudioOnlyIndicator()
+	setLockedIndicator()
+
+	restoreLayout(prefs)
 	parseSearchCmd()
 	parsePlayParams()
 
@@ -118,12 +215,36 @@ func SetupUI() error {
 
 // StopUI stops the application.
 func StopUI(closeInstances bool) {
-	close(detectClose)
+	closeOnce.Do(func() {
+		close(detectClose)
+	})
 
+	saveLayout()
+	lib.StopControl()
+	lib.StopEndpoint()
+	lib.StopSync()
+	stopMPD()
+	stopSession()
 	StopPlayer(closeInstances)
 	App.Stop()
 }
 
+// monoScreen wraps a screen and strips colors from its content.
+type monoScreen struct {
+	tcell.Screen
+}
+
+// SetContent sets the content of a cell without any colors. Cells
+// which have a background color, like selected items, are shown in reverse.
+func (m *monoScreen) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	_, bg, attrs := style.Decompose()
+	if bg != tcell.ColorDefault {
+		attrs |= tcell.AttrReverse
+	}
+
+	m.Screen.SetContent(x, y, mainc, combc, tcell.StyleDefault.Attributes(attrs))
+}
+
 // suspendUI suspends the application.
 func suspendUI(t tcell.Screen) {
 	if !appSuspend {
@@ -135,6 +256,25 @@ func suspendUI(t tcell.Screen) {
 	appSuspend = false
 }
 
+// watchSuspend suspends the application when it is stopped
+// from outside, and notifies if the playback was paused
+// for a system suspend after the system resumes.
+func watchSuspend() {
+	lib.WatchSuspend(func() {
+		App.QueueUpdateDraw(func() {
+			appSuspend = true
+		})
+	}, func(paused bool) {
+		App.QueueUpdateDraw(func() {
+			App.Sync()
+		})
+
+		if paused {
+			InfoMessage("Playback was paused for a system suspend", false)
+		}
+	})
+}
+
 // setupPrimitives sets up the display elements and positions
 // each element appropriately.
 func setupPrimitives() {
@@ -145,17 +285,22 @@ func setupPrimitives() {
 	SetupPlayer()
 	SetupFileBrowser()
 	SetupPlaylist()
+	setupLiveChat()
 
 	VPage = tview.NewPages()
 	VPage.AddPage("banner", showBanner(), true, true)
 	VPage.AddPage("search", ResultsFlex, true, false)
 
-	box := tview.NewBox().
+	uiSpacer = tview.NewBox().
 		SetBackgroundColor(tcell.ColorDefault)
 
+	ViewFlex = tview.NewFlex().
+		AddItem(VPage, 0, 10, false)
+	ViewFlex.SetBackgroundColor(tcell.ColorDefault)
+
 	UIFlex = tview.NewFlex().
-		AddItem(VPage, 0, 10, false).
-		AddItem(box, 1, 0, false).
+		AddItem(ViewFlex, 0, 10, false).
+		AddItem(uiSpacer, 1, 0, false).
 		AddItem(Status, 1, 0, false).
 		SetDirection(tview.FlexRow)
 
@@ -164,7 +309,12 @@ func setupPrimitives() {
 
 // showBanner displays the banner on the screen.
 func showBanner() tview.Primitive {
-	lines := strings.Split(banner, "\n")
+	text := banner
+	if lib.ScreenReaderMode() {
+		text = "invidtui"
+	}
+
+	lines := strings.Split(text, "\n")
 	bannerWidth := 0
 	bannerHeight := len(lines)
 	for _, line := range lines {
@@ -175,7 +325,7 @@ func showBanner() tview.Primitive {
 	bannerBox := tview.NewTextView()
 	bannerBox.SetDynamicColors(true)
 	bannerBox.SetBackgroundColor(tcell.ColorDefault)
-	bannerBox.SetText("[::b]" + banner)
+	bannerBox.SetText("[::b]" + text)
 
 	box := tview.NewBox().
 		SetBackgroundColor(tcell.ColorDefault)
@@ -246,18 +396,120 @@ func confirmQuit() {
 	SetInput("Quit? (y/n)", 1, qfunc, ifunc)
 }
 
-// detectMPVClose detects if MPV has exited unexpectedly,
-// and stops the application.
-func detectMPVClose() {
-	lib.GetMPV().WaitUntilClosed()
+// askRestartMPV asks whether to restart MPV after it has exited, and
+// waits for the answer.
+func askRestartMPV() bool {
+	answer := make(chan bool, 1)
+	reply := func(restart bool) {
+		select {
+		case answer <- restart:
+		default:
+		}
+	}
+
+	App.QueueUpdateDraw(func() {
+		p := App.GetFocus()
+
+		SetInput("MPV has exited. Restart it? (y/n)", 1, nil, func(e *tcell.EventKey) *tcell.EventKey {
+			switch e.Key() {
+			case tcell.KeyEnter:
+				reply(InputBox.GetText() == "y")
+
+			case tcell.KeyEscape:
+				reply(false)
+
+			default:
+				return e
+			}
+
+			App.SetFocus(p)
+			Status.SwitchToPage("messages")
+
+			return e
+		})
+	})
 
 	select {
-	case _, ok := <-detectClose:
-		if !ok {
+	case restart := <-answer:
+		return restart
+
+	case <-detectClose:
+		return false
+	}
+}
+
+// restartMPV starts a new MPV instance, and restores the player state,
+// the queue and the playback position from before MPV exited.
+func restartMPV() error {
+	InfoMessage("Restarting MPV", true)
+
+	queue, position, playTime, err := lib.MPVRestart()
+	if err != nil {
+		return err
+	}
+
+	savePlayerState()
+	loadPlayerState()
+
+	if len(queue) == 0 {
+		InfoMessage("MPV restarted", false)
+		return nil
+	}
+
+	go restoreSession(sessionState{
+		Queue:    queue,
+		Position: position,
+		Time:     playTime,
+	})
+
+	return nil
+}
+
+// detectMPVClose detects if the connection to MPV was lost, and tries to
+// reconnect to it. If MPV has exited unexpectedly, it offers to restart MPV
+// and restore the queue, and stops the application otherwise.
+func detectMPVClose() {
+	if err := lib.MPVWait(); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	closing := func() bool {
+		select {
+		case _, ok := <-detectClose:
+			return !ok
+
+		default:
+		}
+
+		return false
+	}
+
+	for {
+		lib.GetMPV().WaitUntilClosed()
+		if closing() {
 			return
 		}
 
-	default:
+		InfoMessage("Connection to MPV lost, reconnecting", true)
+
+		err := lib.MPVReconnect()
+		if closing() {
+			return
+		}
+		if err == nil {
+			InfoMessage("Reconnected to MPV", false)
+			continue
+		}
+
+		if !askRestartMPV() || closing() {
+			break
+		}
+
+		if err := restartMPV(); err != nil {
+			ErrorMessage(err)
+			break
+		}
 	}
 
 	StopUI(true)
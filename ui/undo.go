@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/darkhz/invidtui/lib"
+)
+
+// undoAction stores a description of a destructive action,
+// and the function to revert it.
+type undoAction struct {
+	desc string
+	undo func() error
+}
+
+var (
+	undoStack []undoAction
+	undoLock  sync.Mutex
+)
+
+const undoMax = 10
+
+// Undo reverts the last destructive action.
+func Undo() {
+	undoLock.Lock()
+	if len(undoStack) == 0 {
+		undoLock.Unlock()
+		InfoMessage("Nothing to undo", false)
+		return
+	}
+
+	action := undoStack[len(undoStack)-1]
+	undoStack = undoStack[:len(undoStack)-1]
+	undoLock.Unlock()
+
+	InfoMessage("Undoing "+action.desc, true)
+
+	if err := action.undo(); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Undid "+action.desc, false)
+}
+
+// addUndo adds an action to the undo stack. Only the
+// last undoMax actions are stored.
+func addUndo(desc string, undo func() error) {
+	undoLock.Lock()
+	defer undoLock.Unlock()
+
+	undoStack = append(undoStack, undoAction{desc, undo})
+	if len(undoStack) > undoMax {
+		undoStack = undoStack[len(undoStack)-undoMax:]
+	}
+}
+
+// addQueueEntryUndo stores the queue entry at pos, so that
+// it can be reinserted at the same position after deletion.
+func addQueueEntryUndo(pos int) {
+	list := updatePlaylist()
+	if pos < 0 || pos >= len(list) {
+		return
+	}
+
+	entry := list[pos]
+
+	addUndo("deletion of "+entry.Title, func() error {
+		return lib.GetMPV().PlaylistInsert(entry.Filename, pos)
+	})
+}
+
+// addQueueEntriesUndo stores the queue entries at the given sorted
+// positions, so that they can be reinserted after they are deleted.
+func addQueueEntriesUndo(entries []lib.PlaylistEntry, positions []int) {
+	addUndo("deletion of "+strconv.Itoa(len(positions))+" entries", func() error {
+		for _, pos := range positions {
+			if err := lib.GetMPV().PlaylistInsert(entries[pos].Filename, pos); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// addQueueClearUndo stores the queue entries and the current
+// position, so that the queue can be restored after it is cleared.
+func addQueueClearUndo() {
+	list := updatePlaylist()
+	if len(list) == 0 {
+		return
+	}
+
+	pos := lib.GetMPV().PlaylistPos()
+
+	addUndo("queue clear", func() error {
+		for _, entry := range list {
+			if err := lib.GetMPV().PlaylistInsert(entry.Filename, -1); err != nil {
+				return err
+			}
+		}
+
+		lib.GetMPV().SetPlaylistPos(pos)
+
+		return nil
+	})
+}
+
+// addPlaylistRemoveUndo stores the playlist's videos and privacy, so that
+// the playlist can be recreated after it is removed from the account.
+// Instances only report whether a playlist is public, so unlisted
+// playlists are recreated as private.
+func addPlaylistRemoveUndo(info lib.SearchResult) error {
+	playlist, err := lib.GetClient().AllPlaylistVideos(info.PlaylistID, true)
+	if err != nil {
+		return err
+	}
+
+	privacy := "private"
+	if playlist.IsListed {
+		privacy = "public"
+	}
+
+	addUndo("removal of playlist "+info.Title, func() error {
+		id, err := lib.GetClient().CreatePlaylist(playlist.Title, privacy)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return fmt.Errorf("Could not recreate playlist %s", playlist.Title)
+		}
+
+		for _, video := range playlist.Videos {
+			if err := lib.GetClient().AddPlaylistVideo(id, video.VideoID); err != nil {
+				return err
+			}
+		}
+
+		loadPlaylists(false)
+
+		return nil
+	})
+
+	return nil
+}
+
+// addPlaylistVideoUndo stores a video that was removed from a playlist,
+// so that it can be added back to the playlist.
+func addPlaylistVideoUndo(info lib.SearchResult) {
+	addUndo("removal of "+info.Title, func() error {
+		return lib.GetClient().AddPlaylistVideo(info.PlaylistID, info.VideoID)
+	})
+}
@@ -0,0 +1,62 @@
+package ui
+
+import "github.com/darkhz/invidtui/lib"
+
+// watchedToggled is set if hiding watched videos
+// was toggled from the value set with --hide-watched.
+var watchedToggled bool
+
+// hidingWatched returns whether watched videos are hidden
+// from the search results and the trending videos.
+func hidingWatched() bool {
+	return lib.HideWatched() != watchedToggled
+}
+
+// toggleHideWatched toggles hiding watched videos, and lists
+// the search results and the trending videos again.
+func toggleHideWatched() {
+	watchedToggled = !watchedToggled
+
+	relistResults()
+	relistTrending()
+
+	if hidingWatched() {
+		InfoMessage("Hiding watched videos", false)
+	} else {
+		InfoMessage("Showing watched videos", false)
+	}
+}
+
+// watchedVideos returns the IDs of the videos in the play
+// history and the watch log, if watched videos are hidden.
+func watchedVideos() map[string]struct{} {
+	if !hidingWatched() {
+		return nil
+	}
+
+	watched := make(map[string]struct{})
+	for id := range lib.WatchCounts() {
+		watched[id] = struct{}{}
+	}
+
+	playHistoryLock.Lock()
+	for _, info := range playHistory {
+		if info.Type == "video" {
+			watched[info.VideoID] = struct{}{}
+		}
+	}
+	playHistoryLock.Unlock()
+
+	return watched
+}
+
+// isWatched returns whether the result is a video which is in the watched set.
+func isWatched(watched map[string]struct{}, result lib.SearchResult) bool {
+	if watched == nil || result.Type != "video" {
+		return false
+	}
+
+	_, ok := watched[result.VideoID]
+
+	return ok
+}
@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/darkhz/invidtui/lib"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+var watchLaterTable *tview.Table
+
+// ShowWatchLater shows a popup with the Watch Later list. If logged
+// in, the list is synced with the account playlist when it is shown.
+func ShowWatchLater() {
+	if pg, _ := MPage.GetFrontPage(); pg == "watchlater" {
+		return
+	}
+
+	if lib.IsAuthInstance() {
+		InfoMessage("Syncing Watch Later", true)
+		syncWatchLater()
+	}
+
+	if len(lib.WatchLater()) == 0 {
+		InfoMessage("Watch Later is empty", false)
+		return
+	}
+
+	App.QueueUpdateDraw(func() {
+		watchLaterTable = tview.NewTable()
+		watchLaterTable.SetSelectorWrap(true)
+		watchLaterTable.SetSelectable(true, false)
+		watchLaterTable.SetBackgroundColor(tcell.ColorDefault)
+		watchLaterTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			capturePlayerEvent(event)
+
+			switch event.Key() {
+			case tcell.KeyEscape:
+				exitFocus()
+				Status.SwitchToPage("messages")
+			}
+
+			switch event.Rune() {
+			case 'd':
+				removeWatchLater()
+
+			case 'R':
+				go func() {
+					InfoMessage("Syncing Watch Later", true)
+					if syncWatchLater() {
+						InfoMessage("Watch Later synced", false)
+					}
+
+					App.QueueUpdateDraw(listWatchLater)
+				}()
+			}
+
+			return event
+		})
+
+		title := tview.NewTextView()
+		title.SetDynamicColors(true)
+		title.SetText("[::bu]Watch Later")
+		title.SetTextAlign(tview.AlignCenter)
+		title.SetBackgroundColor(tcell.ColorDefault)
+
+		flex := tview.NewFlex().
+			AddItem(title, 1, 0, false).
+			AddItem(watchLaterTable, 10, 10, true).
+			SetDirection(tview.FlexRow)
+
+		listWatchLater()
+
+		MPage.AddAndSwitchToPage(
+			"watchlater",
+			statusmodal(flex, watchLaterTable),
+			true,
+		).ShowPage("ui")
+
+		App.SetFocus(watchLaterTable)
+	})
+
+	InfoMessage("Loaded Watch Later", false)
+}
+
+// listWatchLater lists the videos in the Watch Later list.
+func listWatchLater() {
+	pos, _ := watchLaterTable.GetSelection()
+
+	watchLaterTable.Clear()
+
+	for row, info := range lib.WatchLater() {
+		watchLaterTable.SetCell(row, 0, tview.NewTableCell("[blue::b]"+tview.Escape(info.Title)).
+			SetExpansion(1).
+			SetReference(info).
+			SetSelectedStyle(mainStyle),
+		)
+
+		watchLaterTable.SetCell(row, 1, tview.NewTableCell("").
+			SetSelectable(false),
+		)
+
+		watchLaterTable.SetCell(row, 2, tview.NewTableCell("[purple::b]"+tview.Escape(info.Author)).
+			SetSelectedStyle(auxStyle),
+		)
+
+		watchLaterTable.SetCell(row, 3, tview.NewTableCell("").
+			SetSelectable(false),
+		)
+
+		watchLaterTable.SetCell(row, 4, tview.NewTableCell("[pink]"+lib.FormatLength(info.LengthSeconds)).
+			SetSelectedStyle(auxStyle),
+		)
+	}
+
+	if rows := watchLaterTable.GetRowCount(); pos >= rows {
+		pos = rows - 1
+	}
+
+	watchLaterTable.Select(pos, 0)
+
+	resizemodal()
+}
+
+// addWatchLater adds the selected videos to the Watch Later list.
+func addWatchLater() {
+	var added int
+
+	entries, err := getSelectedEntries()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	entries = filterEntries(entries, "video")
+	if entries == nil {
+		ErrorMessage(fmt.Errorf("Only videos can be added to Watch Later"))
+		return
+	}
+
+	for _, info := range entries {
+		if lib.AddWatchLater(info) {
+			added++
+		}
+	}
+
+	InfoMessage("Added "+strconv.Itoa(added)+" videos to Watch Later", false)
+
+	if added > 0 && lib.IsAuthInstance() {
+		go syncWatchLater()
+	}
+}
+
+// removeWatchLater removes the selected video from the Watch Later list.
+func removeWatchLater() {
+	row, _ := watchLaterTable.GetSelection()
+
+	info, ok := getCellReference(watchLaterTable, row)
+	if !ok {
+		return
+	}
+
+	lib.RemoveWatchLater(info.VideoID)
+	listWatchLater()
+
+	InfoMessage("Removed "+tview.Escape(info.Title)+" from Watch Later", false)
+
+	if lib.IsAuthInstance() {
+		go syncWatchLater()
+	}
+}
+
+// syncWatchLater syncs the Watch Later list with the account
+// playlist, and returns whether the lists were synced.
+func syncWatchLater() bool {
+	added, removed, err := lib.SyncWatchLater()
+	if err != nil {
+		ErrorMessage(fmt.Errorf("Cannot sync Watch Later: %s", err))
+		return false
+	}
+
+	if added > 0 || removed > 0 {
+		InfoMessage(fmt.Sprintf("Watch Later synced, %d added and %d removed", added, removed), false)
+	}
+
+	return true
+}